@@ -1,9 +1,15 @@
 package main
 
 import (
+	"os"
+
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/http"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		http.RunCommand(os.Args[1], os.Args[2:])
+		return
+	}
 	http.Main()
 }
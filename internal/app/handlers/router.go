@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/realtime"
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/handlers"
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/services"
 	"github.com/gorilla/mux"
@@ -10,7 +11,7 @@ import (
 )
 
 // Routes initializes the routes for the application with the provided services.
-func Routes(agentService *services.AgentServiceImpl, caseService *services.CaseServiceImpl, teamService *services.TeamServiceImpl, userService *services.UserServiceImpl, subscriptionService *services.SubscriptionServiceImpl) http.Handler {
+func Routes(agentService *services.AgentServiceImpl, caseService *services.CaseServiceImpl, teamService *services.TeamServiceImpl, userService *services.UserServiceImpl, subscriptionService *services.SubscriptionServiceImpl, folderService *services.FolderServiceImpl, templateService *services.CaseTemplateServiceImpl, realtimeHub *realtime.Hub) http.Handler {
 	router := mux.NewRouter()
 
 	// Create a new CORS handler with the desired configuration
@@ -31,6 +32,9 @@ func Routes(agentService *services.AgentServiceImpl, caseService *services.CaseS
 	teamHandler := handlers.NewTeamHandler(teamService)
 	userHandler := handlers.NewUserHandler(userService)
 	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionService)
+	folderHandler := handlers.NewFolderHandler(folderService)
+	templateHandler := handlers.NewCaseTemplateHandler(templateService)
+	realtimeHandler := handlers.NewRealtimeHandler(realtimeHub, caseService)
 
 	// Register agent routes
 	registerAgentRoutes(router, agentHandler)
@@ -38,6 +42,15 @@ func Routes(agentService *services.AgentServiceImpl, caseService *services.CaseS
 	// Register case routes
 	registerCaseRoutes(router, caseHandler)
 
+	// Register folder routes
+	registerFolderRoutes(router, folderHandler)
+
+	// Register case template routes
+	registerCaseTemplateRoutes(router, templateHandler)
+
+	// Register realtime routes
+	registerRealtimeRoutes(router, realtimeHandler)
+
 	// Register team routes
 	registerTeamRoutes(router, teamHandler)
 
@@ -64,12 +77,52 @@ func registerAgentRoutes(router *mux.Router, handler *handlers.AgentHandler) {
 func registerCaseRoutes(router *mux.Router, handler *handlers.CaseHandler) {
 	router.HandleFunc("/api-cases/", handler.GetAllCases).Methods(http.MethodGet)
 	router.HandleFunc("/cases-user/", handler.GetCasesByCreatorID).Methods(http.MethodGet)
+	router.HandleFunc("/cases/search", handler.SearchCases).Methods(http.MethodGet)
+	router.HandleFunc("/cases/import", handler.ImportTranscript).Methods(http.MethodPost)
+	router.HandleFunc("/cases/trash", handler.GetTrashedCases).Methods(http.MethodGet)
+	router.HandleFunc("/cases/tags", handler.BulkTagCases).Methods(http.MethodPost)
 	router.HandleFunc("/cases/{id}/", handler.GetCaseByID).Methods(http.MethodGet)
 	router.HandleFunc("/cases-create/", handler.CreateCase).Methods(http.MethodPost)
 	router.HandleFunc("/cases/{id}/", handler.UpdateCase).Methods(http.MethodPatch)
 	router.HandleFunc("/cases/{id}/", handler.DeleteCase).Methods(http.MethodDelete)
 	router.HandleFunc("/case-add-user/{id}/", handler.AddCollaboratorToCase).Methods(http.MethodPost)
 	router.HandleFunc("/case-remove-user/{id}/{userID}/", handler.RemoveCollaboratorFromCase).Methods(http.MethodDelete)
+	router.HandleFunc("/cases/{id}/collaborators/{userID}", handler.UpdateCollaboratorRole).Methods(http.MethodPatch)
+	router.HandleFunc("/cases/{id}/messages", handler.AppendMessages).Methods(http.MethodPost)
+	router.HandleFunc("/cases/{id}/messages", handler.GetCaseMessages).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/messages/{msgID}", handler.EditMessage).Methods(http.MethodPatch)
+	router.HandleFunc("/cases/{id}/messages/{msgID}", handler.DeleteMessage).Methods(http.MethodDelete)
+	router.HandleFunc("/cases/{id}/messages/{msgID}/history", handler.GetMessageHistory).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/messages/{msgID}/thread", handler.GetMessageThread).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/events", handler.StreamCaseEvents).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/export", handler.ExportCase).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/restore", handler.RestoreCase).Methods(http.MethodPost)
+	router.HandleFunc("/cases/{id}/fork", handler.ForkCase).Methods(http.MethodPost)
+	router.HandleFunc("/cases/{id}/forks", handler.GetCaseForks).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/tool-calls", handler.GetToolInvocations).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/versions", handler.GetCaseVersions).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/versions/diff", handler.DiffCaseVersions).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/versions/{version:[0-9]+}", handler.GetCaseVersion).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/versions/{version:[0-9]+}/restore", handler.RestoreCaseVersion).Methods(http.MethodPost)
+	router.HandleFunc("/cases/{id}/share-links", handler.CreateShareLink).Methods(http.MethodPost)
+	router.HandleFunc("/cases/{id}/share-links", handler.GetShareLinks).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/share-links/{linkID}", handler.RevokeShareLink).Methods(http.MethodDelete)
+	router.HandleFunc("/shared/{token}", handler.GetSharedCase).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/invitations", handler.GetCaseInvitations).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/invitations/{invitationID}", handler.RevokeCaseInvitation).Methods(http.MethodDelete)
+	router.HandleFunc("/invitations/{token}/accept", handler.AcceptCaseInvitation).Methods(http.MethodPost)
+	router.HandleFunc("/cases/{id}/activity", handler.GetCaseActivity).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/messages/{msgID}/annotations", handler.CreateAnnotation).Methods(http.MethodPost)
+	router.HandleFunc("/cases/{id}/annotations", handler.GetAnnotations).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/annotations/{annotationID}", handler.UpdateAnnotation).Methods(http.MethodPatch)
+	router.HandleFunc("/cases/{id}/annotations/{annotationID}", handler.DeleteAnnotation).Methods(http.MethodDelete)
+	router.HandleFunc("/users/{id}/bookmarks", handler.GetUserBookmarks).Methods(http.MethodGet)
+	router.HandleFunc("/usage", handler.GetUsage).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/documents", handler.UploadDocument).Methods(http.MethodPost)
+	router.HandleFunc("/cases/{id}/documents", handler.GetCaseDocuments).Methods(http.MethodGet)
+	router.HandleFunc("/cases/{id}/documents/{documentID}", handler.DeleteDocument).Methods(http.MethodDelete)
+	router.HandleFunc("/documents/{id}", handler.DownloadDocument).Methods(http.MethodGet)
+	router.HandleFunc("/documents/{id}/text", handler.GetDocumentText).Methods(http.MethodGet)
 }
 
 func registerTeamRoutes(router *mux.Router, handler *handlers.TeamHandler) {
@@ -96,3 +149,23 @@ func registerSubscriptionRoutes(router *mux.Router, handler *handlers.Subscripti
 	router.HandleFunc("/subscriptions/{id}/", handler.UpdateSubscription).Methods(http.MethodPatch)
 	router.HandleFunc("/subscriptions/{id}/", handler.DeleteSubscription).Methods(http.MethodDelete)
 }
+
+func registerFolderRoutes(router *mux.Router, handler *handlers.FolderHandler) {
+	router.HandleFunc("/folders", handler.GetFolders).Methods(http.MethodGet)
+	router.HandleFunc("/folders", handler.CreateFolder).Methods(http.MethodPost)
+	router.HandleFunc("/folders/{id}", handler.GetFolderByID).Methods(http.MethodGet)
+	router.HandleFunc("/folders/{id}", handler.UpdateFolder).Methods(http.MethodPatch)
+	router.HandleFunc("/folders/{id}", handler.DeleteFolder).Methods(http.MethodDelete)
+}
+
+func registerCaseTemplateRoutes(router *mux.Router, handler *handlers.CaseTemplateHandler) {
+	router.HandleFunc("/templates", handler.GetTemplates).Methods(http.MethodGet)
+	router.HandleFunc("/templates", handler.CreateTemplate).Methods(http.MethodPost)
+	router.HandleFunc("/templates/{id}", handler.GetTemplateByID).Methods(http.MethodGet)
+	router.HandleFunc("/templates/{id}", handler.UpdateTemplate).Methods(http.MethodPatch)
+	router.HandleFunc("/templates/{id}", handler.DeleteTemplate).Methods(http.MethodDelete)
+}
+
+func registerRealtimeRoutes(router *mux.Router, handler *handlers.RealtimeHandler) {
+	router.HandleFunc("/cases/{id}/ws", handler.ConnectCase).Methods(http.MethodGet)
+}
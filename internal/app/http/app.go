@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"time"
 
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/db"
@@ -37,7 +38,10 @@ func NewApplication(cfg Config, logger logs.Logger) (*Application, error) {
 	}
 
 	laDatabase := db.CreateDB(client, cfg.MongoDB.Database, logger)
-	services := db.InitializeServices(laDatabase, logger)
+	services, err := db.InitializeServices(laDatabase, logger)
+	if err != nil {
+		return nil, err
+	}
 
 	return &Application{
 		config:   cfg,
@@ -47,8 +51,9 @@ func NewApplication(cfg Config, logger logs.Logger) (*Application, error) {
 	}, nil
 }
 
-// Run starts the HTTP server for the application.
+// Run starts the background workers and the HTTP server for the application.
 func (app *Application) Run() error {
+	app.services.StartWorkers(context.Background())
 	server := NewHTTPServer(&app.config, app.services, app.logger)
 	return server.ServeHTTP()
 }
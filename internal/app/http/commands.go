@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/db"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/services"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// RunCommand executes a one-off maintenance command, e.g. `server migrate-messages`
+// or `server import-transcript <file> <creator-id> [case name]`.
+func RunCommand(name string, args []string) {
+	logger := logs.Init()
+	defer logger.Sync()
+
+	cfg := loadConfig(logger)
+	client, err := db.Connect(cfg.MongoDB.URI, 60*time.Second, logger)
+	if err != nil {
+		handleErrorAndExit(logger, "Failed to connect to MongoDB", err)
+	}
+	database := db.CreateDB(client, cfg.MongoDB.Database, logger)
+	ctx := context.Background()
+
+	switch name {
+	case "migrate-messages":
+		migrated, err := db.MigrateEmbeddedMessages(ctx, database, logger)
+		if err != nil {
+			handleErrorAndExit(logger, "Message migration failed", err)
+		}
+		logger.Info("Message migration completed", zap.Int("cases", migrated))
+	case "import-transcript":
+		importTranscript(ctx, database, logger, args)
+	default:
+		handleErrorAndExit(logger, "Unknown command", fmt.Errorf("unknown command %q", name))
+	}
+}
+
+// importTranscript creates a case from an Autogen transcript file through the same
+// path as POST /cases/import and logs the per-message report.
+func importTranscript(ctx context.Context, database *mongo.Database, logger logs.Logger, args []string) {
+	if len(args) < 2 {
+		handleErrorAndExit(logger, "Invalid arguments", fmt.Errorf("usage: import-transcript <file> <creator-id> [case name]"))
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		handleErrorAndExit(logger, "Failed to read transcript", err)
+	}
+	creatorID, err := primitive.ObjectIDFromHex(args[1])
+	if err != nil {
+		handleErrorAndExit(logger, "Invalid creator ID", err)
+	}
+	request, err := services.DecodeImportTranscript(data)
+	if err != nil {
+		handleErrorAndExit(logger, "Invalid transcript", err)
+	}
+	if len(args) > 2 {
+		request.Name = helpers.NewNullable(strings.Join(args[2:], " "))
+	}
+
+	// Only the services are needed: the realtime and trash purge workers of a
+	// serving instance are left unstarted.
+	appServices, err := db.InitializeServices(database, logger)
+	if err != nil {
+		handleErrorAndExit(logger, "Failed to initialize services", err)
+	}
+	report, err := appServices.CaseService.ImportTranscript(ctx, creatorID, request)
+	if report != nil {
+		for _, messageErr := range report.Errors {
+			logger.Warn("Skipped transcript message", zap.Int("index", messageErr.Index), zap.String("error", messageErr.Error))
+		}
+	}
+	if err != nil {
+		handleErrorAndExit(logger, "Transcript import failed", err)
+	}
+	logger.Info("Transcript imported",
+		zap.String("case_id", report.Case.ID.Value.Hex()),
+		zap.Int("imported", report.Imported),
+		zap.Int("skipped", report.Skipped),
+	)
+}
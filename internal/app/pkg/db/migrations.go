@@ -0,0 +1,140 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// MigrateEmbeddedMessages moves the messages embedded in case documents into the
+// case_messages collection, one case per transaction. Embedded messages keep their
+// original order and take sequence numbers 0..n-1; any messages already appended to
+// the collection for that case are shifted after them. Cases that no longer embed
+// messages are skipped, so the migration can be re-run safely, also from several
+// processes at once.
+func MigrateEmbeddedMessages(ctx context.Context, database *mongo.Database, logger logs.Logger) (int, error) {
+	cases := database.Collection("cases")
+	messages := database.Collection("case_messages")
+
+	cursor, err := cases.Find(ctx, bson.M{"messages.0": bson.M{"$exists": true}}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		logger.Error("Failed to find cases with embedded messages", err)
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var caseModel models.Case
+		if err := cursor.Decode(&caseModel); err != nil {
+			logger.Error("Failed to decode case", err)
+			return migrated, err
+		}
+
+		var moved int
+		err := database.Client().UseSession(ctx, func(sc mongo.SessionContext) error {
+			_, err := sc.WithTransaction(sc, func(tx mongo.SessionContext) (interface{}, error) {
+				var err error
+				moved, err = migrateCaseMessages(tx, cases, messages, caseModel.ID)
+				return nil, err
+			})
+			return err
+		})
+		if errors.Is(err, errCaseMigrated) {
+			moved, err = 0, nil
+		}
+		if err != nil {
+			logger.Error("Failed to migrate case messages", err, zap.String("case_id", caseModel.ID.Hex()))
+			return migrated, err
+		}
+		if moved == 0 {
+			logger.Info("Case messages already migrated", zap.String("case_id", caseModel.ID.Hex()))
+			continue
+		}
+
+		migrated++
+		logger.Info("Migrated case messages",
+			zap.String("case_id", caseModel.ID.Hex()),
+			zap.Int("messages", moved),
+		)
+	}
+	if err := cursor.Err(); err != nil {
+		logger.Error("Failed to iterate cases", err)
+		return migrated, err
+	}
+	return migrated, nil
+}
+
+// errCaseMigrated aborts the migration of a case whose embedded messages were
+// moved by another process while it was being migrated.
+var errCaseMigrated = errors.New("case messages already migrated")
+
+// migrateCaseMessages splits the embedded messages of a single case out and
+// returns how many were moved. The case is read again inside the transaction, so
+// a retried transaction, or another process migrating the same case, never moves
+// the messages twice.
+func migrateCaseMessages(ctx context.Context, cases, messages *mongo.Collection, caseID primitive.ObjectID) (int, error) {
+	embeddedFilter := bson.M{"_id": caseID, "messages.0": bson.M{"$exists": true}}
+	var caseModel models.Case
+	if err := cases.FindOne(ctx, embeddedFilter).Decode(&caseModel); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	embedded := len(caseModel.Messages)
+
+	// Shift messages appended since the new storage went live, highest first so
+	// the unique (case_id, seq) index never sees two messages on the same number.
+	opts := options.Find().SetSort(bson.D{{Key: "seq", Value: -1}}).SetProjection(bson.M{"_id": 1})
+	existing, err := messages.Find(ctx, bson.M{"case_id": caseModel.ID}, opts)
+	if err != nil {
+		return 0, err
+	}
+	var shifted []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := existing.All(ctx, &shifted); err != nil {
+		return 0, err
+	}
+	for _, message := range shifted {
+		if _, err := messages.UpdateByID(ctx, message.ID, bson.M{"$inc": bson.M{"seq": embedded}}); err != nil {
+			return 0, err
+		}
+	}
+
+	docs := make([]interface{}, embedded)
+	for i, message := range caseModel.Messages {
+		if message.ID.IsZero() {
+			message.ID = primitive.NewObjectID()
+		}
+		if message.CreatedAt.IsZero() {
+			message.CreatedAt = caseModel.CreationDate
+		}
+		message.CaseID = caseModel.ID
+		message.Sequence = i
+		docs[i] = message
+	}
+	if _, err := messages.InsertMany(ctx, docs); err != nil {
+		return 0, err
+	}
+
+	result, err := cases.UpdateOne(ctx, embeddedFilter, bson.M{
+		"$inc":   bson.M{"message_seq": embedded},
+		"$unset": bson.M{"messages": ""},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if result.MatchedCount == 0 {
+		return 0, errCaseMigrated
+	}
+	return embedded, nil
+}
@@ -1,6 +1,17 @@
 package db
 
 import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/events"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/export"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/notify"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/pricing"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/realtime"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/storage"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/utils/env"
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/repositories"
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/services"
@@ -15,19 +26,77 @@ type Services struct {
 	TeamService         *services.TeamServiceImpl
 	UserService         *services.UserServiceImpl
 	SubscriptionService *services.SubscriptionServiceImpl
+	FolderService       *services.FolderServiceImpl
+	TemplateService     *services.CaseTemplateServiceImpl
+	RealtimeHub         *realtime.Hub
 }
 
-func InitializeServices(db *mongo.Database, logger logs.Logger) *Services {
+func InitializeServices(db *mongo.Database, logger logs.Logger) (*Services, error) {
 	// Initialize DAOs
 	agentDAO := daos.NewAgentDAO(db, logger)
 	caseDAO := daos.NewCaseDAO(db, logger)
+	messageDAO := daos.NewMessageDAO(db, logger)
+	caseVersionDAO := daos.NewCaseVersionDAO(db, logger)
+	folderDAO := daos.NewFolderDAO(db, logger)
+	caseTemplateDAO := daos.NewCaseTemplateDAO(db, logger)
+	shareLinkDAO := daos.NewShareLinkDAO(db, logger)
+	caseInvitationDAO := daos.NewCaseInvitationDAO(db, logger)
+	caseActivityDAO := daos.NewCaseActivityDAO(db, logger)
+	annotationDAO := daos.NewAnnotationDAO(db, logger)
+	documentDAO := daos.NewDocumentDAO(db, logger)
+	documentBlobDAO := daos.NewDocumentBlobDAO(db, logger)
 	teamDAO := daos.NewTeamDAO(db, logger)
 	userDAO := daos.NewUserDAO(db, logger)
 	subscriptionDAO := daos.NewSubscriptionsDAO(db, logger)
 
+	// Ensure indexes
+	if err := caseDAO.EnsureIndexes(context.Background()); err != nil {
+		logger.Error("Failed to ensure case indexes", err)
+	}
+	if err := messageDAO.EnsureIndexes(context.Background()); err != nil {
+		logger.Error("Failed to ensure message indexes", err)
+	}
+	if err := caseVersionDAO.EnsureIndexes(context.Background()); err != nil {
+		logger.Error("Failed to ensure case version indexes", err)
+	}
+	if err := folderDAO.EnsureIndexes(context.Background()); err != nil {
+		logger.Error("Failed to ensure folder indexes", err)
+	}
+	if err := caseTemplateDAO.EnsureIndexes(context.Background()); err != nil {
+		logger.Error("Failed to ensure case template indexes", err)
+	}
+	if err := shareLinkDAO.EnsureIndexes(context.Background()); err != nil {
+		logger.Error("Failed to ensure share link indexes", err)
+	}
+	if err := caseInvitationDAO.EnsureIndexes(context.Background()); err != nil {
+		logger.Error("Failed to ensure case invitation indexes", err)
+	}
+	if err := caseActivityDAO.EnsureIndexes(context.Background()); err != nil {
+		logger.Error("Failed to ensure case activity indexes", err)
+	}
+	if err := annotationDAO.EnsureIndexes(context.Background()); err != nil {
+		logger.Error("Failed to ensure annotation indexes", err)
+	}
+	if err := documentDAO.EnsureIndexes(context.Background()); err != nil {
+		logger.Error("Failed to ensure document indexes", err)
+	}
+
 	// Initialize repositories
 	agentRepo := repositories.NewAgentRepository(agentDAO, userDAO, logger)
 	caseRepo := repositories.NewCaseRepository(caseDAO)
+	messageRepo := repositories.NewMessageRepository(messageDAO, caseDAO, logger)
+	caseVersionRepo := repositories.NewCaseVersionRepository(caseVersionDAO, caseDAO, logger)
+	folderRepo := repositories.NewFolderRepository(folderDAO, caseDAO, logger)
+	caseTemplateRepo := repositories.NewCaseTemplateRepository(caseTemplateDAO, teamDAO, logger)
+	shareLinkRepo := repositories.NewShareLinkRepository(shareLinkDAO, caseDAO, logger)
+	caseInvitationRepo := repositories.NewCaseInvitationRepository(caseInvitationDAO, caseDAO, logger)
+	caseActivityRepo := repositories.NewCaseActivityRepository(caseActivityDAO, logger)
+	annotationRepo := repositories.NewAnnotationRepository(annotationDAO, logger)
+	blobStore, err := newBlobStore()
+	if err != nil {
+		return nil, err
+	}
+	documentRepo := repositories.NewDocumentRepository(documentDAO, documentBlobDAO, messageDAO, blobStore, logger)
 	teamRepo := repositories.NewTeamRepository(teamDAO, userDAO, logger)
 	userRepo := repositories.NewUserRepository(userDAO)
 	subscriptionRepo := repositories.NewSubscriptionRepository(subscriptionDAO)
@@ -38,13 +107,39 @@ func InitializeServices(db *mongo.Database, logger logs.Logger) *Services {
 	teamMapper := mappers.NewTeamConversionService(logger)
 	userMapper := mappers.NewUserConversionService(logger)
 	subscriptionMapper := mappers.NewSubscriptionConversionService(logger)
+	folderMapper := mappers.NewFolderConversionService(logger)
+	caseTemplateMapper := mappers.NewCaseTemplateConversionService(caseMapper, logger)
+	shareLinkMapper := mappers.NewShareLinkConversionService(logger)
+
+	// Initialize realtime delivery
+	replayTTL := time.Duration(env.GetInt("EVENT_REPLAY_TTL_MINUTES", 15)) * time.Minute
+	broadcaster := events.NewBroadcaster(env.GetInt("EVENT_REPLAY_BUFFER", 256), replayTTL)
+	fanOut, err := newFanOut(db, logger)
+	if err != nil {
+		return nil, err
+	}
+	realtimeHub := realtime.NewHub(fanOut, broadcaster, logger)
 
 	// Initialize services
 	agentService := services.NewAgentService(agentRepo, agentMapper, userMapper, logger)
-	caseService := services.NewCaseService(caseRepo, caseMapper, userMapper, userRepo, logger)
+	notifier, err := newNotifier(logger)
+	if err != nil {
+		return nil, err
+	}
+	exporter, err := newExportRenderer(logger)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := newPriceTable()
+	if err != nil {
+		return nil, err
+	}
+	caseService := services.NewCaseService(caseRepo, messageRepo, agentRepo, caseVersionRepo, folderRepo, caseTemplateRepo, shareLinkRepo, caseInvitationRepo, caseActivityRepo, annotationRepo, documentRepo, caseMapper, shareLinkMapper, userMapper, userRepo, broadcaster, exporter, notifier, prices, logger)
 	teamService := services.NewTeamService(teamRepo, teamMapper, logger)
-	userService := services.NewUserService(userRepo, userMapper, logger)
+	userService := services.NewUserService(userRepo, userMapper, caseService, logger)
 	subscriptionService := services.NewSubscriptionService(subscriptionRepo, subscriptionMapper, logger)
+	folderService := services.NewFolderService(folderRepo, folderMapper, logger)
+	templateService := services.NewCaseTemplateService(caseTemplateRepo, caseTemplateMapper, logger)
 
 	return &Services{
 		AgentService:        agentService,
@@ -52,5 +147,105 @@ func InitializeServices(db *mongo.Database, logger logs.Logger) *Services {
 		TeamService:         teamService,
 		UserService:         userService,
 		SubscriptionService: subscriptionService,
+		FolderService:       folderService,
+		TemplateService:     templateService,
+		RealtimeHub:         realtimeHub,
+	}, nil
+}
+
+// StartWorkers starts the background work of a serving instance: delivering
+// realtime events and purging cases that have outlived their time in the
+// trash. One-off commands build the services without starting them.
+func (s *Services) StartWorkers(ctx context.Context) {
+	go s.RealtimeHub.Run(ctx)
+
+	trashRetention := time.Duration(env.GetInt("CASE_TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour
+	purgeInterval := time.Duration(env.GetInt("CASE_TRASH_PURGE_INTERVAL_MINUTES", 60)) * time.Minute
+	go s.CaseService.RunTrashPurge(ctx, trashRetention, purgeInterval)
+}
+
+// newExportRenderer loads the case export templates, overridable through
+// EXPORT_TEMPLATE_DIR. A broken override is an error rather than a silent fall
+// back to the built-in templates.
+func newExportRenderer(logger logs.Logger) (*export.Renderer, error) {
+	renderer, err := export.NewRenderer(env.GetString("EXPORT_TEMPLATE_DIR", ""), env.GetString("DOCUMENT_BASE_URL", ""), logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load export templates: %w", err)
+	}
+	return renderer, nil
+}
+
+// newFanOut selects the realtime fan-out backend from REALTIME_FANOUT: "memory"
+// (the default) for a single replica, or "mongo" to share events between
+// replicas. A mongo fan-out that cannot be set up is an error rather than a
+// silent fall back, which would keep events from reaching the other replicas.
+func newFanOut(db *mongo.Database, logger logs.Logger) (realtime.FanOut, error) {
+	switch backend := env.GetString("REALTIME_FANOUT", "memory"); backend {
+	case "mongo":
+		fanOut, err := realtime.NewMongoFanOut(context.Background(), db, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up mongo realtime fan-out: %w", err)
+		}
+		return fanOut, nil
+	case "memory":
+		return realtime.NewMemoryFanOut(), nil
+	default:
+		return nil, fmt.Errorf("unknown REALTIME_FANOUT %q", backend)
 	}
 }
+
+// newNotifier selects how invitations reach people without an account from
+// INVITATION_NOTIFIER: "log" (the default) or "webhook", which posts them to
+// INVITATION_WEBHOOK_URL. Choosing the webhook without a URL is an error, as
+// invitations would otherwise only ever reach the logs.
+func newNotifier(logger logs.Logger) (notify.Notifier, error) {
+	switch notifier := env.GetString("INVITATION_NOTIFIER", "log"); notifier {
+	case "webhook":
+		url := env.GetString("INVITATION_WEBHOOK_URL", "")
+		if url == "" {
+			return nil, fmt.Errorf("INVITATION_NOTIFIER is webhook but INVITATION_WEBHOOK_URL is not set")
+		}
+		timeout := time.Duration(env.GetInt("INVITATION_WEBHOOK_TIMEOUT_SECONDS", 10)) * time.Second
+		return notify.NewWebhookNotifier(url, timeout), nil
+	case "log":
+		return notify.NewLogNotifier(logger), nil
+	default:
+		return nil, fmt.Errorf("unknown INVITATION_NOTIFIER %q", notifier)
+	}
+}
+
+// newBlobStore selects where uploaded documents are kept from DOCUMENT_STORE:
+// "local" (the default) keeps them under DOCUMENT_STORE_DIR, "gcs" in the
+// DOCUMENT_GCS_BUCKET bucket under the optional DOCUMENT_GCS_PREFIX. A store
+// that cannot be set up is an error: falling back to local disk would lose
+// documents on replicas that do not share it.
+func newBlobStore() (storage.BlobStore, error) {
+	switch backend := env.GetString("DOCUMENT_STORE", "local"); backend {
+	case "gcs":
+		bucket := env.GetString("DOCUMENT_GCS_BUCKET", "")
+		if bucket == "" {
+			return nil, fmt.Errorf("DOCUMENT_STORE is gcs but DOCUMENT_GCS_BUCKET is not set")
+		}
+		store, err := storage.NewGCSBlobStore(context.Background(), bucket, env.GetString("DOCUMENT_GCS_PREFIX", ""))
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up GCS document storage: %w", err)
+		}
+		return store, nil
+	case "local":
+		store, err := storage.NewLocalBlobStore(env.GetString("DOCUMENT_STORE_DIR", "data/documents"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up local document storage: %w", err)
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown DOCUMENT_STORE %q", backend)
+	}
+}
+
+// newPriceTable loads the model prices usage is charged at from the JSON file
+// named by USAGE_PRICE_TABLE. Without one, usage is charged at the price of the
+// agent. A table that cannot be loaded is an error, as costs are stored with
+// each message and would stay wrong.
+func newPriceTable() (pricing.Table, error) {
+	return pricing.Load(env.GetString("USAGE_PRICE_TABLE", ""))
+}
@@ -0,0 +1,234 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Event types published for a case.
+const (
+	MessageAppended     = "message.appended"
+	MessageUpdated      = "message.updated"
+	MessageDeleted      = "message.deleted"
+	CaseUpdated         = "case.updated"
+	CaseDeleted         = "case.deleted"
+	CaseRestored        = "case.restored"
+	CollaboratorChanged = "collaborator.changed"
+)
+
+const (
+	defaultReplayBuffer     = 256
+	defaultReplayTTL        = 15 * time.Minute
+	subscriberChannelBuffer = 64
+	firehoseChannelBuffer   = 1024
+)
+
+// Event is a change to a case. IDs increase monotonically across all cases for the
+// lifetime of the process, so they can be used to resume a stream.
+type Event struct {
+	ID         uint64             `json:"id"`
+	CaseID     primitive.ObjectID `json:"case_id"`
+	Type       string             `json:"type"`
+	Data       interface{}        `json:"data"`
+	OccurredAt time.Time          `json:"occurred_at"`
+	// Remote marks events relayed from another server replica.
+	Remote bool `json:"-"`
+}
+
+// Subscription receives the events of one case, or of every case when created
+// through SubscribeAll. The channel is closed when the
+// subscription is closed or when the subscriber falls too far behind; in the latter
+// case the client is expected to reconnect and resume from its last event ID.
+type Subscription struct {
+	caseID      primitive.ObjectID
+	all         bool
+	events      chan Event
+	broadcaster *Broadcaster
+	once        sync.Once
+}
+
+// Events returns the channel delivering the subscription's events.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close unregisters the subscription and closes its channel.
+func (s *Subscription) Close() {
+	s.broadcaster.unsubscribe(s)
+}
+
+// Broadcaster fans case events out to in-process subscribers and keeps the most
+// recent events of each case so reconnecting clients can catch up. The history of
+// a case nobody is subscribed to is dropped once its last event is older than the
+// replay TTL, so cases that have gone quiet, been deleted or purged do not hold on
+// to their events.
+type Broadcaster struct {
+	mu          sync.Mutex
+	lastID      uint64
+	bufferSize  int
+	replayTTL   time.Duration
+	lastSweep   time.Time
+	history     map[primitive.ObjectID][]Event
+	subscribers map[primitive.ObjectID]map[*Subscription]struct{}
+	firehose    map[*Subscription]struct{}
+}
+
+// NewBroadcaster creates a broadcaster keeping up to bufferSize events per case
+// for replay, for replayTTL after a case's last event. Non-positive values use
+// the defaults.
+func NewBroadcaster(bufferSize int, replayTTL time.Duration) *Broadcaster {
+	if bufferSize <= 0 {
+		bufferSize = defaultReplayBuffer
+	}
+	if replayTTL <= 0 {
+		replayTTL = defaultReplayTTL
+	}
+	return &Broadcaster{
+		bufferSize:  bufferSize,
+		replayTTL:   replayTTL,
+		lastSweep:   time.Now(),
+		history:     make(map[primitive.ObjectID][]Event),
+		subscribers: make(map[primitive.ObjectID]map[*Subscription]struct{}),
+		firehose:    make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish records an event for a case and delivers it to the case's subscribers.
+func (b *Broadcaster) Publish(caseID primitive.ObjectID, eventType string, data interface{}) Event {
+	return b.deliver(Event{
+		CaseID:     caseID,
+		Type:       eventType,
+		Data:       data,
+		OccurredAt: time.Now(),
+	})
+}
+
+// Relay delivers an event published on another replica to local subscribers. The
+// event is given a local ID so it can be replayed like any other.
+func (b *Broadcaster) Relay(caseID primitive.ObjectID, eventType string, data interface{}, occurredAt time.Time) Event {
+	return b.deliver(Event{
+		CaseID:     caseID,
+		Type:       eventType,
+		Data:       data,
+		OccurredAt: occurredAt,
+		Remote:     true,
+	})
+}
+
+func (b *Broadcaster) deliver(event Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+	caseID := event.CaseID
+	b.sweep()
+
+	history := append(b.history[caseID], event)
+	if len(history) > b.bufferSize {
+		history = history[len(history)-b.bufferSize:]
+	}
+	b.history[caseID] = history
+
+	for subscription := range b.subscribers[caseID] {
+		b.send(subscription, event)
+	}
+	for subscription := range b.firehose {
+		b.send(subscription, event)
+	}
+	return event
+}
+
+// sweep drops the history of cases without subscribers whose last event is older
+// than the replay TTL. It runs at most once per TTL. Callers must hold b.mu.
+func (b *Broadcaster) sweep() {
+	now := time.Now()
+	if now.Sub(b.lastSweep) < b.replayTTL {
+		return
+	}
+	b.lastSweep = now
+	for caseID, history := range b.history {
+		if len(b.subscribers[caseID]) > 0 {
+			continue
+		}
+		if last := history[len(history)-1]; now.Sub(last.OccurredAt) > b.replayTTL {
+			delete(b.history, caseID)
+		}
+	}
+}
+
+// send hands an event to a subscriber without blocking, dropping subscribers whose
+// buffer is full. Callers must hold b.mu.
+func (b *Broadcaster) send(subscription *Subscription, event Event) {
+	select {
+	case subscription.events <- event:
+	default:
+		b.drop(subscription)
+	}
+}
+
+// Subscribe registers a subscriber for a case and returns, along with it, the
+// buffered events published after lastEventID. Registration and replay happen
+// atomically, so no event is missed or delivered twice.
+func (b *Broadcaster) Subscribe(caseID primitive.ObjectID, lastEventID uint64) (*Subscription, []Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []Event
+	if lastEventID > 0 {
+		for _, event := range b.history[caseID] {
+			if event.ID > lastEventID {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	subscription := &Subscription{
+		caseID:      caseID,
+		events:      make(chan Event, subscriberChannelBuffer),
+		broadcaster: b,
+	}
+	if b.subscribers[caseID] == nil {
+		b.subscribers[caseID] = make(map[*Subscription]struct{})
+	}
+	b.subscribers[caseID][subscription] = struct{}{}
+	return subscription, replay
+}
+
+// SubscribeAll registers a subscriber receiving the events of every case.
+func (b *Broadcaster) SubscribeAll() *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscription := &Subscription{
+		all:         true,
+		events:      make(chan Event, firehoseChannelBuffer),
+		broadcaster: b,
+	}
+	b.firehose[subscription] = struct{}{}
+	return subscription
+}
+
+func (b *Broadcaster) unsubscribe(subscription *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.drop(subscription)
+}
+
+// drop removes a subscription and closes its channel. Callers must hold b.mu.
+func (b *Broadcaster) drop(subscription *Subscription) {
+	subscription.once.Do(func() {
+		defer close(subscription.events)
+		if subscription.all {
+			delete(b.firehose, subscription)
+			return
+		}
+		subscribers := b.subscribers[subscription.caseID]
+		delete(subscribers, subscription)
+		if len(subscribers) == 0 {
+			delete(b.subscribers, subscription.caseID)
+		}
+	})
+}
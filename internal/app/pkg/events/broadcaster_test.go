@@ -0,0 +1,56 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBroadcasterDropsStaleHistory(t *testing.T) {
+	const ttl = 20 * time.Millisecond
+	quiet, watched, active := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+
+	broadcaster := NewBroadcaster(4, ttl)
+	broadcaster.Publish(quiet, CaseUpdated, nil)
+	subscription, _ := broadcaster.Subscribe(watched, 0)
+	defer subscription.Close()
+	broadcaster.Publish(watched, CaseUpdated, nil)
+
+	time.Sleep(2 * ttl)
+	broadcaster.Publish(active, CaseUpdated, nil)
+
+	tests := []struct {
+		name   string
+		caseID primitive.ObjectID
+		kept   bool
+	}{
+		{"case without subscribers gone quiet", quiet, false},
+		{"case with a subscriber", watched, true},
+		{"case published to just now", active, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broadcaster.mu.Lock()
+			_, kept := broadcaster.history[tt.caseID]
+			broadcaster.mu.Unlock()
+			if kept != tt.kept {
+				t.Errorf("history kept = %v, want %v", kept, tt.kept)
+			}
+		})
+	}
+}
+
+func TestBroadcasterReplaysBufferedEvents(t *testing.T) {
+	caseID := primitive.NewObjectID()
+	broadcaster := NewBroadcaster(2, time.Minute)
+	first := broadcaster.Publish(caseID, MessageAppended, nil)
+	broadcaster.Publish(caseID, MessageAppended, nil)
+	last := broadcaster.Publish(caseID, MessageUpdated, nil)
+
+	subscription, replay := broadcaster.Subscribe(caseID, first.ID)
+	defer subscription.Close()
+	if len(replay) != 2 || replay[1].ID != last.ID {
+		t.Fatalf("replay = %+v, want the last 2 events", replay)
+	}
+}
@@ -0,0 +1,207 @@
+package export
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.uber.org/zap"
+)
+
+// Supported export formats.
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+	FormatJSON     = "json"
+)
+
+const (
+	markdownTemplate = "case.md.tmpl"
+	htmlTemplate     = "case.html.tmpl"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// Transcript is the view of a case handed to the export templates.
+type Transcript struct {
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	Agent         *Participant   `json:"agent,omitempty"`
+	Creator       *Participant   `json:"creator,omitempty"`
+	Collaborators []Participant  `json:"collaborators"`
+	Messages      []MessageEntry `json:"messages"`
+	CreationDate  time.Time      `json:"creation_date"`
+	LastEdit      time.Time      `json:"last_edit"`
+	ExportedAt    time.Time      `json:"exported_at"`
+}
+
+// Participant is an agent, the case creator or a collaborator.
+type Participant struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+	Role  string `json:"role,omitempty"`
+	Edit  bool   `json:"edit,omitempty"`
+}
+
+// MessageEntry is a single message of the transcript.
+type MessageEntry struct {
+	ID           string           `json:"id"`
+	Position     int              `json:"position"`
+	ReplyTo      *int             `json:"reply_to,omitempty"` // position of the thread parent, on replies
+	Sender       string           `json:"sender"`
+	Recipient    string           `json:"recipient,omitempty"`
+	Content      string           `json:"content"`
+	FunctionCall bool             `json:"function_call"`
+	ToolCalls    []ToolCallEntry  `json:"tool_calls,omitempty"`
+	ToolResult   *ToolResultEntry `json:"tool_result,omitempty"`
+	DocumentPath string           `json:"document_path,omitempty"`
+	DocumentName string           `json:"document_name,omitempty"`
+	DocumentURL  string           `json:"document_url,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+	Edited       bool             `json:"edited"`
+}
+
+// ToolCallEntry is a tool invocation a message asks for.
+type ToolCallEntry struct {
+	CallID    string          `json:"call_id,omitempty"`
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// ToolResultEntry marks a message as the outcome of the tool invocation with CallID.
+type ToolResultEntry struct {
+	CallID     string `json:"call_id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Status     string `json:"status"`
+	DurationMS *int64 `json:"duration_ms,omitempty"`
+}
+
+// Renderer turns transcripts into downloadable documents. Markdown and HTML come
+// from templates; a deployment can replace either by placing a file with the same
+// name (case.md.tmpl, case.html.tmpl) in its template directory. JSON is encoded
+// directly from the transcript.
+type Renderer struct {
+	markdown        *texttemplate.Template
+	html            *htmltemplate.Template
+	documentBaseURL string
+}
+
+// NewRenderer loads the export templates, preferring those found in templateDir
+// when it is set. documentBaseURL is prefixed to relative document paths.
+func NewRenderer(templateDir, documentBaseURL string, logger logs.Logger) (*Renderer, error) {
+	renderer := &Renderer{documentBaseURL: strings.TrimRight(documentBaseURL, "/")}
+	funcs := map[string]interface{}{"formatTime": formatTime}
+
+	markdownSource, err := loadTemplate(templateDir, markdownTemplate, logger)
+	if err != nil {
+		return nil, err
+	}
+	if renderer.markdown, err = texttemplate.New(markdownTemplate).Funcs(funcs).Parse(markdownSource); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", markdownTemplate, err)
+	}
+
+	htmlSource, err := loadTemplate(templateDir, htmlTemplate, logger)
+	if err != nil {
+		return nil, err
+	}
+	if renderer.html, err = htmltemplate.New(htmlTemplate).Funcs(funcs).Parse(htmlSource); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", htmlTemplate, err)
+	}
+	return renderer, nil
+}
+
+// loadTemplate reads a template from the override directory, falling back to the
+// embedded default when the directory does not provide it.
+func loadTemplate(templateDir, name string, logger logs.Logger) (string, error) {
+	if templateDir != "" {
+		source, err := os.ReadFile(filepath.Join(templateDir, name))
+		if err == nil {
+			logger.Info("Using export template override", zap.String("template", filepath.Join(templateDir, name)))
+			return string(source), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf("read %s: %w", name, err)
+		}
+	}
+	source, err := defaultTemplates.ReadFile(path.Join("templates", name))
+	if err != nil {
+		return "", fmt.Errorf("read embedded %s: %w", name, err)
+	}
+	return string(source), nil
+}
+
+// ContentType returns the MIME type and file extension of a format, and whether
+// the format is supported.
+func ContentType(format string) (string, string, bool) {
+	switch format {
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8", "md", true
+	case FormatHTML:
+		return "text/html; charset=utf-8", "html", true
+	case FormatJSON:
+		return "application/json", "json", true
+	}
+	return "", "", false
+}
+
+// Render produces the transcript in the requested format.
+func (r *Renderer) Render(format string, transcript Transcript) ([]byte, error) {
+	for i := range transcript.Messages {
+		message := &transcript.Messages[i]
+		if message.DocumentPath != "" {
+			if message.DocumentName == "" {
+				message.DocumentName = path.Base(message.DocumentPath)
+			}
+			message.DocumentURL = r.documentURL(message.DocumentPath)
+		}
+	}
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatMarkdown:
+		err = r.markdown.Execute(&buf, transcript)
+	case FormatHTML:
+		err = r.html.Execute(&buf, transcript)
+	case FormatJSON:
+		encoder := json.NewEncoder(&buf)
+		encoder.SetIndent("", " ")
+		err = encoder.Encode(transcript)
+	default:
+		err = fmt.Errorf("unsupported export format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// documentURL links a document path, leaving absolute URLs untouched.
+func (r *Renderer) documentURL(documentPath string) string {
+	if parsed, err := url.Parse(documentPath); err == nil && parsed.IsAbs() {
+		return documentPath
+	}
+	if r.documentBaseURL == "" {
+		return documentPath
+	}
+	return r.documentBaseURL + "/" + strings.TrimLeft(documentPath, "/")
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "—"
+	}
+	return t.UTC().Format("2006-01-02 15:04 MST")
+}
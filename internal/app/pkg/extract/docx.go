@@ -0,0 +1,72 @@
+package extract
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// maxDocxXMLSize bounds how much of the decompressed document body is parsed.
+const maxDocxXMLSize = 256 << 20
+
+// docxText reads the paragraphs of the main document part of a DOCX file.
+// Headers, footers and comments are left out.
+func docxText(r io.ReaderAt, size int64) (string, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return "", err
+	}
+	var body *zip.File
+	for _, file := range archive.File {
+		if file.Name == "word/document.xml" {
+			body = file
+			break
+		}
+	}
+	if body == nil {
+		return "", errors.New("docx: missing word/document.xml")
+	}
+	content, err := body.Open()
+	if err != nil {
+		return "", err
+	}
+	defer content.Close()
+
+	var text strings.Builder
+	inText := false
+	decoder := xml.NewDecoder(io.LimitReader(content, maxDocxXMLSize))
+	for text.Len() <= MaxTextSize {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteByte('\t')
+			case "br", "cr":
+				text.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch element.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(element)
+			}
+		}
+	}
+	return text.String(), nil
+}
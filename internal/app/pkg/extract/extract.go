@@ -0,0 +1,103 @@
+// Package extract pulls the plain text out of uploaded documents so it can be
+// searched and handed to agents without parsing the original file again.
+package extract
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxTextSize is the most text, in bytes, kept from a single document. Longer
+// text is cut at this size.
+const MaxTextSize = 4 << 20
+
+// ErrUnsupported is returned for documents whose format has no text extractor.
+var ErrUnsupported = errors.New("unsupported document format")
+
+const docxContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+type format int
+
+const (
+	formatUnknown format = iota
+	formatText
+	formatDOCX
+	formatPDF
+)
+
+// Supported reports whether text can be extracted from a document with the given
+// content type and file name.
+func Supported(contentType, fileName string) bool {
+	return formatOf(contentType, fileName) != formatUnknown
+}
+
+// Text extracts the text of a document. Plain text, DOCX and PDF files with a
+// text layer are supported; scanned PDFs yield no text.
+func Text(r io.ReaderAt, size int64, contentType, fileName string) (string, error) {
+	var text string
+	var err error
+	switch formatOf(contentType, fileName) {
+	case formatText:
+		text, err = plainText(r, size)
+	case formatDOCX:
+		text, err = docxText(r, size)
+	case formatPDF:
+		text, err = pdfText(r, size)
+	default:
+		return "", ErrUnsupported
+	}
+	if err != nil {
+		return "", err
+	}
+	return normalize(text), nil
+}
+
+// formatOf picks the extractor from the content type, falling back to the file
+// extension for generic or missing types.
+func formatOf(contentType, fileName string) format {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/pdf":
+		return formatPDF
+	case mediaType == docxContentType:
+		return formatDOCX
+	case strings.HasPrefix(mediaType, "text/") && mediaType != "text/html":
+		return formatText
+	}
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".pdf":
+		return formatPDF
+	case ".docx":
+		return formatDOCX
+	case ".txt", ".md", ".csv":
+		return formatText
+	}
+	return formatUnknown
+}
+
+func plainText(r io.ReaderAt, size int64) (string, error) {
+	data, err := io.ReadAll(io.NewSectionReader(r, 0, min(size, MaxTextSize+utf8.UTFMax)))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(string(data), "\uFEFF"), nil
+}
+
+// normalize replaces invalid UTF-8 and NUL bytes, which the database rejects in
+// text indexes, and cuts the text to MaxTextSize on a character boundary.
+func normalize(text string) string {
+	text = strings.ToValidUTF8(text, "\uFFFD")
+	text = strings.ReplaceAll(text, "\x00", "")
+	if len(text) > MaxTextSize {
+		cut := MaxTextSize
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = text[:cut]
+	}
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,193 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// docxFile builds a DOCX archive holding the given parts.
+func docxFile(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range parts {
+		part, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := part.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// pdfFile builds a PDF with one page per entry, each showing its text in
+// Helvetica.
+func pdfFile(pages ...string) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // page tree, filled in below
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+	var kids []string
+	for _, text := range pages {
+		stream := fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream))
+		objects = append(objects, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", len(objects)))
+		kids = append(kids, fmt.Sprintf("%d 0 R", len(objects)))
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+const docxBody = `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>Lease</w:t></w:r><w:r><w:tab/><w:t xml:space="preserve">agreement </w:t></w:r></w:p>
+<w:p><w:r><w:t>Line one</w:t><w:br/><w:t>Line two</w:t></w:r><w:r><w:instrText>PAGE</w:instrText></w:r></w:p>
+</w:body></w:document>`
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		fileName    string
+		want        string
+		wantErr     error
+		anyErr      bool
+	}{
+		{
+			name:        "plain text drops the byte order mark",
+			data:        []byte("\uFEFF  Notes on the case\n"),
+			contentType: "text/plain; charset=utf-8",
+			want:        "Notes on the case",
+		},
+		{
+			name:        "plain text replaces invalid UTF-8 and NUL bytes",
+			data:        []byte("a\x00b\xffc"),
+			contentType: "text/plain",
+			want:        "ab\uFFFDc",
+		},
+		{
+			name:        "format from the extension when the type is generic",
+			data:        []byte("# Heading"),
+			contentType: "application/octet-stream",
+			fileName:    "notes.MD",
+			want:        "# Heading",
+		},
+		{
+			name:        "docx paragraphs, tabs and breaks",
+			data:        docxFile(t, map[string]string{"word/document.xml": docxBody, "word/footer1.xml": "<w:ftr/>"}),
+			contentType: docxContentType,
+			want:        "Lease\tagreement \nLine one\nLine two",
+		},
+		{
+			name:     "docx without a document part",
+			data:     docxFile(t, map[string]string{"word/styles.xml": "<w:styles/>"}),
+			fileName: "brief.docx",
+			anyErr:   true,
+		},
+		{
+			name:     "docx that is not an archive",
+			data:     []byte("not a zip"),
+			fileName: "brief.docx",
+			anyErr:   true,
+		},
+		{
+			name:        "pdf pages in order",
+			data:        pdfFile("First page", "Second page"),
+			contentType: "application/pdf",
+			want:        "First page\n\nSecond page",
+		},
+		{
+			name:     "malformed pdf is an error",
+			data:     []byte("%PDF-1.4\nnot really a pdf"),
+			fileName: "scan.pdf",
+			anyErr:   true,
+		},
+		{
+			name:        "html is unsupported",
+			data:        []byte("<p>hi</p>"),
+			contentType: "text/html",
+			fileName:    "page.html",
+			wantErr:     ErrUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Text(bytes.NewReader(tt.data), int64(len(tt.data)), tt.contentType, tt.fileName)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Text() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.anyErr:
+				if err == nil {
+					t.Fatalf("Text() = %q, want an error", got)
+				}
+			case err != nil:
+				t.Fatalf("Text() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Text() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSupported(t *testing.T) {
+	tests := []struct {
+		contentType string
+		fileName    string
+		want        bool
+	}{
+		{"application/pdf", "", true},
+		{docxContentType, "", true},
+		{"text/csv", "", true},
+		{"text/html", "", false},
+		{"", "report.PDF", true},
+		{"application/octet-stream", "memo.docx", true},
+		{"", "data.csv", true},
+		{"application/msword", "memo.doc", false},
+		{"image/png", "scan.png", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType+" "+tt.fileName, func(t *testing.T) {
+			if got := Supported(tt.contentType, tt.fileName); got != tt.want {
+				t.Errorf("Supported(%q, %q) = %v, want %v", tt.contentType, tt.fileName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeCutsOnCharacterBoundary(t *testing.T) {
+	text := strings.Repeat("a", MaxTextSize-1) + "é"
+	got := normalize(text)
+	if len(got) != MaxTextSize-1 {
+		t.Fatalf("len(normalize()) = %d, want %d", len(got), MaxTextSize-1)
+	}
+}
@@ -0,0 +1,45 @@
+package extract
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// pdfText reads the text layer of a PDF file, one page after another. The PDF
+// reader panics on malformed files, so panics are turned into errors.
+func pdfText(r io.ReaderAt, size int64) (text string, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			text, err = "", fmt.Errorf("pdf: %v", recovered)
+		}
+	}()
+	reader, err := pdf.NewReader(r, size)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	for i := 1; i <= reader.NumPage() && builder.Len() <= MaxTextSize; i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		// Font resource names are local to a page: the same name can stand for a
+		// different font, with a different encoding, on the next page.
+		fonts := make(map[string]*pdf.Font)
+		for _, name := range page.Fonts() {
+			font := page.Font(name)
+			fonts[name] = &font
+		}
+		pageText, err := page.GetPlainText(fonts)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(pageText)
+		builder.WriteString("\n\n")
+	}
+	return builder.String(), nil
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.uber.org/zap"
+)
+
+// CaseInvitation is everything needed to tell someone they were invited to a case.
+type CaseInvitation struct {
+	Email       string    `json:"email"`
+	CaseID      string    `json:"case_id"`
+	CaseName    string    `json:"case_name"`
+	Role        string    `json:"role"`
+	InviterName string    `json:"inviter_name"`
+	Token       string    `json:"token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// Notifier delivers notifications to people outside the application, such as
+// invitees who do not have an account yet.
+type Notifier interface {
+	// NotifyCaseInvitation delivers a case invitation to its recipient.
+	NotifyCaseInvitation(ctx context.Context, invitation CaseInvitation) error
+}
+
+// LogNotifier only logs notifications. It is meant for development; the token is
+// left out of the log, so invitees can only join by signing up with the invited
+// email.
+type LogNotifier struct {
+	logger logs.Logger
+}
+
+// NewLogNotifier creates a notifier that writes to the log.
+func NewLogNotifier(logger logs.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+// NotifyCaseInvitation logs the invitation.
+func (n *LogNotifier) NotifyCaseInvitation(ctx context.Context, invitation CaseInvitation) error {
+	n.logger.Info("Case invitation issued",
+		zap.String("email", invitation.Email),
+		zap.String("case_id", invitation.CaseID),
+		zap.String("role", invitation.Role),
+		zap.Time("expires_at", invitation.ExpiresAt))
+	return nil
+}
+
+// WebhookNotifier posts notifications as JSON to an HTTP endpoint, typically a
+// mail delivery service.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier that posts to the given URL.
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+// webhookPayload wraps a notification with its type so one endpoint can serve
+// several kinds.
+type webhookPayload struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// NotifyCaseInvitation posts the invitation under the case.invitation type.
+func (n *WebhookNotifier) NotifyCaseInvitation(ctx context.Context, invitation CaseInvitation) error {
+	return n.post(ctx, webhookPayload{Type: "case.invitation", Data: invitation})
+}
+
+func (n *WebhookNotifier) post(ctx context.Context, payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("notification webhook returned %s", resp.Status)
+	}
+	return nil
+}
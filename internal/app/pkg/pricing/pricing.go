@@ -0,0 +1,75 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// tokensPerUnit is the number of tokens prices are quoted for.
+const tokensPerUnit = 1_000_000
+
+// Price is what a model charges per million prompt and per million completion
+// tokens.
+type Price struct {
+	Prompt     float64 `json:"prompt"`
+	Completion float64 `json:"completion"`
+}
+
+// Flat charges every token at the same price.
+func Flat(perMillion float64) Price {
+	return Price{Prompt: perMillion, Completion: perMillion}
+}
+
+// Cost is the price of the given numbers of tokens.
+func (p Price) Cost(promptTokens, completionTokens int64) float64 {
+	return (float64(promptTokens)*p.Prompt + float64(completionTokens)*p.Completion) / tokensPerUnit
+}
+
+// Table maps model names to their prices.
+type Table map[string]Price
+
+// Load reads a price table from a JSON file mapping model names to prices, as in
+// {"gpt-4o": {"prompt": 2.5, "completion": 10}}. An empty path gives an empty table.
+func Load(path string) (Table, error) {
+	table := Table{}
+	if path == "" {
+		return table, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price table: %w", err)
+	}
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("failed to parse price table: %w", err)
+	}
+	for model, price := range table {
+		if price.Prompt < 0 || price.Completion < 0 {
+			return nil, fmt.Errorf("price table has a negative price for model %q", model)
+		}
+	}
+	return table, nil
+}
+
+// Lookup finds the price of a model. Models are matched exactly or, failing
+// that, by the longest listed name they start with, so that dated releases such
+// as "gpt-4o-2024-08-06" take the price of "gpt-4o".
+func (t Table) Lookup(model string) (Price, bool) {
+	if model == "" {
+		return Price{}, false
+	}
+	if price, ok := t[model]; ok {
+		return price, true
+	}
+	var match string
+	for name := range t {
+		if len(name) > len(match) && strings.HasPrefix(model, name) {
+			match = name
+		}
+	}
+	if match == "" {
+		return Price{}, false
+	}
+	return t[match], true
+}
@@ -0,0 +1,120 @@
+package pricing
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPriceCost(t *testing.T) {
+	tests := []struct {
+		name             string
+		price            Price
+		promptTokens     int64
+		completionTokens int64
+		want             float64
+	}{
+		{"no tokens", Price{Prompt: 2.5, Completion: 10}, 0, 0, 0},
+		{"prompt and completion priced apart", Price{Prompt: 2.5, Completion: 10}, 1_000_000, 500_000, 7.5},
+		{"small counts", Price{Prompt: 3, Completion: 15}, 1200, 300, 0.0081},
+		{"flat price", Flat(4), 250_000, 250_000, 2},
+		{"free model", Price{}, 1_000_000, 1_000_000, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.price.Cost(tt.promptTokens, tt.completionTokens); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("Cost(%d, %d) = %v, want %v", tt.promptTokens, tt.completionTokens, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableLookup(t *testing.T) {
+	table := Table{
+		"gpt-4o":      {Prompt: 2.5, Completion: 10},
+		"gpt-4o-mini": {Prompt: 0.15, Completion: 0.6},
+		"claude":      {Prompt: 3, Completion: 15},
+	}
+
+	tests := []struct {
+		name   string
+		model  string
+		want   Price
+		wantOK bool
+	}{
+		{"exact name", "gpt-4o", table["gpt-4o"], true},
+		{"dated release takes its model's price", "gpt-4o-2024-08-06", table["gpt-4o"], true},
+		{"longest listed prefix wins", "gpt-4o-mini-2024-07-18", table["gpt-4o-mini"], true},
+		{"unlisted model", "mistral-large", Price{}, false},
+		{"listed name longer than the model", "gpt-4", Price{}, false},
+		{"empty model", "", Price{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := table.Lookup(tt.model)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Lookup(%q) = %+v, %v, want %+v, %v", tt.model, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    Table
+		wantErr bool
+	}{
+		{
+			name:    "prices per model",
+			content: `{"gpt-4o": {"prompt": 2.5, "completion": 10}, "local": {}}`,
+			want:    Table{"gpt-4o": {Prompt: 2.5, Completion: 10}, "local": {}},
+		},
+		{
+			name:    "empty table",
+			content: `{}`,
+			want:    Table{},
+		},
+		{
+			name:    "malformed JSON",
+			content: `{"gpt-4o": `,
+			wantErr: true,
+		},
+		{
+			name:    "negative price",
+			content: `{"gpt-4o": {"prompt": -1, "completion": 10}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "prices.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadWithoutPath(t *testing.T) {
+	table, err := Load("")
+	if err != nil || table == nil || len(table) != 0 {
+		t.Fatalf("Load(\"\") = %v, %v, want an empty table", table, err)
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("Load() of a missing file succeeded")
+	}
+}
@@ -0,0 +1,66 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Envelope is a realtime message exchanged between server replicas.
+type Envelope struct {
+	Origin  string             `json:"origin" bson:"origin"`
+	CaseID  primitive.ObjectID `json:"case_id" bson:"case_id"`
+	Type    string             `json:"type" bson:"type"`
+	UserID  primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Payload json.RawMessage    `json:"payload" bson:"payload"`
+	SentAt  time.Time          `json:"sent_at" bson:"sent_at"`
+}
+
+// FanOut carries envelopes to every replica, including the one publishing them.
+type FanOut interface {
+	// Publish sends an envelope to all listening replicas.
+	Publish(ctx context.Context, envelope Envelope) error
+	// Listen delivers envelopes to the callback until the context is cancelled.
+	Listen(ctx context.Context, deliver func(Envelope)) error
+}
+
+// MemoryFanOut is an in-process FanOut for deployments running a single replica.
+type MemoryFanOut struct {
+	mu        sync.RWMutex
+	listeners map[int]func(Envelope)
+	nextID    int
+}
+
+// NewMemoryFanOut creates a new in-memory fan-out.
+func NewMemoryFanOut() *MemoryFanOut {
+	return &MemoryFanOut{listeners: make(map[int]func(Envelope))}
+}
+
+// Publish delivers the envelope to every listener.
+func (f *MemoryFanOut) Publish(ctx context.Context, envelope Envelope) error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, deliver := range f.listeners {
+		deliver(envelope)
+	}
+	return nil
+}
+
+// Listen registers the callback until the context is cancelled.
+func (f *MemoryFanOut) Listen(ctx context.Context, deliver func(Envelope)) error {
+	f.mu.Lock()
+	id := f.nextID
+	f.nextID++
+	f.listeners[id] = deliver
+	f.mu.Unlock()
+
+	<-ctx.Done()
+
+	f.mu.Lock()
+	delete(f.listeners, id)
+	f.mu.Unlock()
+	return ctx.Err()
+}
@@ -0,0 +1,521 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/events"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+// Realtime message types sent by and to collaborators, next to the case events
+// defined in the events package.
+const (
+	TypePresence         = "presence"
+	TypePresenceSnapshot = "presence.snapshot"
+	TypeTyping           = "typing"
+	TypeRead             = "read"
+	TypeError            = "error"
+
+	// typePresenceHeartbeat carries the users connected to a case on one replica.
+	// It is only exchanged between replicas.
+	typePresenceHeartbeat = "presence.heartbeat"
+)
+
+const (
+	clientSendBuffer   = 64
+	maxInboundPayload  = 64 << 10
+	listenRetryBackoff = time.Second
+
+	// Replicas announce their connected users every presenceHeartbeat; users
+	// last announced more than presenceTTL ago, for example by a replica that
+	// died, are taken offline.
+	presenceHeartbeat = 30 * time.Second
+	presenceTTL       = 3 * presenceHeartbeat
+)
+
+// Frame is a message written to or read from a collaborator's WebSocket.
+type Frame struct {
+	Type   string             `json:"type"`
+	CaseID primitive.ObjectID `json:"case_id"`
+	UserID string             `json:"user_id,omitempty"`
+	Data   json.RawMessage    `json:"data,omitempty"`
+	SentAt time.Time          `json:"sent_at,omitempty"`
+}
+
+type presenceData struct {
+	Status string `json:"status"`
+}
+
+// heartbeatData counts the connections of each user, by hex ID, on a replica.
+type heartbeatData struct {
+	Users map[string]int `json:"users"`
+}
+
+// presenceEntry is what one replica last reported about a user's connections.
+type presenceEntry struct {
+	connections int
+	seen        time.Time
+}
+
+type typingData struct {
+	Typing bool `json:"typing"`
+}
+
+type readData struct {
+	Position *int `json:"position"`
+}
+
+type client struct {
+	caseID primitive.ObjectID
+	userID primitive.ObjectID
+	conn   *websocket.Conn
+	send   chan []byte
+	once   sync.Once
+}
+
+func (c *client) close() {
+	c.once.Do(func() {
+		close(c.send)
+	})
+}
+
+// Hub connects the collaborators of each case over WebSockets. Case events
+// published locally and messages sent by collaborators go through the fan-out, so
+// every replica delivers them to its own connections; events coming from other
+// replicas are also relayed into the local broadcaster for SSE subscribers.
+type Hub struct {
+	node        string
+	fanOut      FanOut
+	broadcaster *events.Broadcaster
+	logger      logs.Logger
+
+	mu    sync.Mutex
+	rooms map[primitive.ObjectID]map[*client]struct{}
+	// presence holds, per case and user, the connections reported by each replica.
+	presence map[primitive.ObjectID]map[primitive.ObjectID]map[string]*presenceEntry
+}
+
+// NewHub creates a hub publishing through the given fan-out.
+func NewHub(fanOut FanOut, broadcaster *events.Broadcaster, logger logs.Logger) *Hub {
+	return &Hub{
+		node:        primitive.NewObjectID().Hex(),
+		fanOut:      fanOut,
+		broadcaster: broadcaster,
+		logger:      logger,
+		rooms:       make(map[primitive.ObjectID]map[*client]struct{}),
+		presence:    make(map[primitive.ObjectID]map[primitive.ObjectID]map[string]*presenceEntry),
+	}
+}
+
+// Run forwards local case events to the fan-out and delivers fan-out envelopes to
+// connected collaborators until the context is cancelled.
+func (h *Hub) Run(ctx context.Context) {
+	subscription := h.broadcaster.SubscribeAll()
+	go func() {
+		defer func() { subscription.Close() }()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-subscription.Events():
+				if !ok {
+					h.logger.Warn("Realtime hub fell behind local events, resubscribing")
+					subscription = h.broadcaster.SubscribeAll()
+					continue
+				}
+				if event.Remote {
+					continue
+				}
+				h.publish(ctx, event.CaseID, primitive.NilObjectID, event.Type, event.Data)
+			}
+		}
+	}()
+	go h.keepPresence(ctx)
+
+	for {
+		if err := h.fanOut.Listen(ctx, h.deliver); ctx.Err() != nil {
+			return
+		} else if err != nil {
+			h.logger.Error("Realtime fan-out listener stopped", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryBackoff):
+		}
+	}
+}
+
+// Serve attaches an authorised collaborator's connection to a case and blocks
+// until the connection closes.
+func (h *Hub) Serve(conn *websocket.Conn, caseID, userID primitive.ObjectID) {
+	conn.MaxPayloadBytes = maxInboundPayload
+	c := &client{
+		caseID: caseID,
+		userID: userID,
+		conn:   conn,
+		send:   make(chan []byte, clientSendBuffer),
+	}
+	ctx := context.Background()
+
+	go h.write(c)
+	snapshot := h.register(c)
+	h.sendFrame(c, Frame{Type: TypePresenceSnapshot, CaseID: caseID, Data: snapshot, SentAt: time.Now()})
+	h.publish(ctx, caseID, userID, TypePresence, presenceData{Status: "online"})
+
+	defer func() {
+		h.unregister(c)
+		h.publish(ctx, caseID, userID, TypePresence, presenceData{Status: "offline"})
+	}()
+
+	for {
+		var frame Frame
+		if err := websocket.JSON.Receive(conn, &frame); err != nil {
+			return
+		}
+		switch frame.Type {
+		case TypeTyping:
+			var data typingData
+			if err := json.Unmarshal(frame.Data, &data); err != nil {
+				h.sendError(c, "Invalid typing indicator")
+				continue
+			}
+			h.publish(ctx, caseID, userID, TypeTyping, data)
+		case TypeRead:
+			var data readData
+			if err := json.Unmarshal(frame.Data, &data); err != nil || data.Position == nil || *data.Position < 0 {
+				h.sendError(c, "Invalid read position")
+				continue
+			}
+			h.publish(ctx, caseID, userID, TypeRead, data)
+		default:
+			h.sendError(c, "Unsupported message type")
+		}
+	}
+}
+
+// publish sends a message to every replica through the fan-out.
+func (h *Hub) publish(ctx context.Context, caseID, userID primitive.ObjectID, messageType string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		h.logger.Error("Failed to encode realtime payload", err)
+		return
+	}
+	envelope := Envelope{
+		Origin:  h.node,
+		CaseID:  caseID,
+		Type:    messageType,
+		UserID:  userID,
+		Payload: payload,
+		SentAt:  time.Now(),
+	}
+	if err := h.fanOut.Publish(ctx, envelope); err != nil {
+		h.logger.Error("Failed to publish realtime message", err, zap.String("type", messageType))
+	}
+}
+
+// deliver hands an envelope from any replica to the local connections of its case.
+func (h *Hub) deliver(envelope Envelope) {
+	if envelope.Origin != h.node && isCaseEvent(envelope.Type) {
+		h.broadcaster.Relay(envelope.CaseID, envelope.Type, envelope.Payload, envelope.SentAt)
+	}
+	if envelope.Type == typePresenceHeartbeat {
+		h.applyHeartbeat(envelope)
+		return
+	}
+	if envelope.Type == TypePresence && !h.trackPresence(envelope) {
+		return
+	}
+
+	outbound := Frame{
+		Type:   envelope.Type,
+		CaseID: envelope.CaseID,
+		Data:   envelope.Payload,
+		SentAt: envelope.SentAt,
+	}
+	if !envelope.UserID.IsZero() {
+		outbound.UserID = envelope.UserID.Hex()
+	}
+	frame, err := json.Marshal(outbound)
+	if err != nil {
+		h.logger.Error("Failed to encode realtime frame", err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.rooms[envelope.CaseID] {
+		// Typing indicators are not echoed back to the collaborator typing.
+		if envelope.Type == TypeTyping && c.userID == envelope.UserID {
+			continue
+		}
+		h.enqueue(c, frame)
+	}
+}
+
+// trackPresence counts a user's open connections on a case per replica and
+// reports whether the user went online or offline as a result.
+func (h *Hub) trackPresence(envelope Envelope) bool {
+	var data presenceData
+	if err := json.Unmarshal(envelope.Payload, &data); err != nil {
+		return false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	wasOnline := h.isOnline(envelope.CaseID, envelope.UserID)
+	switch data.Status {
+	case "online":
+		entry := h.presenceEntry(envelope.CaseID, envelope.UserID, envelope.Origin)
+		entry.connections++
+		entry.seen = time.Now()
+	case "offline":
+		entry := h.presence[envelope.CaseID][envelope.UserID][envelope.Origin]
+		if entry == nil {
+			return false
+		}
+		entry.connections--
+		entry.seen = time.Now()
+		if entry.connections <= 0 {
+			h.forgetPresence(envelope.CaseID, envelope.UserID, envelope.Origin)
+		}
+	default:
+		return false
+	}
+	return wasOnline != h.isOnline(envelope.CaseID, envelope.UserID)
+}
+
+// applyHeartbeat replaces what a replica reported about a case with its latest
+// heartbeat and tells local connections about users going online or offline.
+func (h *Hub) applyHeartbeat(envelope Envelope) {
+	var data heartbeatData
+	if err := json.Unmarshal(envelope.Payload, &data); err != nil {
+		h.logger.Error("Failed to decode presence heartbeat", err)
+		return
+	}
+	connected := make(map[primitive.ObjectID]int, len(data.Users))
+	for hex, connections := range data.Users {
+		if userID, err := primitive.ObjectIDFromHex(hex); err == nil && connections > 0 {
+			connected[userID] = connections
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := time.Now()
+	for userID, replicas := range h.presence[envelope.CaseID] {
+		if _, ok := connected[userID]; ok || replicas[envelope.Origin] == nil {
+			continue
+		}
+		h.forgetPresence(envelope.CaseID, userID, envelope.Origin)
+		if !h.isOnline(envelope.CaseID, userID) {
+			h.announcePresence(envelope.CaseID, userID, "offline")
+		}
+	}
+	for userID, connections := range connected {
+		wasOnline := h.isOnline(envelope.CaseID, userID)
+		entry := h.presenceEntry(envelope.CaseID, userID, envelope.Origin)
+		entry.connections = connections
+		entry.seen = now
+		if !wasOnline {
+			h.announcePresence(envelope.CaseID, userID, "online")
+		}
+	}
+}
+
+// keepPresence announces the local connections to every replica and takes
+// users offline whose replicas stopped announcing them.
+func (h *Hub) keepPresence(ctx context.Context) {
+	ticker := time.NewTicker(presenceHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for caseID, data := range h.localPresence() {
+				h.publish(ctx, caseID, primitive.NilObjectID, typePresenceHeartbeat, data)
+			}
+			h.expirePresence(now)
+		}
+	}
+}
+
+// localPresence counts the connections of each user on this replica by case.
+func (h *Hub) localPresence() map[primitive.ObjectID]heartbeatData {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	local := make(map[primitive.ObjectID]heartbeatData, len(h.rooms))
+	for caseID, room := range h.rooms {
+		data := heartbeatData{Users: make(map[string]int)}
+		for c := range room {
+			data.Users[c.userID.Hex()]++
+		}
+		local[caseID] = data
+	}
+	return local
+}
+
+// expirePresence drops what replicas reported longer than presenceTTL ago.
+func (h *Hub) expirePresence(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for caseID, users := range h.presence {
+		for userID, replicas := range users {
+			expired := false
+			for node, entry := range replicas {
+				if now.Sub(entry.seen) > presenceTTL {
+					h.forgetPresence(caseID, userID, node)
+					expired = true
+				}
+			}
+			if expired && !h.isOnline(caseID, userID) {
+				h.announcePresence(caseID, userID, "offline")
+			}
+		}
+	}
+}
+
+// presenceEntry returns what a replica reported about a user, creating it if
+// needed. Callers must hold h.mu.
+func (h *Hub) presenceEntry(caseID, userID primitive.ObjectID, node string) *presenceEntry {
+	users := h.presence[caseID]
+	if users == nil {
+		users = make(map[primitive.ObjectID]map[string]*presenceEntry)
+		h.presence[caseID] = users
+	}
+	replicas := users[userID]
+	if replicas == nil {
+		replicas = make(map[string]*presenceEntry)
+		users[userID] = replicas
+	}
+	entry := replicas[node]
+	if entry == nil {
+		entry = &presenceEntry{}
+		replicas[node] = entry
+	}
+	return entry
+}
+
+// forgetPresence drops what a replica reported about a user. Callers must hold
+// h.mu.
+func (h *Hub) forgetPresence(caseID, userID primitive.ObjectID, node string) {
+	users := h.presence[caseID]
+	delete(users[userID], node)
+	if len(users[userID]) == 0 {
+		delete(users, userID)
+	}
+	if len(users) == 0 {
+		delete(h.presence, caseID)
+	}
+}
+
+// isOnline reports whether any replica has a connection of the user on the
+// case. Callers must hold h.mu.
+func (h *Hub) isOnline(caseID, userID primitive.ObjectID) bool {
+	return len(h.presence[caseID][userID]) > 0
+}
+
+// announcePresence tells the local connections of a case that a user went
+// online or offline. Callers must hold h.mu.
+func (h *Hub) announcePresence(caseID, userID primitive.ObjectID, status string) {
+	data, _ := json.Marshal(presenceData{Status: status})
+	frame, err := json.Marshal(Frame{Type: TypePresence, CaseID: caseID, UserID: userID.Hex(), Data: data, SentAt: time.Now()})
+	if err != nil {
+		h.logger.Error("Failed to encode realtime frame", err)
+		return
+	}
+	for c := range h.rooms[caseID] {
+		h.enqueue(c, frame)
+	}
+}
+
+// register adds a connection to its case and returns the users currently online.
+func (h *Hub) register(c *client) json.RawMessage {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.rooms[c.caseID] == nil {
+		h.rooms[c.caseID] = make(map[*client]struct{})
+	}
+	h.rooms[c.caseID][c] = struct{}{}
+
+	online := make([]string, 0, len(h.presence[c.caseID]))
+	for userID := range h.presence[c.caseID] {
+		online = append(online, userID.Hex())
+	}
+	sort.Strings(online)
+	snapshot, _ := json.Marshal(map[string]interface{}{"users": online})
+	return snapshot
+}
+
+func (h *Hub) unregister(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.drop(c)
+}
+
+// drop removes a connection from its case. Callers must hold h.mu.
+func (h *Hub) drop(c *client) {
+	room := h.rooms[c.caseID]
+	delete(room, c)
+	if len(room) == 0 {
+		delete(h.rooms, c.caseID)
+	}
+	c.close()
+}
+
+// enqueue queues a frame for a connection, dropping connections that cannot keep
+// up. Callers must hold h.mu.
+func (h *Hub) enqueue(c *client, frame []byte) {
+	select {
+	case c.send <- frame:
+	default:
+		h.logger.Warn("Dropping slow realtime connection", zap.String("case_id", c.caseID.Hex()))
+		h.drop(c)
+	}
+}
+
+func (h *Hub) sendFrame(c *client, frame Frame) {
+	encoded, err := json.Marshal(frame)
+	if err != nil {
+		h.logger.Error("Failed to encode realtime frame", err)
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.rooms[c.caseID][c]; ok {
+		h.enqueue(c, encoded)
+	}
+}
+
+func (h *Hub) sendError(c *client, message string) {
+	data, _ := json.Marshal(map[string]string{"message": message})
+	h.sendFrame(c, Frame{Type: TypeError, CaseID: c.caseID, Data: data, SentAt: time.Now()})
+}
+
+// write sends queued frames to the connection and closes it once the client is
+// dropped, which also ends the read loop in Serve.
+func (h *Hub) write(c *client) {
+	defer c.conn.Close()
+	for frame := range c.send {
+		if err := websocket.Message.Send(c.conn, string(frame)); err != nil {
+			return
+		}
+	}
+}
+
+func isCaseEvent(messageType string) bool {
+	switch messageType {
+	case events.MessageAppended, events.MessageUpdated, events.MessageDeleted,
+		events.CaseUpdated, events.CaseDeleted, events.CaseRestored, events.CollaboratorChanged:
+		return true
+	}
+	return false
+}
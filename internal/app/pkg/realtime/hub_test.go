@@ -0,0 +1,120 @@
+package realtime
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/events"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...zap.Field)        {}
+func (nopLogger) Info(string, ...zap.Field)         {}
+func (nopLogger) Warn(string, ...zap.Field)         {}
+func (nopLogger) Error(string, error, ...zap.Field) {}
+func (nopLogger) Sync()                             {}
+
+func presenceEnvelope(t *testing.T, node string, caseID, userID primitive.ObjectID, status string) Envelope {
+	t.Helper()
+	payload, err := json.Marshal(presenceData{Status: status})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Envelope{Origin: node, CaseID: caseID, UserID: userID, Type: TypePresence, Payload: payload}
+}
+
+func heartbeatEnvelope(t *testing.T, node string, caseID primitive.ObjectID, users map[primitive.ObjectID]int) Envelope {
+	t.Helper()
+	data := heartbeatData{Users: make(map[string]int)}
+	for userID, connections := range users {
+		data.Users[userID.Hex()] = connections
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Envelope{Origin: node, CaseID: caseID, Type: typePresenceHeartbeat, Payload: payload}
+}
+
+func TestHubPresence(t *testing.T) {
+	caseID, alice, bob := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+
+	tests := []struct {
+		name   string
+		apply  func(t *testing.T, h *Hub)
+		online []primitive.ObjectID
+	}{
+		{
+			name: "connections on two replicas",
+			apply: func(t *testing.T, h *Hub) {
+				h.deliver(presenceEnvelope(t, "a", caseID, alice, "online"))
+				h.deliver(presenceEnvelope(t, "b", caseID, alice, "online"))
+				h.deliver(presenceEnvelope(t, "a", caseID, alice, "offline"))
+			},
+			online: []primitive.ObjectID{alice},
+		},
+		{
+			name: "last connection closed",
+			apply: func(t *testing.T, h *Hub) {
+				h.deliver(presenceEnvelope(t, "a", caseID, alice, "online"))
+				h.deliver(presenceEnvelope(t, "a", caseID, alice, "online"))
+				h.deliver(presenceEnvelope(t, "a", caseID, alice, "offline"))
+				h.deliver(presenceEnvelope(t, "a", caseID, alice, "offline"))
+			},
+		},
+		{
+			name: "replica that stopped announcing expires",
+			apply: func(t *testing.T, h *Hub) {
+				h.deliver(presenceEnvelope(t, "dead", caseID, alice, "online"))
+				h.deliver(presenceEnvelope(t, "live", caseID, bob, "online"))
+				h.entry(caseID, alice, "dead").seen = time.Now().Add(-2 * presenceTTL)
+				h.expirePresence(time.Now())
+			},
+			online: []primitive.ObjectID{bob},
+		},
+		{
+			name: "heartbeat replaces what a replica reported",
+			apply: func(t *testing.T, h *Hub) {
+				h.deliver(presenceEnvelope(t, "a", caseID, alice, "online"))
+				h.deliver(heartbeatEnvelope(t, "a", caseID, map[primitive.ObjectID]int{bob: 1}))
+			},
+			online: []primitive.ObjectID{bob},
+		},
+		{
+			name: "heartbeat leaves other replicas alone",
+			apply: func(t *testing.T, h *Hub) {
+				h.deliver(presenceEnvelope(t, "a", caseID, alice, "online"))
+				h.deliver(heartbeatEnvelope(t, "b", caseID, map[primitive.ObjectID]int{}))
+			},
+			online: []primitive.ObjectID{alice},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHub(NewMemoryFanOut(), events.NewBroadcaster(0, 0), nopLogger{})
+			tt.apply(t, h)
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if len(h.presence[caseID]) != len(tt.online) {
+				t.Fatalf("online users = %d, want %d", len(h.presence[caseID]), len(tt.online))
+			}
+			for _, userID := range tt.online {
+				if !h.isOnline(caseID, userID) {
+					t.Errorf("user %s is offline, want online", userID.Hex())
+				}
+			}
+		})
+	}
+}
+
+// entry returns what a replica reported about a user, for tests to age it.
+func (h *Hub) entry(caseID, userID primitive.ObjectID, node string) *presenceEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.presence[caseID][userID][node]
+}
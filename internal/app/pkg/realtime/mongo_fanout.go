@@ -0,0 +1,155 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	fanOutCollection     = "realtime_events"
+	fanOutCollectionSize = 16 << 20
+	fanOutRetryInterval  = time.Second
+	namespaceExistsCode  = 48
+)
+
+// MongoFanOut shares envelopes between replicas through a capped collection that
+// every replica follows with a tailable cursor.
+type MongoFanOut struct {
+	collection *mongo.Collection
+	logger     logs.Logger
+}
+
+// NewMongoFanOut creates the capped collection backing the fan-out if needed.
+func NewMongoFanOut(ctx context.Context, db *mongo.Database, logger logs.Logger) (*MongoFanOut, error) {
+	opts := options.CreateCollection().SetCapped(true).SetSizeInBytes(fanOutCollectionSize)
+	if err := db.CreateCollection(ctx, fanOutCollection, opts); err != nil {
+		var cmdErr mongo.CommandError
+		if !errors.As(err, &cmdErr) || cmdErr.Code != namespaceExistsCode {
+			logger.Error("Failed to create realtime fan-out collection", err)
+			return nil, err
+		}
+	}
+	return &MongoFanOut{
+		collection: db.Collection(fanOutCollection),
+		logger:     logger,
+	}, nil
+}
+
+// Publish stores the envelope for the other replicas to pick up.
+func (f *MongoFanOut) Publish(ctx context.Context, envelope Envelope) error {
+	_, err := f.collection.InsertOne(ctx, envelope)
+	if err != nil {
+		f.logger.Error("Failed to publish realtime envelope", err)
+	}
+	return err
+}
+
+// Listen follows envelopes published from now on. The capped collection keeps
+// envelopes in insertion order, so whenever the server closes the cursor it is
+// reopened from the start of the collection and everything up to the last
+// delivered envelope is skipped. Positions are never compared across replicas'
+// clocks.
+func (f *MongoFanOut) Listen(ctx context.Context, deliver func(Envelope)) error {
+	last, err := f.lastEnvelopeID(ctx)
+	if err != nil {
+		f.logger.Error("Failed to find the end of the realtime fan-out", err)
+		return err
+	}
+	for {
+		opts := options.Find().
+			SetCursorType(options.TailableAwait).
+			SetMaxAwaitTime(fanOutRetryInterval)
+		cursor, err := f.collection.Find(ctx, bson.M{}, opts)
+		if err == nil {
+			last, err = f.follow(ctx, cursor, last, deliver)
+			cursor.Close(context.Background())
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			f.logger.Error("Realtime fan-out cursor failed", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(fanOutRetryInterval):
+		}
+	}
+}
+
+// follow delivers the envelopes of a freshly opened cursor that come after the
+// one with ID last, and returns the ID of the last envelope delivered. Envelopes
+// are held back until last is passed; if the cursor reaches the end without
+// passing it, last has been pushed out of the capped collection and everything
+// held back is newer, so it is delivered.
+func (f *MongoFanOut) follow(ctx context.Context, cursor *mongo.Cursor, last primitive.ObjectID, deliver func(Envelope)) (primitive.ObjectID, error) {
+	caughtUp := last.IsZero()
+	var skipped []bson.Raw
+	for {
+		if !cursor.TryNext(ctx) {
+			err := cursor.Err()
+			if err == nil && !caughtUp {
+				f.logger.Warn("Realtime fan-out position was evicted, delivering the whole collection")
+				caughtUp = true
+				for _, raw := range skipped {
+					last = f.deliverRaw(raw, last, deliver)
+				}
+				skipped = nil
+			}
+			if err != nil || cursor.ID() == 0 {
+				return last, err
+			}
+			continue
+		}
+		raw := slices.Clone(cursor.Current)
+		if caughtUp {
+			last = f.deliverRaw(raw, last, deliver)
+			continue
+		}
+		if id, ok := raw.Lookup("_id").ObjectIDOK(); ok && id == last {
+			caughtUp = true
+			skipped = nil
+			continue
+		}
+		skipped = append(skipped, raw)
+	}
+}
+
+// deliverRaw decodes and delivers a stored envelope, returning its ID as the new
+// position.
+func (f *MongoFanOut) deliverRaw(raw bson.Raw, last primitive.ObjectID, deliver func(Envelope)) primitive.ObjectID {
+	if id, ok := raw.Lookup("_id").ObjectIDOK(); ok {
+		last = id
+	}
+	var envelope Envelope
+	if err := bson.Unmarshal(raw, &envelope); err != nil {
+		f.logger.Error("Failed to decode realtime envelope", err)
+		return last
+	}
+	deliver(envelope)
+	return last
+}
+
+// lastEnvelopeID returns the ID of the most recently stored envelope, or a zero
+// ID when the collection is empty.
+func (f *MongoFanOut) lastEnvelopeID(ctx context.Context) (primitive.ObjectID, error) {
+	var stored struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "$natural", Value: -1}}).SetProjection(bson.M{"_id": 1})
+	err := f.collection.FindOne(ctx, bson.M{}, opts).Decode(&stored)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return primitive.NilObjectID, nil
+	}
+	return stored.ID, err
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	gcs "cloud.google.com/go/storage"
+)
+
+// GCSBlobStore keeps blobs as objects of a Google Cloud Storage bucket, optionally
+// under a key prefix.
+type GCSBlobStore struct {
+	client *gcs.Client
+	bucket string
+	prefix string
+}
+
+// NewGCSBlobStore connects to Cloud Storage with the application default
+// credentials.
+func NewGCSBlobStore(ctx context.Context, bucket, prefix string) (*GCSBlobStore, error) {
+	client, err := gcs.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &GCSBlobStore{client: client, bucket: bucket, prefix: prefix}, nil
+}
+
+// Put uploads the blob as an object. The object only becomes visible once the
+// upload completes; a failed read of r cancels the upload.
+func (s *GCSBlobStore) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	writer := s.object(key).NewWriter(ctx)
+	writer.ContentType = contentType
+	if _, err := io.Copy(writer, r); err != nil {
+		cancel()
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+// Open starts reading the object holding the blob.
+func (s *GCSBlobStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	reader, err := s.object(key).NewReader(ctx)
+	if errors.Is(err, gcs.ErrObjectNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	return reader, err
+}
+
+// Delete removes the object holding the blob.
+func (s *GCSBlobStore) Delete(ctx context.Context, key string) error {
+	err := s.object(key).Delete(ctx)
+	if errors.Is(err, gcs.ErrObjectNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Close releases the Cloud Storage client.
+func (s *GCSBlobStore) Close() error {
+	return s.client.Close()
+}
+
+func (s *GCSBlobStore) object(key string) *gcs.ObjectHandle {
+	return s.client.Bucket(s.bucket).Object(s.prefix + key)
+}
@@ -0,0 +1,22 @@
+// Package storage keeps the binary content of uploaded documents behind the
+// BlobStore interface, so deployments can choose where it lives.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrBlobNotFound is returned when no blob is stored under a key.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore reads and writes blobs addressed by slash-separated keys.
+type BlobStore interface {
+	// Put stores the content read from r under key, replacing any previous blob.
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Open returns a reader over the blob stored under key. The caller closes it.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,185 @@
+package daos
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrAnnotationNotFound is returned when no annotation matches the given ID
+var ErrAnnotationNotFound = errors.New("annotation not found")
+
+// AnnotationDAOInterface defines the interface for the AnnotationDAO
+type AnnotationDAOInterface interface {
+	EnsureIndexes(ctx context.Context) error
+	Create(ctx context.Context, annotation *models.Annotation) error
+	FindByID(ctx context.Context, caseID, id primitive.ObjectID) (models.Annotation, error)
+	FindVisible(ctx context.Context, caseID, userID primitive.ObjectID, filter models.AnnotationFilter) ([]models.Annotation, error)
+	FindByAuthor(ctx context.Context, authorID primitive.ObjectID, caseIDs []primitive.ObjectID, filter models.AnnotationFilter) ([]models.Annotation, error)
+	Update(ctx context.Context, caseID, id primitive.ObjectID, updates bson.M) (models.Annotation, error)
+	Delete(ctx context.Context, caseID, id primitive.ObjectID) error
+	DeleteByCaseID(ctx context.Context, caseID primitive.ObjectID) error
+}
+
+// AnnotationDAO implements the AnnotationDAOInterface
+type AnnotationDAO struct {
+	collection *mongo.Collection
+	logger     logs.Logger
+}
+
+// NewAnnotationDAO creates a new AnnotationDAO
+func NewAnnotationDAO(db *mongo.Database, logger logs.Logger) *AnnotationDAO {
+	return &AnnotationDAO{
+		collection: db.Collection("annotations"),
+		logger:     logger,
+	}
+}
+
+// EnsureIndexes creates the indexes the annotation queries rely on
+func (dao *AnnotationDAO) EnsureIndexes(ctx context.Context) error {
+	dao.logger.Info("DAO Level: Attempting to create annotation indexes")
+	_, err := dao.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "case_id", Value: 1}, {Key: "message_id", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "author_id", Value: 1}, {Key: "_id", Value: -1}}},
+	})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to create annotation indexes", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created annotation indexes")
+	return nil
+}
+
+// Create stores a new annotation
+func (dao *AnnotationDAO) Create(ctx context.Context, annotation *models.Annotation) error {
+	dao.logger.Info("DAO Level: Attempting to create annotation")
+	if annotation.ID.IsZero() {
+		annotation.ID = primitive.NewObjectID()
+	}
+	if _, err := dao.collection.InsertOne(ctx, annotation); err != nil {
+		dao.logger.Error("DAO Level: Failed to create annotation", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created annotation")
+	return nil
+}
+
+// FindByID retrieves an annotation of a case by its ID
+func (dao *AnnotationDAO) FindByID(ctx context.Context, caseID, id primitive.ObjectID) (models.Annotation, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve annotation by ID")
+	var result models.Annotation
+	err := dao.collection.FindOne(ctx, bson.M{"_id": id, "case_id": caseID}).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Warn("Annotation not found")
+			return result, ErrAnnotationNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to retrieve annotation", err)
+		return result, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved annotation")
+	return result, nil
+}
+
+// FindVisible retrieves the annotations of a case a user may see, the shared ones
+// and their own private ones, newest first
+func (dao *AnnotationDAO) FindVisible(ctx context.Context, caseID, userID primitive.ObjectID, filter models.AnnotationFilter) ([]models.Annotation, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve visible annotations")
+	query := bson.M{
+		"case_id": caseID,
+		"$or": bson.A{
+			bson.M{"visibility": models.AnnotationVisibilityShared},
+			bson.M{"author_id": userID},
+		},
+	}
+	return dao.find(ctx, query, filter)
+}
+
+// FindByAuthor retrieves the annotations a user made on the given cases, newest first
+func (dao *AnnotationDAO) FindByAuthor(ctx context.Context, authorID primitive.ObjectID, caseIDs []primitive.ObjectID, filter models.AnnotationFilter) ([]models.Annotation, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve annotations by author")
+	query := bson.M{
+		"author_id": authorID,
+		"case_id":   bson.M{"$in": caseIDs},
+	}
+	return dao.find(ctx, query, filter)
+}
+
+func (dao *AnnotationDAO) find(ctx context.Context, query bson.M, filter models.AnnotationFilter) ([]models.Annotation, error) {
+	if !filter.MessageID.IsZero() {
+		query["message_id"] = filter.MessageID
+	}
+	if !filter.Before.IsZero() {
+		query["_id"] = bson.M{"$lt": filter.Before}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+	cursor, err := dao.collection.Find(ctx, query, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve annotations", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var annotations []models.Annotation
+	if err := cursor.All(ctx, &annotations); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode annotations", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved annotations")
+	return annotations, nil
+}
+
+// Update applies an update document to an annotation of a case and returns the
+// updated annotation
+func (dao *AnnotationDAO) Update(ctx context.Context, caseID, id primitive.ObjectID, updates bson.M) (models.Annotation, error) {
+	dao.logger.Info("DAO Level: Attempting to update annotation")
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var result models.Annotation
+	err := dao.collection.FindOneAndUpdate(ctx, bson.M{"_id": id, "case_id": caseID}, updates, opts).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Warn("Annotation not found")
+			return result, ErrAnnotationNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to update annotation", err)
+		return result, err
+	}
+	dao.logger.Info("DAO Level: Successfully updated annotation")
+	return result, nil
+}
+
+// Delete deletes an annotation of a case by its ID
+func (dao *AnnotationDAO) Delete(ctx context.Context, caseID, id primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to delete annotation")
+	result, err := dao.collection.DeleteOne(ctx, bson.M{"_id": id, "case_id": caseID})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to delete annotation", err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		dao.logger.Warn("Annotation not found")
+		return ErrAnnotationNotFound
+	}
+	dao.logger.Info("DAO Level: Successfully deleted annotation")
+	return nil
+}
+
+// DeleteByCaseID permanently deletes the annotations of a case
+func (dao *AnnotationDAO) DeleteByCaseID(ctx context.Context, caseID primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to delete case annotations")
+	if _, err := dao.collection.DeleteMany(ctx, bson.M{"case_id": caseID}); err != nil {
+		dao.logger.Error("DAO Level: Failed to delete case annotations", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully deleted case annotations")
+	return nil
+}
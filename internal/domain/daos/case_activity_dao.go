@@ -0,0 +1,104 @@
+package daos
+
+import (
+	"context"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CaseActivityDAOInterface defines the interface for the CaseActivityDAO
+type CaseActivityDAOInterface interface {
+	EnsureIndexes(ctx context.Context) error
+	Create(ctx context.Context, activity *models.CaseActivity) error
+	FindByCaseID(ctx context.Context, caseID primitive.ObjectID, filter models.CaseActivityFilter) ([]models.CaseActivity, error)
+	DeleteByCaseID(ctx context.Context, caseID primitive.ObjectID) error
+}
+
+// CaseActivityDAO implements the CaseActivityDAOInterface
+type CaseActivityDAO struct {
+	collection *mongo.Collection
+	logger     logs.Logger
+}
+
+// NewCaseActivityDAO creates a new CaseActivityDAO
+func NewCaseActivityDAO(db *mongo.Database, logger logs.Logger) *CaseActivityDAO {
+	return &CaseActivityDAO{
+		collection: db.Collection("case_activity"),
+		logger:     logger,
+	}
+}
+
+// EnsureIndexes creates the indexes the activity queries rely on
+func (dao *CaseActivityDAO) EnsureIndexes(ctx context.Context) error {
+	dao.logger.Info("DAO Level: Attempting to create case activity indexes")
+	_, err := dao.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "case_id", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "case_id", Value: 1}, {Key: "action", Value: 1}, {Key: "_id", Value: -1}}},
+	})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to create case activity indexes", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created case activity indexes")
+	return nil
+}
+
+// Create stores a new activity entry
+func (dao *CaseActivityDAO) Create(ctx context.Context, activity *models.CaseActivity) error {
+	dao.logger.Info("DAO Level: Attempting to create case activity")
+	if activity.ID.IsZero() {
+		activity.ID = primitive.NewObjectID()
+	}
+	if _, err := dao.collection.InsertOne(ctx, activity); err != nil {
+		dao.logger.Error("DAO Level: Failed to create case activity", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created case activity")
+	return nil
+}
+
+// FindByCaseID retrieves a page of a case's activity, newest first
+func (dao *CaseActivityDAO) FindByCaseID(ctx context.Context, caseID primitive.ObjectID, filter models.CaseActivityFilter) ([]models.CaseActivity, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve case activity")
+	query := bson.M{"case_id": caseID}
+	if len(filter.Actions) > 0 {
+		query["action"] = bson.M{"$in": filter.Actions}
+	}
+	if !filter.Before.IsZero() {
+		query["_id"] = bson.M{"$lt": filter.Before}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+	cursor, err := dao.collection.Find(ctx, query, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve case activity", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var activities []models.CaseActivity
+	if err := cursor.All(ctx, &activities); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode case activity", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved case activity")
+	return activities, nil
+}
+
+// DeleteByCaseID permanently deletes the activity of a case
+func (dao *CaseActivityDAO) DeleteByCaseID(ctx context.Context, caseID primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to delete case activity")
+	if _, err := dao.collection.DeleteMany(ctx, bson.M{"case_id": caseID}); err != nil {
+		dao.logger.Error("DAO Level: Failed to delete case activity", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully deleted case activity")
+	return nil
+}
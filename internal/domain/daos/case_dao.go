@@ -3,24 +3,49 @@ package daos
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// ErrCaseNotFound is returned when no case matches the given ID
+var ErrCaseNotFound = errors.New("case not found")
+
+// ErrCollaboratorNotFound is returned when a user does not collaborate on a case.
+var ErrCollaboratorNotFound = errors.New("collaborator not found")
+
+// ErrCollaboratorExists is returned when a user already collaborates on a case.
+var ErrCollaboratorExists = errors.New("collaborator already exists")
+
 // CaseDAOInterface defines the interface for the CaseDAO
 type CaseDAOInterface interface {
 	FindAll(ctx context.Context) ([]models.Case, error)
 	FindByID(ctx context.Context, id primitive.ObjectID) (models.Case, error)
-	FindByCreatorID(ctx context.Context, creatorID primitive.ObjectID) ([]models.Case, error)
+	FindByCreatorID(ctx context.Context, creatorID primitive.ObjectID, filter models.CaseListFilter) ([]models.Case, error)
 	Create(ctx context.Context, caseRequest *models.Case) (*mongo.InsertOneResult, error)
 	Update(ctx context.Context, id primitive.ObjectID, updates map[string]interface{}) (*mongo.UpdateResult, error)
+	ReserveMessageSequence(ctx context.Context, id primitive.ObjectID, n int, lastEdit time.Time) (int, error)
+	ReserveVersion(ctx context.Context, id primitive.ObjectID) (int, error)
+	EnsureIndexes(ctx context.Context) error
+	FindAccessible(ctx context.Context, userID primitive.ObjectID, agentID primitive.ObjectID) ([]models.Case, error)
+	SearchByName(ctx context.Context, caseIDs []primitive.ObjectID, query string) ([]models.CaseSearchResult, error)
 	Delete(ctx context.Context, id primitive.ObjectID) error
-	AddCollaborator(ctx context.Context, caseID primitive.ObjectID, collaborator map[string]interface{}) (*mongo.UpdateResult, error)
+	Trash(ctx context.Context, id primitive.ObjectID, deletedAt time.Time, deletedBy primitive.ObjectID) error
+	Restore(ctx context.Context, id primitive.ObjectID, lastEdit time.Time) error
+	FindTrashedByID(ctx context.Context, id primitive.ObjectID) (models.Case, error)
+	FindTrashed(ctx context.Context, userID primitive.ObjectID) ([]models.Case, error)
+	FindTrashedBefore(ctx context.Context, cutoff time.Time) ([]models.Case, error)
+	FindForks(ctx context.Context, caseID, userID primitive.ObjectID) ([]models.Case, error)
+	MoveToFolder(ctx context.Context, fromFolderID, toFolderID primitive.ObjectID) error
+	UpdateTags(ctx context.Context, caseIDs []primitive.ObjectID, userID primitive.ObjectID, add, remove []string) ([]primitive.ObjectID, error)
+	AddCollaborator(ctx context.Context, caseID primitive.ObjectID, collaborator models.Collaborators) (*mongo.UpdateResult, error)
 	RemoveCollaborator(ctx context.Context, caseID, collaboratorID primitive.ObjectID) (*mongo.UpdateResult, error)
+	UpdateCollaboratorRole(ctx context.Context, caseID, collaboratorID primitive.ObjectID, role string) error
 }
 
 // CaseDAO implements the CaseDAOInterface
@@ -37,10 +62,30 @@ func NewCaseDAO(db *mongo.Database, logger logs.Logger) *CaseDAO {
 	}
 }
 
+// EnsureIndexes creates the indexes the case queries rely on
+func (dao *CaseDAO) EnsureIndexes(ctx context.Context) error {
+	dao.logger.Info("DAO Level: Attempting to create case indexes")
+	_, err := dao.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "creator_id", Value: 1}}},
+		{Keys: bson.D{{Key: "creator_id", Value: 1}, {Key: "folder_id", Value: 1}}},
+		{Keys: bson.D{{Key: "creator_id", Value: 1}, {Key: "tags", Value: 1}}},
+		{Keys: bson.D{{Key: "collaborators._id", Value: 1}}},
+		{Keys: bson.D{{Key: "name", Value: "text"}}},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "forked_from.case_id", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to create case indexes", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created case indexes")
+	return nil
+}
+
 // FindAll retrieves all cases from the database
 func (dao *CaseDAO) FindAll(ctx context.Context) ([]models.Case, error) {
 	dao.logger.Info("DAO Level: Attempting to retrieve all cases")
-	cursor, err := dao.collection.Find(ctx, bson.M{})
+	cursor, err := dao.collection.Find(ctx, liveCases(bson.M{}))
 	if err != nil {
 		dao.logger.Error("DAO Level: Failed to retrieve cases", err)
 		return nil, err
@@ -61,11 +106,11 @@ func (dao *CaseDAO) FindAll(ctx context.Context) ([]models.Case, error) {
 func (dao *CaseDAO) FindByID(ctx context.Context, id primitive.ObjectID) (models.Case, error) {
 	dao.logger.Info("DAO Level: Attempting to retrieve case by ID")
 	var caseResponse models.Case
-	err := dao.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&caseResponse)
+	err := dao.collection.FindOne(ctx, liveCases(bson.M{"_id": id})).Decode(&caseResponse)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			dao.logger.Warn("Case not found")
-			return models.Case{}, errors.New("case not found")
+			return models.Case{}, ErrCaseNotFound
 		}
 		dao.logger.Error("DAO Level: Failed to retrieve case", err)
 		return models.Case{}, err
@@ -74,10 +119,18 @@ func (dao *CaseDAO) FindByID(ctx context.Context, id primitive.ObjectID) (models
 	return caseResponse, nil
 }
 
-// FindByCreatorID retrieves cases by creator ID from the database
-func (dao *CaseDAO) FindByCreatorID(ctx context.Context, creatorID primitive.ObjectID) ([]models.Case, error) {
+// FindByCreatorID retrieves cases by creator ID from the database, optionally
+// narrowed to a set of folders and to cases carrying all of the given tags
+func (dao *CaseDAO) FindByCreatorID(ctx context.Context, creatorID primitive.ObjectID, filter models.CaseListFilter) ([]models.Case, error) {
 	dao.logger.Info("DAO Level: Attempting to retrieve cases by creator ID")
-	cursor, err := dao.collection.Find(ctx, bson.M{"creator_id": creatorID})
+	query := liveCases(bson.M{"creator_id": creatorID})
+	if len(filter.FolderIDs) > 0 {
+		query["folder_id"] = bson.M{"$in": filter.FolderIDs}
+	}
+	if len(filter.Tags) > 0 {
+		query["tags"] = bson.M{"$all": filter.Tags}
+	}
+	cursor, err := dao.collection.Find(ctx, query)
 	if err != nil {
 		dao.logger.Error("DAO Level: Failed to retrieve cases by creator ID", err)
 		return nil, err
@@ -94,6 +147,57 @@ func (dao *CaseDAO) FindByCreatorID(ctx context.Context, creatorID primitive.Obj
 	return cases, nil
 }
 
+// FindAccessible retrieves the cases a user created or collaborates on, optionally
+// narrowed to one agent. Legacy embedded messages are not loaded.
+func (dao *CaseDAO) FindAccessible(ctx context.Context, userID primitive.ObjectID, agentID primitive.ObjectID) ([]models.Case, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve accessible cases")
+	filter := liveCases(accessibleBy(userID))
+	if !agentID.IsZero() {
+		filter["agent_id"] = agentID
+	}
+	opts := options.Find().SetProjection(bson.M{"messages": 0})
+	cursor, err := dao.collection.Find(ctx, filter, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve accessible cases", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var cases []models.Case
+	if err := cursor.All(ctx, &cases); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode cases", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved accessible cases")
+	return cases, nil
+}
+
+// SearchByName runs a text search over the names of the given cases
+func (dao *CaseDAO) SearchByName(ctx context.Context, caseIDs []primitive.ObjectID, query string) ([]models.CaseSearchResult, error) {
+	dao.logger.Info("DAO Level: Attempting to search cases by name")
+	filter := liveCases(bson.M{
+		"_id":   bson.M{"$in": caseIDs},
+		"$text": bson.M{"$search": query},
+	})
+	opts := options.Find().
+		SetProjection(bson.M{"_id": 1, "score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}})
+	cursor, err := dao.collection.Find(ctx, filter, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to search cases by name", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []models.CaseSearchResult
+	if err := cursor.All(ctx, &results); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode case search results", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully searched cases by name")
+	return results, nil
+}
+
 // Create creates a new case in the database
 func (dao *CaseDAO) Create(ctx context.Context, caseRequest *models.Case) (*mongo.InsertOneResult, error) {
 	dao.logger.Info("DAO Level: Attempting to create new case")
@@ -109,7 +213,7 @@ func (dao *CaseDAO) Create(ctx context.Context, caseRequest *models.Case) (*mong
 // Update updates an existing case in the database
 func (dao *CaseDAO) Update(ctx context.Context, id primitive.ObjectID, updates map[string]interface{}) (*mongo.UpdateResult, error) {
 	dao.logger.Info("DAO Level: Attempting to update case")
-	result, err := dao.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": updates})
+	result, err := dao.collection.UpdateOne(ctx, liveCases(bson.M{"_id": id}), bson.M{"$set": updates})
 	if err != nil {
 		dao.logger.Error("DAO Level: Failed to update case", err)
 		return nil, err
@@ -118,7 +222,57 @@ func (dao *CaseDAO) Update(ctx context.Context, id primitive.ObjectID, updates m
 	return result, nil
 }
 
-// Delete deletes a case by its ID from the database
+// ReserveMessageSequence atomically reserves n message sequence numbers on a case
+// and bumps its last edit time, returning the first reserved number
+func (dao *CaseDAO) ReserveMessageSequence(ctx context.Context, id primitive.ObjectID, n int, lastEdit time.Time) (int, error) {
+	dao.logger.Info("DAO Level: Attempting to reserve message sequence numbers")
+	update := bson.M{
+		"$inc": bson.M{"message_seq": n},
+		"$set": bson.M{"last_edit": lastEdit},
+	}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"message_seq": 1})
+	var result struct {
+		MessageSeq int `bson:"message_seq"`
+	}
+	err := dao.collection.FindOneAndUpdate(ctx, liveCases(bson.M{"_id": id}), update, opts).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Warn("Case not found")
+			return 0, ErrCaseNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to reserve message sequence numbers", err)
+		return 0, err
+	}
+	dao.logger.Info("DAO Level: Successfully reserved message sequence numbers")
+	return result.MessageSeq - n, nil
+}
+
+// ReserveVersion atomically increments the version counter of a case and returns
+// the new version number
+func (dao *CaseDAO) ReserveVersion(ctx context.Context, id primitive.ObjectID) (int, error) {
+	dao.logger.Info("DAO Level: Attempting to reserve case version")
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"version_seq": 1})
+	var result struct {
+		VersionSeq int `bson:"version_seq"`
+	}
+	err := dao.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"version_seq": 1}}, opts).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Warn("Case not found")
+			return 0, ErrCaseNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to reserve case version", err)
+		return 0, err
+	}
+	dao.logger.Info("DAO Level: Successfully reserved case version")
+	return result.VersionSeq, nil
+}
+
+// Delete permanently deletes a case by its ID from the database
 func (dao *CaseDAO) Delete(ctx context.Context, id primitive.ObjectID) error {
 	dao.logger.Info("DAO Level: Attempting to delete case")
 	_, err := dao.collection.DeleteOne(ctx, bson.M{"_id": id})
@@ -131,13 +285,18 @@ func (dao *CaseDAO) Delete(ctx context.Context, id primitive.ObjectID) error {
 }
 
 // AddCollaborator adds a collaborator to a case in the database
-func (dao *CaseDAO) AddCollaborator(ctx context.Context, caseID primitive.ObjectID, collaborator map[string]interface{}) (*mongo.UpdateResult, error) {
+func (dao *CaseDAO) AddCollaborator(ctx context.Context, caseID primitive.ObjectID, collaborator models.Collaborators) (*mongo.UpdateResult, error) {
 	dao.logger.Info("DAO Level: Attempting to add collaborator to case")
-	result, err := dao.collection.UpdateOne(ctx, bson.M{"_id": caseID}, bson.M{"$addToSet": bson.M{"collaborator_ids": collaborator}})
+	filter := liveCases(bson.M{"_id": caseID, "collaborators._id": bson.M{"$ne": collaborator.ID}})
+	result, err := dao.collection.UpdateOne(ctx, filter, bson.M{"$push": bson.M{"collaborators": collaborator}})
 	if err != nil {
 		dao.logger.Error("DAO Level: Failed to add collaborator to case", err)
 		return nil, err
 	}
+	if result.MatchedCount == 0 {
+		dao.logger.Warn("DAO Level: Case not found or user already collaborates on it")
+		return nil, ErrCollaboratorExists
+	}
 	dao.logger.Info("DAO Level: Successfully added collaborator to case")
 	return result, nil
 }
@@ -145,11 +304,261 @@ func (dao *CaseDAO) AddCollaborator(ctx context.Context, caseID primitive.Object
 // RemoveCollaborator removes a collaborator from a case in the database
 func (dao *CaseDAO) RemoveCollaborator(ctx context.Context, caseID, collaboratorID primitive.ObjectID) (*mongo.UpdateResult, error) {
 	dao.logger.Info("DAO Level: Attempting to remove collaborator from case")
-	result, err := dao.collection.UpdateOne(ctx, bson.M{"_id": caseID}, bson.M{"$pull": bson.M{"collaborator_ids": collaboratorID}})
+	filter := liveCases(bson.M{"_id": caseID, "collaborators._id": collaboratorID})
+	result, err := dao.collection.UpdateOne(ctx, filter, bson.M{"$pull": bson.M{"collaborators": bson.M{"_id": collaboratorID}}})
 	if err != nil {
 		dao.logger.Error("DAO Level: Failed to remove collaborator from case", err)
 		return nil, err
 	}
+	if result.MatchedCount == 0 {
+		dao.logger.Warn("DAO Level: Collaborator not found on case")
+		return nil, ErrCollaboratorNotFound
+	}
 	dao.logger.Info("DAO Level: Successfully removed collaborator from case")
 	return result, nil
 }
+
+// UpdateCollaboratorRole changes the role of a collaborator on a case, keeping the
+// legacy edit flag in step with it
+func (dao *CaseDAO) UpdateCollaboratorRole(ctx context.Context, caseID, collaboratorID primitive.ObjectID, role string) error {
+	dao.logger.Info("DAO Level: Attempting to update collaborator role")
+	filter := liveCases(bson.M{"_id": caseID, "collaborators._id": collaboratorID})
+	update := bson.M{"$set": bson.M{
+		"collaborators.$.role": role,
+		"collaborators.$.edit": models.CaseRoleAllows(role, models.CaseRoleEditor),
+	}}
+	result, err := dao.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to update collaborator role", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		dao.logger.Warn("DAO Level: Collaborator not found on case")
+		return ErrCollaboratorNotFound
+	}
+	dao.logger.Info("DAO Level: Successfully updated collaborator role")
+	return nil
+}
+
+// Trash moves a live case to the trash
+func (dao *CaseDAO) Trash(ctx context.Context, id primitive.ObjectID, deletedAt time.Time, deletedBy primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to move case to trash")
+	set := bson.M{"deleted_at": deletedAt}
+	if !deletedBy.IsZero() {
+		set["deleted_by"] = deletedBy
+	}
+	result, err := dao.collection.UpdateOne(ctx, liveCases(bson.M{"_id": id}), bson.M{"$set": set})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to move case to trash", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		dao.logger.Warn("Case not found")
+		return ErrCaseNotFound
+	}
+	dao.logger.Info("DAO Level: Successfully moved case to trash")
+	return nil
+}
+
+// Restore takes a case out of the trash
+func (dao *CaseDAO) Restore(ctx context.Context, id primitive.ObjectID, lastEdit time.Time) error {
+	dao.logger.Info("DAO Level: Attempting to restore case from trash")
+	update := bson.M{
+		"$unset": bson.M{"deleted_at": "", "deleted_by": ""},
+		"$set":   bson.M{"last_edit": lastEdit},
+	}
+	result, err := dao.collection.UpdateOne(ctx, trashedCases(bson.M{"_id": id}), update)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to restore case from trash", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		dao.logger.Warn("Trashed case not found")
+		return ErrCaseNotFound
+	}
+	dao.logger.Info("DAO Level: Successfully restored case from trash")
+	return nil
+}
+
+// FindTrashedByID retrieves a trashed case by its ID
+func (dao *CaseDAO) FindTrashedByID(ctx context.Context, id primitive.ObjectID) (models.Case, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve trashed case by ID")
+	var caseResponse models.Case
+	err := dao.collection.FindOne(ctx, trashedCases(bson.M{"_id": id})).Decode(&caseResponse)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Warn("Trashed case not found")
+			return models.Case{}, ErrCaseNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to retrieve trashed case", err)
+		return models.Case{}, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved trashed case")
+	return caseResponse, nil
+}
+
+// FindTrashed retrieves the trashed cases a user created or collaborates on, most
+// recently deleted first. Legacy embedded messages are not loaded.
+func (dao *CaseDAO) FindTrashed(ctx context.Context, userID primitive.ObjectID) ([]models.Case, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve trashed cases")
+	opts := options.Find().
+		SetProjection(bson.M{"messages": 0}).
+		SetSort(bson.D{{Key: "deleted_at", Value: -1}})
+	cursor, err := dao.collection.Find(ctx, trashedCases(accessibleBy(userID)), opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve trashed cases", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var cases []models.Case
+	if err := cursor.All(ctx, &cases); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode cases", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved trashed cases")
+	return cases, nil
+}
+
+// FindTrashedBefore retrieves the cases trashed before the cutoff. Only the IDs
+// and trash metadata are loaded.
+func (dao *CaseDAO) FindTrashedBefore(ctx context.Context, cutoff time.Time) ([]models.Case, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve expired trashed cases")
+	filter := bson.M{"deleted_at": bson.M{"$lt": cutoff}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1, "creator_id": 1, "deleted_at": 1, "deleted_by": 1})
+	cursor, err := dao.collection.Find(ctx, filter, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve expired trashed cases", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var cases []models.Case
+	if err := cursor.All(ctx, &cases); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode cases", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved expired trashed cases")
+	return cases, nil
+}
+
+// FindForks retrieves the live forks of a case that a user created or collaborates
+// on, newest first. Legacy embedded messages are not loaded.
+func (dao *CaseDAO) FindForks(ctx context.Context, caseID, userID primitive.ObjectID) ([]models.Case, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve case forks")
+	filter := liveCases(accessibleBy(userID))
+	filter["forked_from.case_id"] = caseID
+	opts := options.Find().
+		SetProjection(bson.M{"messages": 0}).
+		SetSort(bson.D{{Key: "creation_date", Value: -1}})
+	cursor, err := dao.collection.Find(ctx, filter, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve case forks", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var cases []models.Case
+	if err := cursor.All(ctx, &cases); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode cases", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved case forks")
+	return cases, nil
+}
+
+// MoveToFolder moves every case of a folder into another one, or out of any folder
+// when toFolderID is zero
+func (dao *CaseDAO) MoveToFolder(ctx context.Context, fromFolderID, toFolderID primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to move cases between folders")
+	update := bson.M{"$unset": bson.M{"folder_id": ""}}
+	if !toFolderID.IsZero() {
+		update = bson.M{"$set": bson.M{"folder_id": toFolderID}}
+	}
+	if _, err := dao.collection.UpdateMany(ctx, bson.M{"folder_id": fromFolderID}, update); err != nil {
+		dao.logger.Error("DAO Level: Failed to move cases between folders", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully moved cases between folders")
+	return nil
+}
+
+// UpdateTags adds and removes tags on the given live cases that a user created or
+// can edit, returning the IDs of the cases it updated
+func (dao *CaseDAO) UpdateTags(ctx context.Context, caseIDs []primitive.ObjectID, userID primitive.ObjectID, add, remove []string) ([]primitive.ObjectID, error) {
+	dao.logger.Info("DAO Level: Attempting to update case tags")
+	filter := liveCases(editableBy(userID))
+	filter["_id"] = bson.M{"$in": caseIDs}
+	// Resolve the editable cases first so the caller learns which ones changed.
+	cursor, err := dao.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve cases to tag", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var cases []models.Case
+	if err := cursor.All(ctx, &cases); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode cases to tag", err)
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, len(cases))
+	for i, caseModel := range cases {
+		ids[i] = caseModel.ID
+	}
+	if len(ids) == 0 {
+		dao.logger.Info("DAO Level: No cases to tag")
+		return ids, nil
+	}
+	filter["_id"] = bson.M{"$in": ids}
+	// A single update cannot both add to and pull from the same array.
+	if len(add) > 0 {
+		if _, err := dao.collection.UpdateMany(ctx, filter, bson.M{"$addToSet": bson.M{"tags": bson.M{"$each": add}}}); err != nil {
+			dao.logger.Error("DAO Level: Failed to add case tags", err)
+			return nil, err
+		}
+	}
+	if len(remove) > 0 {
+		if _, err := dao.collection.UpdateMany(ctx, filter, bson.M{"$pullAll": bson.M{"tags": remove}}); err != nil {
+			dao.logger.Error("DAO Level: Failed to remove case tags", err)
+			return nil, err
+		}
+	}
+	dao.logger.Info("DAO Level: Successfully updated case tags")
+	return ids, nil
+}
+
+// accessibleBy matches the cases a user created or collaborates on.
+func accessibleBy(userID primitive.ObjectID) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"creator_id": userID},
+		bson.M{"collaborators._id": userID},
+	}}
+}
+
+// editableBy matches the cases a user created or collaborates on as an editor or
+// owner. Collaborators stored before roles existed are editors when their edit
+// flag is set.
+func editableBy(userID primitive.ObjectID) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"creator_id": userID},
+		bson.M{"collaborators": bson.M{"$elemMatch": bson.M{
+			"_id": userID,
+			"$or": bson.A{
+				bson.M{"role": bson.M{"$in": bson.A{models.CaseRoleEditor, models.CaseRoleOwner}}},
+				bson.M{"role": bson.M{"$exists": false}, "edit": true},
+			},
+		}}},
+	}}
+}
+
+// liveCases narrows a filter to cases that are not in the trash.
+func liveCases(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+	return filter
+}
+
+// trashedCases narrows a filter to cases that are in the trash.
+func trashedCases(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": true}
+	return filter
+}
@@ -0,0 +1,196 @@
+package daos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrInvitationNotFound is returned when no pending invitation matches a token or ID
+var ErrInvitationNotFound = errors.New("case invitation not found")
+
+// CaseInvitationDAOInterface defines the interface for the CaseInvitationDAO
+type CaseInvitationDAOInterface interface {
+	EnsureIndexes(ctx context.Context) error
+	Create(ctx context.Context, invitation *models.CaseInvitation) error
+	FindByTokenHash(ctx context.Context, tokenHash string) (models.CaseInvitation, error)
+	FindPendingByEmail(ctx context.Context, email string, at time.Time) ([]models.CaseInvitation, error)
+	FindPendingByCaseID(ctx context.Context, caseID primitive.ObjectID, at time.Time) ([]models.CaseInvitation, error)
+	MarkAccepted(ctx context.Context, id, userID primitive.ObjectID, acceptedAt time.Time) error
+	DeletePending(ctx context.Context, caseID primitive.ObjectID, email string) error
+	Delete(ctx context.Context, caseID, id primitive.ObjectID) error
+	DeleteByCaseID(ctx context.Context, caseID primitive.ObjectID) error
+}
+
+// CaseInvitationDAO implements the CaseInvitationDAOInterface
+type CaseInvitationDAO struct {
+	collection *mongo.Collection
+	logger     logs.Logger
+}
+
+// NewCaseInvitationDAO creates a new CaseInvitationDAO
+func NewCaseInvitationDAO(db *mongo.Database, logger logs.Logger) *CaseInvitationDAO {
+	return &CaseInvitationDAO{
+		collection: db.Collection("case_invitations"),
+		logger:     logger,
+	}
+}
+
+// EnsureIndexes creates the indexes the invitation queries rely on
+func (dao *CaseInvitationDAO) EnsureIndexes(ctx context.Context) error {
+	dao.logger.Info("DAO Level: Attempting to create case invitation indexes")
+	_, err := dao.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "email", Value: 1}, {Key: "expires_at", Value: 1}}},
+		{Keys: bson.D{{Key: "case_id", Value: 1}, {Key: "email", Value: 1}}},
+	})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to create case invitation indexes", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created case invitation indexes")
+	return nil
+}
+
+// Create stores a new invitation
+func (dao *CaseInvitationDAO) Create(ctx context.Context, invitation *models.CaseInvitation) error {
+	dao.logger.Info("DAO Level: Attempting to create case invitation")
+	if invitation.ID.IsZero() {
+		invitation.ID = primitive.NewObjectID()
+	}
+	if _, err := dao.collection.InsertOne(ctx, invitation); err != nil {
+		dao.logger.Error("DAO Level: Failed to create case invitation", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created case invitation")
+	return nil
+}
+
+// FindByTokenHash retrieves the invitation issued for a token, accepted or not
+func (dao *CaseInvitationDAO) FindByTokenHash(ctx context.Context, tokenHash string) (models.CaseInvitation, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve case invitation by token")
+	var result models.CaseInvitation
+	err := dao.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Warn("Case invitation not found")
+			return result, ErrInvitationNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to retrieve case invitation by token", err)
+		return result, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved case invitation by token")
+	return result, nil
+}
+
+// FindPendingByEmail retrieves the invitations addressed to an email that can still be accepted
+func (dao *CaseInvitationDAO) FindPendingByEmail(ctx context.Context, email string, at time.Time) ([]models.CaseInvitation, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve pending invitations by email")
+	return dao.findPending(ctx, bson.M{"email": email}, at)
+}
+
+// FindPendingByCaseID retrieves the invitations to a case that can still be accepted, newest first
+func (dao *CaseInvitationDAO) FindPendingByCaseID(ctx context.Context, caseID primitive.ObjectID, at time.Time) ([]models.CaseInvitation, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve pending invitations by case ID")
+	return dao.findPending(ctx, bson.M{"case_id": caseID}, at)
+}
+
+func (dao *CaseInvitationDAO) findPending(ctx context.Context, filter bson.M, at time.Time) ([]models.CaseInvitation, error) {
+	filter["accepted_at"] = bson.M{"$exists": false}
+	filter["expires_at"] = bson.M{"$gt": at}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := dao.collection.Find(ctx, filter, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve pending invitations", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var invitations []models.CaseInvitation
+	if err := cursor.All(ctx, &invitations); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode pending invitations", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved pending invitations")
+	return invitations, nil
+}
+
+// MarkAccepted records that a user accepted an invitation. An invitation can only
+// be accepted once, and not after it expires
+func (dao *CaseInvitationDAO) MarkAccepted(ctx context.Context, id, userID primitive.ObjectID, acceptedAt time.Time) error {
+	dao.logger.Info("DAO Level: Attempting to mark case invitation as accepted")
+	filter := bson.M{"_id": id, "accepted_at": bson.M{"$exists": false}, "expires_at": bson.M{"$gt": acceptedAt}}
+	update := bson.M{"$set": bson.M{"accepted_at": acceptedAt, "accepted_by": userID}}
+	result, err := dao.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to mark case invitation as accepted", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		dao.logger.Warn("Case invitation not found")
+		return ErrInvitationNotFound
+	}
+	dao.logger.Info("DAO Level: Successfully marked case invitation as accepted")
+	return nil
+}
+
+// UnmarkAccepted makes an invitation pending again, provided it is still marked as
+// accepted by the given user at the given time
+func (dao *CaseInvitationDAO) UnmarkAccepted(ctx context.Context, id, userID primitive.ObjectID, acceptedAt time.Time) error {
+	dao.logger.Info("DAO Level: Attempting to unmark case invitation as accepted")
+	filter := bson.M{"_id": id, "accepted_by": userID, "accepted_at": acceptedAt}
+	update := bson.M{"$unset": bson.M{"accepted_at": "", "accepted_by": ""}}
+	if _, err := dao.collection.UpdateOne(ctx, filter, update); err != nil {
+		dao.logger.Error("DAO Level: Failed to unmark case invitation as accepted", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully unmarked case invitation as accepted")
+	return nil
+}
+
+// DeletePending deletes the invitations to a case addressed to an email that have not been accepted
+func (dao *CaseInvitationDAO) DeletePending(ctx context.Context, caseID primitive.ObjectID, email string) error {
+	dao.logger.Info("DAO Level: Attempting to delete pending case invitations")
+	filter := bson.M{"case_id": caseID, "email": email, "accepted_at": bson.M{"$exists": false}}
+	if _, err := dao.collection.DeleteMany(ctx, filter); err != nil {
+		dao.logger.Error("DAO Level: Failed to delete pending case invitations", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully deleted pending case invitations")
+	return nil
+}
+
+// Delete deletes an invitation to a case that has not been accepted
+func (dao *CaseInvitationDAO) Delete(ctx context.Context, caseID, id primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to delete case invitation")
+	filter := bson.M{"_id": id, "case_id": caseID, "accepted_at": bson.M{"$exists": false}}
+	result, err := dao.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to delete case invitation", err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		dao.logger.Warn("Case invitation not found")
+		return ErrInvitationNotFound
+	}
+	dao.logger.Info("DAO Level: Successfully deleted case invitation")
+	return nil
+}
+
+// DeleteByCaseID permanently deletes every invitation to a case
+func (dao *CaseInvitationDAO) DeleteByCaseID(ctx context.Context, caseID primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to delete case invitations")
+	if _, err := dao.collection.DeleteMany(ctx, bson.M{"case_id": caseID}); err != nil {
+		dao.logger.Error("DAO Level: Failed to delete case invitations", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully deleted case invitations")
+	return nil
+}
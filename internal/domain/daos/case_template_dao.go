@@ -0,0 +1,143 @@
+package daos
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrTemplateNotFound is returned when no case template matches the given ID
+var ErrTemplateNotFound = errors.New("case template not found")
+
+// CaseTemplateDAOInterface defines the interface for the CaseTemplateDAO
+type CaseTemplateDAOInterface interface {
+	EnsureIndexes(ctx context.Context) error
+	Create(ctx context.Context, template *models.CaseTemplate) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (models.CaseTemplate, error)
+	FindAvailable(ctx context.Context, userID primitive.ObjectID, teamIDs []primitive.ObjectID) ([]models.CaseTemplate, error)
+	Update(ctx context.Context, id primitive.ObjectID, updates bson.M) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+// CaseTemplateDAO implements the CaseTemplateDAOInterface
+type CaseTemplateDAO struct {
+	collection *mongo.Collection
+	logger     logs.Logger
+}
+
+// NewCaseTemplateDAO creates a new CaseTemplateDAO
+func NewCaseTemplateDAO(db *mongo.Database, logger logs.Logger) *CaseTemplateDAO {
+	return &CaseTemplateDAO{
+		collection: db.Collection("case_templates"),
+		logger:     logger,
+	}
+}
+
+// EnsureIndexes creates the indexes the template queries rely on
+func (dao *CaseTemplateDAO) EnsureIndexes(ctx context.Context) error {
+	dao.logger.Info("DAO Level: Attempting to create case template indexes")
+	_, err := dao.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "creator_id", Value: 1}}},
+		{Keys: bson.D{{Key: "team_id", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to create case template indexes", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created case template indexes")
+	return nil
+}
+
+// Create stores a new case template
+func (dao *CaseTemplateDAO) Create(ctx context.Context, template *models.CaseTemplate) error {
+	dao.logger.Info("DAO Level: Attempting to create case template")
+	if template.ID.IsZero() {
+		template.ID = primitive.NewObjectID()
+	}
+	if _, err := dao.collection.InsertOne(ctx, template); err != nil {
+		dao.logger.Error("DAO Level: Failed to create case template", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created case template")
+	return nil
+}
+
+// FindByID retrieves a case template by its ID
+func (dao *CaseTemplateDAO) FindByID(ctx context.Context, id primitive.ObjectID) (models.CaseTemplate, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve case template by ID")
+	var template models.CaseTemplate
+	err := dao.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&template)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Warn("Case template not found")
+			return template, ErrTemplateNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to retrieve case template", err)
+		return template, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved case template")
+	return template, nil
+}
+
+// FindAvailable retrieves the personal templates of a user together with the
+// templates of the given teams, ordered by name
+func (dao *CaseTemplateDAO) FindAvailable(ctx context.Context, userID primitive.ObjectID, teamIDs []primitive.ObjectID) ([]models.CaseTemplate, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve available case templates")
+	filter := bson.M{"$or": bson.A{
+		bson.M{"creator_id": userID, "team_id": bson.M{"$exists": false}},
+		bson.M{"team_id": bson.M{"$in": teamIDs}},
+	}}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := dao.collection.Find(ctx, filter, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve case templates", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var templates []models.CaseTemplate
+	if err := cursor.All(ctx, &templates); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode case templates", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved available case templates")
+	return templates, nil
+}
+
+// Update applies an update document to a case template
+func (dao *CaseTemplateDAO) Update(ctx context.Context, id primitive.ObjectID, updates bson.M) error {
+	dao.logger.Info("DAO Level: Attempting to update case template")
+	result, err := dao.collection.UpdateOne(ctx, bson.M{"_id": id}, updates)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to update case template", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		dao.logger.Warn("Case template not found")
+		return ErrTemplateNotFound
+	}
+	dao.logger.Info("DAO Level: Successfully updated case template")
+	return nil
+}
+
+// Delete deletes a case template by its ID
+func (dao *CaseTemplateDAO) Delete(ctx context.Context, id primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to delete case template")
+	result, err := dao.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to delete case template", err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		dao.logger.Warn("Case template not found")
+		return ErrTemplateNotFound
+	}
+	dao.logger.Info("DAO Level: Successfully deleted case template")
+	return nil
+}
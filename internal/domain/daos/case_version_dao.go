@@ -0,0 +1,118 @@
+package daos
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrVersionNotFound is returned when a case has no version with the given number
+var ErrVersionNotFound = errors.New("case version not found")
+
+// CaseVersionDAOInterface defines the interface for the CaseVersionDAO
+type CaseVersionDAOInterface interface {
+	EnsureIndexes(ctx context.Context) error
+	Create(ctx context.Context, version *models.CaseVersion) error
+	FindByCaseID(ctx context.Context, caseID primitive.ObjectID) ([]models.CaseVersion, error)
+	FindByVersion(ctx context.Context, caseID primitive.ObjectID, version int) (models.CaseVersion, error)
+	DeleteByCaseID(ctx context.Context, caseID primitive.ObjectID) error
+}
+
+// CaseVersionDAO implements the CaseVersionDAOInterface
+type CaseVersionDAO struct {
+	collection *mongo.Collection
+	logger     logs.Logger
+}
+
+// NewCaseVersionDAO creates a new CaseVersionDAO
+func NewCaseVersionDAO(db *mongo.Database, logger logs.Logger) *CaseVersionDAO {
+	return &CaseVersionDAO{
+		collection: db.Collection("case_versions"),
+		logger:     logger,
+	}
+}
+
+// EnsureIndexes creates the indexes the version queries rely on
+func (dao *CaseVersionDAO) EnsureIndexes(ctx context.Context) error {
+	dao.logger.Info("DAO Level: Attempting to create case version indexes")
+	_, err := dao.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "case_id", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to create case version indexes", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created case version indexes")
+	return nil
+}
+
+// Create stores a new case version
+func (dao *CaseVersionDAO) Create(ctx context.Context, version *models.CaseVersion) error {
+	dao.logger.Info("DAO Level: Attempting to create case version")
+	if version.ID.IsZero() {
+		version.ID = primitive.NewObjectID()
+	}
+	if _, err := dao.collection.InsertOne(ctx, version); err != nil {
+		dao.logger.Error("DAO Level: Failed to create case version", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created case version")
+	return nil
+}
+
+// FindByCaseID retrieves the versions of a case, newest first, without their messages
+func (dao *CaseVersionDAO) FindByCaseID(ctx context.Context, caseID primitive.ObjectID) ([]models.CaseVersion, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve case versions")
+	opts := options.Find().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetProjection(bson.M{"snapshot.messages": 0, "messages": 0})
+	cursor, err := dao.collection.Find(ctx, bson.M{"case_id": caseID}, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve case versions", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var versions []models.CaseVersion
+	if err := cursor.All(ctx, &versions); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode case versions", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved case versions")
+	return versions, nil
+}
+
+// FindByVersion retrieves a single version of a case with its full snapshot
+func (dao *CaseVersionDAO) FindByVersion(ctx context.Context, caseID primitive.ObjectID, version int) (models.CaseVersion, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve case version")
+	var result models.CaseVersion
+	err := dao.collection.FindOne(ctx, bson.M{"case_id": caseID, "version": version}).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Warn("Case version not found")
+			return result, ErrVersionNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to retrieve case version", err)
+		return result, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved case version")
+	return result, nil
+}
+
+// DeleteByCaseID permanently deletes every version of a case
+func (dao *CaseVersionDAO) DeleteByCaseID(ctx context.Context, caseID primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to delete case versions")
+	if _, err := dao.collection.DeleteMany(ctx, bson.M{"case_id": caseID}); err != nil {
+		dao.logger.Error("DAO Level: Failed to delete case versions", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully deleted case versions")
+	return nil
+}
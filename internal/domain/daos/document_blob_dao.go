@@ -0,0 +1,177 @@
+package daos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrDocumentBlobNotFound is returned when no blob is recorded under a digest
+var ErrDocumentBlobNotFound = errors.New("document blob not found")
+
+// ErrDocumentBlobDeleting is returned when the content of a blob is being deleted
+var ErrDocumentBlobDeleting = errors.New("document blob is being deleted")
+
+// DocumentBlobDAOInterface defines the interface for the DocumentBlobDAO
+type DocumentBlobDAOInterface interface {
+	Acquire(ctx context.Context, blob models.DocumentBlob) (bool, error)
+	Revive(ctx context.Context, blob models.DocumentBlob, staleBefore time.Time) (bool, error)
+	MarkStored(ctx context.Context, sha256 string, storedAt time.Time) error
+	Release(ctx context.Context, sha256 string, releasedAt time.Time) (models.DocumentBlob, bool, error)
+	Remove(ctx context.Context, tombstone models.DocumentBlob) error
+}
+
+// DocumentBlobDAO implements the DocumentBlobDAOInterface
+type DocumentBlobDAO struct {
+	collection *mongo.Collection
+	logger     logs.Logger
+}
+
+// NewDocumentBlobDAO creates a new DocumentBlobDAO
+func NewDocumentBlobDAO(db *mongo.Database, logger logs.Logger) *DocumentBlobDAO {
+	return &DocumentBlobDAO{
+		collection: db.Collection("document_blobs"),
+		logger:     logger,
+	}
+}
+
+// Acquire takes a reference on the blob with the given digest, recording the blob
+// as pending if it is new. It reports whether the blob is pending, in which case
+// the caller must store its content: either nobody has yet or another upload of
+// it has not finished. Blobs whose content is being deleted are left alone and
+// ErrDocumentBlobDeleting is returned
+func (dao *DocumentBlobDAO) Acquire(ctx context.Context, blob models.DocumentBlob) (bool, error) {
+	dao.logger.Info("DAO Level: Attempting to acquire document blob")
+	filter := bson.M{"_id": blob.SHA256, "state": bson.M{"$ne": models.DocumentBlobDeleting}}
+	update := bson.M{
+		"$inc": bson.M{"ref_count": 1},
+		"$setOnInsert": bson.M{
+			"storage_key":  blob.StorageKey,
+			"content_type": blob.ContentType,
+			"size":         blob.Size,
+			"state":        models.DocumentBlobPending,
+			"generation":   1,
+			"created_at":   blob.CreatedAt,
+			"updated_at":   blob.CreatedAt,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var result models.DocumentBlob
+	err := dao.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent upload recorded the same blob first; count this reference on
+		// it. Failing again means the record is a tombstone.
+		err = dao.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+		if mongo.IsDuplicateKeyError(err) {
+			dao.logger.Warn("DAO Level: Document blob is being deleted")
+			return false, ErrDocumentBlobDeleting
+		}
+	}
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to acquire document blob", err)
+		return false, err
+	}
+	dao.logger.Info("DAO Level: Successfully acquired document blob")
+	return result.State == models.DocumentBlobPending, nil
+}
+
+// Revive turns the tombstone of a blob whose deletion started before staleBefore
+// back into a pending blob holding a single reference. It reports whether it did,
+// in which case the caller must store the content
+func (dao *DocumentBlobDAO) Revive(ctx context.Context, blob models.DocumentBlob, staleBefore time.Time) (bool, error) {
+	dao.logger.Info("DAO Level: Attempting to revive document blob")
+	filter := bson.M{"_id": blob.SHA256, "state": models.DocumentBlobDeleting, "updated_at": bson.M{"$lt": staleBefore}}
+	update := bson.M{
+		"$set": bson.M{
+			"storage_key":  blob.StorageKey,
+			"content_type": blob.ContentType,
+			"size":         blob.Size,
+			"ref_count":    1,
+			"state":        models.DocumentBlobPending,
+			"updated_at":   blob.CreatedAt,
+		},
+		"$inc": bson.M{"generation": 1},
+	}
+	result, err := dao.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to revive document blob", err)
+		return false, err
+	}
+	dao.logger.Info("DAO Level: Successfully checked document blob tombstone")
+	return result.ModifiedCount == 1, nil
+}
+
+// MarkStored records that the content of a pending blob has been stored
+func (dao *DocumentBlobDAO) MarkStored(ctx context.Context, sha256 string, storedAt time.Time) error {
+	dao.logger.Info("DAO Level: Attempting to mark document blob as stored")
+	filter := bson.M{"_id": sha256, "state": models.DocumentBlobPending}
+	update := bson.M{"$set": bson.M{"state": models.DocumentBlobStored, "updated_at": storedAt}}
+	if _, err := dao.collection.UpdateOne(ctx, filter, update); err != nil {
+		dao.logger.Error("DAO Level: Failed to mark document blob as stored", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully marked document blob as stored")
+	return nil
+}
+
+// Release drops a reference on the blob with the given digest. It reports whether
+// that was the last reference, in which case the blob record is turned into a
+// tombstone, returned, and the caller must delete the content and then remove the
+// tombstone. Uploads of the same content wait while the tombstone is in place
+func (dao *DocumentBlobDAO) Release(ctx context.Context, sha256 string, releasedAt time.Time) (models.DocumentBlob, bool, error) {
+	dao.logger.Info("DAO Level: Attempting to release document blob")
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	filter := bson.M{"_id": sha256, "state": bson.M{"$ne": models.DocumentBlobDeleting}}
+	var result models.DocumentBlob
+	err := dao.collection.FindOneAndUpdate(ctx, filter, bson.M{"$inc": bson.M{"ref_count": -1}}, opts).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Warn("Document blob not found")
+			return models.DocumentBlob{}, false, ErrDocumentBlobNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to release document blob", err)
+		return models.DocumentBlob{}, false, err
+	}
+	if result.RefCount > 0 {
+		dao.logger.Info("DAO Level: Successfully released document blob")
+		return models.DocumentBlob{}, false, nil
+	}
+	// Only turn the record into a tombstone if no upload took a new reference in
+	// the meantime.
+	filter = bson.M{"_id": sha256, "ref_count": bson.M{"$lte": 0}, "state": bson.M{"$ne": models.DocumentBlobDeleting}}
+	update := bson.M{
+		"$set": bson.M{"state": models.DocumentBlobDeleting, "updated_at": releasedAt},
+		"$inc": bson.M{"generation": 1},
+	}
+	var tombstone models.DocumentBlob
+	err = dao.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&tombstone)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Info("DAO Level: Successfully released document blob taken up again")
+			return models.DocumentBlob{}, false, nil
+		}
+		dao.logger.Error("DAO Level: Failed to mark document blob for deletion", err)
+		return models.DocumentBlob{}, false, err
+	}
+	dao.logger.Info("DAO Level: Successfully released document blob")
+	return tombstone, true, nil
+}
+
+// Remove deletes the tombstone of a blob once its content is gone. A tombstone
+// revived in the meantime is kept
+func (dao *DocumentBlobDAO) Remove(ctx context.Context, tombstone models.DocumentBlob) error {
+	dao.logger.Info("DAO Level: Attempting to remove document blob tombstone")
+	filter := bson.M{"_id": tombstone.SHA256, "state": models.DocumentBlobDeleting, "generation": tombstone.Generation}
+	if _, err := dao.collection.DeleteOne(ctx, filter); err != nil {
+		dao.logger.Error("DAO Level: Failed to remove document blob tombstone", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully removed document blob tombstone")
+	return nil
+}
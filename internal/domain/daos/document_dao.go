@@ -0,0 +1,252 @@
+package daos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrDocumentNotFound is returned when no document matches the given ID
+var ErrDocumentNotFound = errors.New("document not found")
+
+// DocumentDAOInterface defines the interface for the DocumentDAO
+type DocumentDAOInterface interface {
+	EnsureIndexes(ctx context.Context) error
+	Create(ctx context.Context, document *models.Document) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (models.Document, error)
+	FindByCaseID(ctx context.Context, caseID primitive.ObjectID, includeDeleted bool) ([]models.Document, error)
+	FindWithText(ctx context.Context, id primitive.ObjectID) (models.Document, error)
+	FindExtractedBySHA256(ctx context.Context, sha256 string) (models.Document, error)
+	SetText(ctx context.Context, id primitive.ObjectID, status, text string) error
+	Search(ctx context.Context, caseIDs []primitive.ObjectID, query string, limit int64) ([]models.DocumentSearchResult, error)
+	SoftDelete(ctx context.Context, caseID, id primitive.ObjectID, deletedAt time.Time) error
+	Delete(ctx context.Context, caseID, id primitive.ObjectID) error
+	DeleteByCaseID(ctx context.Context, caseID primitive.ObjectID) error
+}
+
+// DocumentDAO implements the DocumentDAOInterface
+type DocumentDAO struct {
+	collection *mongo.Collection
+	logger     logs.Logger
+}
+
+// NewDocumentDAO creates a new DocumentDAO
+func NewDocumentDAO(db *mongo.Database, logger logs.Logger) *DocumentDAO {
+	return &DocumentDAO{
+		collection: db.Collection("documents"),
+		logger:     logger,
+	}
+}
+
+// EnsureIndexes creates the indexes the document queries rely on
+func (dao *DocumentDAO) EnsureIndexes(ctx context.Context) error {
+	dao.logger.Info("DAO Level: Attempting to create document indexes")
+	_, err := dao.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "case_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "sha256", Value: 1}, {Key: "text_status", Value: 1}}},
+		{Keys: bson.D{{Key: "text", Value: "text"}}},
+	})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to create document indexes", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created document indexes")
+	return nil
+}
+
+// Create stores the metadata of a new document
+func (dao *DocumentDAO) Create(ctx context.Context, document *models.Document) error {
+	dao.logger.Info("DAO Level: Attempting to create document")
+	if document.ID.IsZero() {
+		document.ID = primitive.NewObjectID()
+	}
+	if _, err := dao.collection.InsertOne(ctx, document); err != nil {
+		dao.logger.Error("DAO Level: Failed to create document", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created document")
+	return nil
+}
+
+// withoutText leaves the extracted text, which can be large, out of metadata queries.
+var withoutText = bson.M{"text": 0}
+
+// FindByID retrieves the metadata of a document by its ID, without its text
+func (dao *DocumentDAO) FindByID(ctx context.Context, id primitive.ObjectID) (models.Document, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve document by ID")
+	var result models.Document
+	opts := options.FindOne().SetProjection(withoutText)
+	err := dao.collection.FindOne(ctx, bson.M{"_id": id}, opts).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Warn("Document not found")
+			return result, ErrDocumentNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to retrieve document", err)
+		return result, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved document")
+	return result, nil
+}
+
+// FindByCaseID retrieves the documents of a case without their text, newest
+// first, optionally including those removed from the case
+func (dao *DocumentDAO) FindByCaseID(ctx context.Context, caseID primitive.ObjectID, includeDeleted bool) ([]models.Document, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve case documents")
+	filter := bson.M{"case_id": caseID}
+	if !includeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+	opts := options.Find().
+		SetProjection(withoutText).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := dao.collection.Find(ctx, filter, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve case documents", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var documents []models.Document
+	if err := cursor.All(ctx, &documents); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode case documents", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved case documents")
+	return documents, nil
+}
+
+// FindWithText retrieves a document by its ID together with its extracted text
+func (dao *DocumentDAO) FindWithText(ctx context.Context, id primitive.ObjectID) (models.Document, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve document text")
+	var result models.Document
+	err := dao.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Warn("Document not found")
+			return result, ErrDocumentNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to retrieve document text", err)
+		return result, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved document text")
+	return result, nil
+}
+
+// FindExtractedBySHA256 retrieves a document with the given content digest whose
+// text has already been extracted
+func (dao *DocumentDAO) FindExtractedBySHA256(ctx context.Context, sha256 string) (models.Document, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve extracted document by digest")
+	var result models.Document
+	filter := bson.M{"sha256": sha256, "text_status": models.DocumentTextExtracted}
+	err := dao.collection.FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return result, ErrDocumentNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to retrieve extracted document by digest", err)
+		return result, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved extracted document by digest")
+	return result, nil
+}
+
+// SetText stores the outcome of the text extraction of a document
+func (dao *DocumentDAO) SetText(ctx context.Context, id primitive.ObjectID, status, text string) error {
+	dao.logger.Info("DAO Level: Attempting to set document text")
+	update := bson.M{"$set": bson.M{"text_status": status, "text": text}}
+	if text == "" {
+		update = bson.M{"$set": bson.M{"text_status": status}, "$unset": bson.M{"text": ""}}
+	}
+	result, err := dao.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to set document text", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		dao.logger.Warn("Document not found")
+		return ErrDocumentNotFound
+	}
+	dao.logger.Info("DAO Level: Successfully set document text")
+	return nil
+}
+
+// Search runs a text search over the extracted text of the live documents of
+// the given cases, most relevant first
+func (dao *DocumentDAO) Search(ctx context.Context, caseIDs []primitive.ObjectID, query string, limit int64) ([]models.DocumentSearchResult, error) {
+	dao.logger.Info("DAO Level: Attempting to search documents")
+	filter := bson.M{
+		"case_id":    bson.M{"$in": caseIDs},
+		"deleted_at": bson.M{"$exists": false},
+		"$text":      bson.M{"$search": query},
+	}
+	opts := options.Find().
+		SetLimit(limit).
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}})
+	cursor, err := dao.collection.Find(ctx, filter, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to search documents", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []models.DocumentSearchResult
+	if err := cursor.All(ctx, &results); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode document search results", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully searched documents")
+	return results, nil
+}
+
+// SoftDelete marks a live document of a case as removed from the case
+func (dao *DocumentDAO) SoftDelete(ctx context.Context, caseID, id primitive.ObjectID, deletedAt time.Time) error {
+	dao.logger.Info("DAO Level: Attempting to soft-delete document")
+	filter := bson.M{"_id": id, "case_id": caseID, "deleted_at": bson.M{"$exists": false}}
+	result, err := dao.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"deleted_at": deletedAt}})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to soft-delete document", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		dao.logger.Warn("Document not found")
+		return ErrDocumentNotFound
+	}
+	dao.logger.Info("DAO Level: Successfully soft-deleted document")
+	return nil
+}
+
+// Delete deletes the metadata of a document of a case
+func (dao *DocumentDAO) Delete(ctx context.Context, caseID, id primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to delete document")
+	result, err := dao.collection.DeleteOne(ctx, bson.M{"_id": id, "case_id": caseID})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to delete document", err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		dao.logger.Warn("Document not found")
+		return ErrDocumentNotFound
+	}
+	dao.logger.Info("DAO Level: Successfully deleted document")
+	return nil
+}
+
+// DeleteByCaseID permanently deletes the document metadata of a case
+func (dao *DocumentDAO) DeleteByCaseID(ctx context.Context, caseID primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to delete case documents")
+	if _, err := dao.collection.DeleteMany(ctx, bson.M{"case_id": caseID}); err != nil {
+		dao.logger.Error("DAO Level: Failed to delete case documents", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully deleted case documents")
+	return nil
+}
@@ -0,0 +1,206 @@
+package daos
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrFolderNotFound is returned when no folder matches the given ID
+var ErrFolderNotFound = errors.New("folder not found")
+
+// FolderDAOInterface defines the interface for the FolderDAO
+type FolderDAOInterface interface {
+	EnsureIndexes(ctx context.Context) error
+	Create(ctx context.Context, folder *models.Folder) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (models.Folder, error)
+	FindByOwnerID(ctx context.Context, ownerID primitive.ObjectID) ([]models.Folder, error)
+	FindDescendantIDs(ctx context.Context, id primitive.ObjectID) ([]primitive.ObjectID, error)
+	Update(ctx context.Context, id primitive.ObjectID, updates bson.M) error
+	ReplaceAncestors(ctx context.Context, id primitive.ObjectID, oldAncestors, newAncestors []primitive.ObjectID) error
+	Reparent(ctx context.Context, fromParentID, toParentID primitive.ObjectID) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+// FolderDAO implements the FolderDAOInterface
+type FolderDAO struct {
+	collection *mongo.Collection
+	logger     logs.Logger
+}
+
+// NewFolderDAO creates a new FolderDAO
+func NewFolderDAO(db *mongo.Database, logger logs.Logger) *FolderDAO {
+	return &FolderDAO{
+		collection: db.Collection("folders"),
+		logger:     logger,
+	}
+}
+
+// EnsureIndexes creates the indexes the folder queries rely on
+func (dao *FolderDAO) EnsureIndexes(ctx context.Context) error {
+	dao.logger.Info("DAO Level: Attempting to create folder indexes")
+	_, err := dao.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "parent_id", Value: 1}}},
+		{Keys: bson.D{{Key: "ancestors", Value: 1}}},
+	})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to create folder indexes", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created folder indexes")
+	return nil
+}
+
+// Create stores a new folder
+func (dao *FolderDAO) Create(ctx context.Context, folder *models.Folder) error {
+	dao.logger.Info("DAO Level: Attempting to create folder")
+	if folder.ID.IsZero() {
+		folder.ID = primitive.NewObjectID()
+	}
+	if _, err := dao.collection.InsertOne(ctx, folder); err != nil {
+		dao.logger.Error("DAO Level: Failed to create folder", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created folder")
+	return nil
+}
+
+// FindByID retrieves a folder by its ID
+func (dao *FolderDAO) FindByID(ctx context.Context, id primitive.ObjectID) (models.Folder, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve folder by ID")
+	var folder models.Folder
+	err := dao.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&folder)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Warn("Folder not found")
+			return folder, ErrFolderNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to retrieve folder", err)
+		return folder, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved folder")
+	return folder, nil
+}
+
+// FindByOwnerID retrieves every folder of a user, ordered by name
+func (dao *FolderDAO) FindByOwnerID(ctx context.Context, ownerID primitive.ObjectID) ([]models.Folder, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve folders by owner ID")
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := dao.collection.Find(ctx, bson.M{"owner_id": ownerID}, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve folders", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var folders []models.Folder
+	if err := cursor.All(ctx, &folders); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode folders", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved folders by owner ID")
+	return folders, nil
+}
+
+// FindDescendantIDs retrieves the IDs of every folder nested below a folder
+func (dao *FolderDAO) FindDescendantIDs(ctx context.Context, id primitive.ObjectID) ([]primitive.ObjectID, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve folder descendants")
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := dao.collection.Find(ctx, bson.M{"ancestors": id}, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve folder descendants", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var folders []models.Folder
+	if err := cursor.All(ctx, &folders); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode folders", err)
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, len(folders))
+	for i, folder := range folders {
+		ids[i] = folder.ID
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved folder descendants")
+	return ids, nil
+}
+
+// Update applies an update document to a folder
+func (dao *FolderDAO) Update(ctx context.Context, id primitive.ObjectID, updates bson.M) error {
+	dao.logger.Info("DAO Level: Attempting to update folder")
+	result, err := dao.collection.UpdateOne(ctx, bson.M{"_id": id}, updates)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to update folder", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		dao.logger.Warn("Folder not found")
+		return ErrFolderNotFound
+	}
+	dao.logger.Info("DAO Level: Successfully updated folder")
+	return nil
+}
+
+// ReplaceAncestors swaps the ancestor prefix of every folder below a moved folder
+func (dao *FolderDAO) ReplaceAncestors(ctx context.Context, id primitive.ObjectID, oldAncestors, newAncestors []primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to update folder ancestors")
+	filter := bson.M{"ancestors": id}
+	if len(oldAncestors) > 0 {
+		if _, err := dao.collection.UpdateMany(ctx, filter, bson.M{"$pullAll": bson.M{"ancestors": oldAncestors}}); err != nil {
+			dao.logger.Error("DAO Level: Failed to update folder ancestors", err)
+			return err
+		}
+	}
+	if len(newAncestors) > 0 {
+		update := bson.M{"$push": bson.M{"ancestors": bson.M{"$each": newAncestors, "$position": 0}}}
+		if _, err := dao.collection.UpdateMany(ctx, filter, update); err != nil {
+			dao.logger.Error("DAO Level: Failed to update folder ancestors", err)
+			return err
+		}
+	}
+	dao.logger.Info("DAO Level: Successfully updated folder ancestors")
+	return nil
+}
+
+// Reparent moves the direct children of a folder under another parent, or to the
+// root when toParentID is zero, and drops the folder from every descendant's ancestors
+func (dao *FolderDAO) Reparent(ctx context.Context, fromParentID, toParentID primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to reparent folders")
+	update := bson.M{"$unset": bson.M{"parent_id": ""}}
+	if !toParentID.IsZero() {
+		update = bson.M{"$set": bson.M{"parent_id": toParentID}}
+	}
+	if _, err := dao.collection.UpdateMany(ctx, bson.M{"parent_id": fromParentID}, update); err != nil {
+		dao.logger.Error("DAO Level: Failed to reparent folders", err)
+		return err
+	}
+	if _, err := dao.collection.UpdateMany(ctx, bson.M{"ancestors": fromParentID}, bson.M{"$pull": bson.M{"ancestors": fromParentID}}); err != nil {
+		dao.logger.Error("DAO Level: Failed to reparent folders", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully reparented folders")
+	return nil
+}
+
+// Delete deletes a folder by its ID
+func (dao *FolderDAO) Delete(ctx context.Context, id primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to delete folder")
+	result, err := dao.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to delete folder", err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		dao.logger.Warn("Folder not found")
+		return ErrFolderNotFound
+	}
+	dao.logger.Info("DAO Level: Successfully deleted folder")
+	return nil
+}
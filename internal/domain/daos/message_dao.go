@@ -0,0 +1,542 @@
+package daos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrMessageNotFound is returned when no message matches the given case and ID
+var ErrMessageNotFound = errors.New("message not found")
+
+// MessageDAOInterface defines the interface for the MessageDAO
+type MessageDAOInterface interface {
+	EnsureIndexes(ctx context.Context) error
+	InsertMany(ctx context.Context, messages []models.Message) error
+	FindByCaseID(ctx context.Context, caseID primitive.ObjectID) ([]models.Message, error)
+	FindByCaseIDs(ctx context.Context, caseIDs []primitive.ObjectID) ([]models.Message, error)
+	FindAllByCaseID(ctx context.Context, caseID primitive.ObjectID) ([]models.Message, error)
+	FindRefs(ctx context.Context, caseID primitive.ObjectID) ([]models.MessageRef, error)
+	FindPage(ctx context.Context, caseID primitive.ObjectID, seqFilter bson.M, limit int64, ascending, mainLine bool) ([]models.Message, error)
+	Exists(ctx context.Context, caseID primitive.ObjectID, seqFilter bson.M, mainLine bool) (bool, error)
+	CountByCaseID(ctx context.Context, caseID primitive.ObjectID, mainLine bool) (int64, error)
+	FindReplies(ctx context.Context, caseID, parentID primitive.ObjectID) ([]models.Message, error)
+	CountReplies(ctx context.Context, caseID primitive.ObjectID, parentIDs []primitive.ObjectID) (map[primitive.ObjectID]int, error)
+	CountByDocumentID(ctx context.Context, documentID primitive.ObjectID) (int64, error)
+	FindToolMessages(ctx context.Context, caseID primitive.ObjectID) ([]models.Message, error)
+	DeleteByCaseID(ctx context.Context, caseID primitive.ObjectID) error
+	FindByID(ctx context.Context, caseID, id primitive.ObjectID) (models.Message, error)
+	UpdateContent(ctx context.Context, caseID, id primitive.ObjectID, content, documentPath string, editedAt time.Time, editedBy primitive.ObjectID) (models.Message, error)
+	SoftDelete(ctx context.Context, caseID, id primitive.ObjectID, deletedAt time.Time, deletedBy primitive.ObjectID) (models.Message, error)
+	Restore(ctx context.Context, caseID, id primitive.ObjectID, content, documentPath string, restoredAt time.Time, restoredBy primitive.ObjectID) (models.Message, error)
+	Search(ctx context.Context, caseIDs []primitive.ObjectID, filter models.MessageSearchFilter, limit int64) ([]models.MessageSearchResult, error)
+	SumUsage(ctx context.Context, caseIDs []primitive.ObjectID, from, to time.Time) ([]models.CaseUsage, error)
+}
+
+// MessageDAO implements the MessageDAOInterface
+type MessageDAO struct {
+	collection *mongo.Collection
+	logger     logs.Logger
+}
+
+// NewMessageDAO creates a new MessageDAO
+func NewMessageDAO(db *mongo.Database, logger logs.Logger) *MessageDAO {
+	return &MessageDAO{
+		collection: db.Collection("case_messages"),
+		logger:     logger,
+	}
+}
+
+// EnsureIndexes creates the indexes the message queries rely on
+func (dao *MessageDAO) EnsureIndexes(ctx context.Context) error {
+	dao.logger.Info("DAO Level: Attempting to create message indexes")
+	_, err := dao.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "case_id", Value: 1}, {Key: "seq", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "content", Value: "text"}}},
+		{Keys: bson.D{{Key: "case_id", Value: 1}, {Key: "parent_message_id", Value: 1}, {Key: "seq", Value: 1}}},
+		{Keys: bson.D{{Key: "document_id", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{
+			Keys:    bson.D{{Key: "case_id", Value: 1}, {Key: "created_at", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"usage": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to create message indexes", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created message indexes")
+	return nil
+}
+
+// InsertMany stores a batch of messages in the database
+func (dao *MessageDAO) InsertMany(ctx context.Context, messages []models.Message) error {
+	dao.logger.Info("DAO Level: Attempting to insert messages")
+	docs := make([]interface{}, len(messages))
+	for i := range messages {
+		docs[i] = messages[i]
+	}
+	if _, err := dao.collection.InsertMany(ctx, docs); err != nil {
+		dao.logger.Error("DAO Level: Failed to insert messages", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully inserted messages")
+	return nil
+}
+
+// FindByCaseID retrieves all messages of a case in sequence order
+func (dao *MessageDAO) FindByCaseID(ctx context.Context, caseID primitive.ObjectID) ([]models.Message, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve messages by case ID")
+	opts := options.Find().SetSort(bson.D{{Key: "seq", Value: 1}})
+	cursor, err := dao.collection.Find(ctx, liveMessages(bson.M{"case_id": caseID}), opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve messages by case ID", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []models.Message
+	if err := cursor.All(ctx, &messages); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode messages", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved messages by case ID")
+	return messages, nil
+}
+
+// FindAllByCaseID retrieves every message of a case in sequence order, soft-deleted
+// ones and their history included
+func (dao *MessageDAO) FindAllByCaseID(ctx context.Context, caseID primitive.ObjectID) ([]models.Message, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve all messages by case ID")
+	opts := options.Find().SetSort(bson.D{{Key: "seq", Value: 1}})
+	cursor, err := dao.collection.Find(ctx, bson.M{"case_id": caseID}, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve all messages by case ID", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []models.Message
+	if err := cursor.All(ctx, &messages); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode messages", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved all messages by case ID")
+	return messages, nil
+}
+
+// FindRefs retrieves the IDs and revision numbers of the live messages of a
+// case in sequence order, without reading their content
+func (dao *MessageDAO) FindRefs(ctx context.Context, caseID primitive.ObjectID) ([]models.MessageRef, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve message references")
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: liveMessages(bson.M{"case_id": caseID})}},
+		{{Key: "$sort", Value: bson.D{{Key: "seq", Value: 1}}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":      0,
+			"id":       "$_id",
+			"revision": bson.M{"$size": bson.M{"$ifNull": bson.A{"$history", bson.A{}}}},
+		}}},
+	}
+	cursor, err := dao.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve message references", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	refs := []models.MessageRef{}
+	if err := cursor.All(ctx, &refs); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode message references", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved message references")
+	return refs, nil
+}
+
+// FindByCaseIDs retrieves the messages of several cases, ordered by case and sequence
+func (dao *MessageDAO) FindByCaseIDs(ctx context.Context, caseIDs []primitive.ObjectID) ([]models.Message, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve messages by case IDs")
+	opts := options.Find().SetSort(bson.D{{Key: "case_id", Value: 1}, {Key: "seq", Value: 1}})
+	cursor, err := dao.collection.Find(ctx, liveMessages(bson.M{"case_id": bson.M{"$in": caseIDs}}), opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve messages by case IDs", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []models.Message
+	if err := cursor.All(ctx, &messages); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode messages", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved messages by case IDs")
+	return messages, nil
+}
+
+// FindPage retrieves up to limit messages of a case matching seqFilter, sorted by
+// sequence. With mainLine set, thread replies are left out
+func (dao *MessageDAO) FindPage(ctx context.Context, caseID primitive.ObjectID, seqFilter bson.M, limit int64, ascending, mainLine bool) ([]models.Message, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve messages page")
+	direction := -1
+	if ascending {
+		direction = 1
+	}
+	filter := caseMessages(caseID, mainLine)
+	if len(seqFilter) > 0 {
+		filter["seq"] = seqFilter
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "seq", Value: direction}}).SetLimit(limit)
+	cursor, err := dao.collection.Find(ctx, filter, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve messages page", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []models.Message
+	if err := cursor.All(ctx, &messages); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode messages", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved messages page")
+	return messages, nil
+}
+
+// Exists reports whether a case has at least one message matching seqFilter
+func (dao *MessageDAO) Exists(ctx context.Context, caseID primitive.ObjectID, seqFilter bson.M, mainLine bool) (bool, error) {
+	dao.logger.Info("DAO Level: Attempting to check for messages")
+	filter := caseMessages(caseID, mainLine)
+	if len(seqFilter) > 0 {
+		filter["seq"] = seqFilter
+	}
+	count, err := dao.collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to check for messages", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CountByCaseID returns the number of messages stored for a case
+func (dao *MessageDAO) CountByCaseID(ctx context.Context, caseID primitive.ObjectID, mainLine bool) (int64, error) {
+	dao.logger.Info("DAO Level: Attempting to count messages by case ID")
+	count, err := dao.collection.CountDocuments(ctx, caseMessages(caseID, mainLine))
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to count messages by case ID", err)
+		return 0, err
+	}
+	dao.logger.Info("DAO Level: Successfully counted messages by case ID")
+	return count, nil
+}
+
+// CountByDocumentID counts the live messages that refer to an uploaded document
+func (dao *MessageDAO) CountByDocumentID(ctx context.Context, documentID primitive.ObjectID) (int64, error) {
+	dao.logger.Info("DAO Level: Attempting to count messages by document ID")
+	count, err := dao.collection.CountDocuments(ctx, bson.M{
+		"document_id": documentID,
+		"deleted_at":  bson.M{"$exists": false},
+	})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to count messages by document ID", err)
+		return 0, err
+	}
+	dao.logger.Info("DAO Level: Successfully counted messages by document ID")
+	return count, nil
+}
+
+// DeleteByCaseID deletes every message of a case from the database
+func (dao *MessageDAO) DeleteByCaseID(ctx context.Context, caseID primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to delete messages by case ID")
+	if _, err := dao.collection.DeleteMany(ctx, bson.M{"case_id": caseID}); err != nil {
+		dao.logger.Error("DAO Level: Failed to delete messages by case ID", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully deleted messages by case ID")
+	return nil
+}
+
+// FindReplies retrieves the live replies in the thread of a message, in sequence order
+func (dao *MessageDAO) FindReplies(ctx context.Context, caseID, parentID primitive.ObjectID) ([]models.Message, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve message replies")
+	opts := options.Find().SetSort(bson.D{{Key: "seq", Value: 1}})
+	cursor, err := dao.collection.Find(ctx, liveMessages(bson.M{"case_id": caseID, "parent_message_id": parentID}), opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve message replies", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []models.Message
+	if err := cursor.All(ctx, &messages); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode messages", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved message replies")
+	return messages, nil
+}
+
+// FindToolMessages retrieves the live messages of a case that ask for tool calls
+// or carry tool results, in conversation order
+func (dao *MessageDAO) FindToolMessages(ctx context.Context, caseID primitive.ObjectID) ([]models.Message, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve tool messages")
+	filter := liveMessages(bson.M{
+		"case_id": caseID,
+		"$or": bson.A{
+			bson.M{"tool_calls.0": bson.M{"$exists": true}},
+			bson.M{"tool_result": bson.M{"$exists": true}},
+		},
+	})
+	opts := options.Find().
+		SetProjection(bson.M{"history": 0}).
+		SetSort(bson.D{{Key: "seq", Value: 1}})
+	cursor, err := dao.collection.Find(ctx, filter, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve tool messages", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []models.Message
+	if err := cursor.All(ctx, &messages); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode messages", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved tool messages")
+	return messages, nil
+}
+
+// CountReplies counts the live replies to each of the given messages. Messages
+// without replies are absent from the result
+func (dao *MessageDAO) CountReplies(ctx context.Context, caseID primitive.ObjectID, parentIDs []primitive.ObjectID) (map[primitive.ObjectID]int, error) {
+	dao.logger.Info("DAO Level: Attempting to count message replies")
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: liveMessages(bson.M{"case_id": caseID, "parent_message_id": bson.M{"$in": parentIDs}})}},
+		{{Key: "$group", Value: bson.M{"_id": "$parent_message_id", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := dao.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to count message replies", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		ParentID primitive.ObjectID `bson:"_id"`
+		Count    int                `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode reply counts", err)
+		return nil, err
+	}
+	counts := make(map[primitive.ObjectID]int, len(results))
+	for _, result := range results {
+		counts[result.ParentID] = result.Count
+	}
+	dao.logger.Info("DAO Level: Successfully counted message replies")
+	return counts, nil
+}
+
+// SumUsage totals the token usage of the messages of each of the given cases
+// created between from and to; zero bounds are left open. Soft-deleted messages
+// count, as their tokens were spent all the same. Cases without usage are absent.
+func (dao *MessageDAO) SumUsage(ctx context.Context, caseIDs []primitive.ObjectID, from, to time.Time) ([]models.CaseUsage, error) {
+	dao.logger.Info("DAO Level: Attempting to sum message usage")
+	match := bson.M{"case_id": bson.M{"$in": caseIDs}, "usage": bson.M{"$exists": true}}
+	createdAt := bson.M{}
+	if !from.IsZero() {
+		createdAt["$gte"] = from
+	}
+	if !to.IsZero() {
+		createdAt["$lte"] = to
+	}
+	if len(createdAt) > 0 {
+		match["created_at"] = createdAt
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":               "$case_id",
+			"prompt_tokens":     bson.M{"$sum": "$usage.prompt_tokens"},
+			"completion_tokens": bson.M{"$sum": "$usage.completion_tokens"},
+			"cost":              bson.M{"$sum": "$usage.cost"},
+			"messages":          bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err := dao.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to sum message usage", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []models.CaseUsage
+	if err := cursor.All(ctx, &results); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode message usage", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully summed message usage")
+	return results, nil
+}
+
+// FindByID retrieves a single message of a case, including soft-deleted ones
+func (dao *MessageDAO) FindByID(ctx context.Context, caseID, id primitive.ObjectID) (models.Message, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve message by ID")
+	var message models.Message
+	err := dao.collection.FindOne(ctx, bson.M{"_id": id, "case_id": caseID}).Decode(&message)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Warn("Message not found")
+			return models.Message{}, ErrMessageNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to retrieve message", err)
+		return models.Message{}, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved message")
+	return message, nil
+}
+
+// UpdateContent replaces the content of a live message, recording its previous
+// state in the message history within the same update
+func (dao *MessageDAO) UpdateContent(ctx context.Context, caseID, id primitive.ObjectID, content, documentPath string, editedAt time.Time, editedBy primitive.ObjectID) (models.Message, error) {
+	dao.logger.Info("DAO Level: Attempting to edit message")
+	update := bson.A{
+		bson.M{"$set": bson.M{
+			"history":       appendRevision(models.MessageRevisionEdit, editedAt, editedBy),
+			"content":       bson.M{"$literal": content},
+			"document_path": bson.M{"$literal": documentPath},
+			"edited_at":     editedAt,
+		}},
+	}
+	return dao.findOneAndUpdate(ctx, liveMessages(bson.M{"_id": id, "case_id": caseID}), update, "edit message")
+}
+
+// SoftDelete marks a live message as deleted, recording its last state in the history
+func (dao *MessageDAO) SoftDelete(ctx context.Context, caseID, id primitive.ObjectID, deletedAt time.Time, deletedBy primitive.ObjectID) (models.Message, error) {
+	dao.logger.Info("DAO Level: Attempting to delete message")
+	update := bson.A{
+		bson.M{"$set": bson.M{
+			"history":    appendRevision(models.MessageRevisionDelete, deletedAt, deletedBy),
+			"deleted_at": deletedAt,
+			"deleted_by": deletedBy,
+		}},
+	}
+	return dao.findOneAndUpdate(ctx, liveMessages(bson.M{"_id": id, "case_id": caseID}), update, "delete message")
+}
+
+// Restore brings a message, deleted or not, back to the given content, recording
+// its previous state in the history
+func (dao *MessageDAO) Restore(ctx context.Context, caseID, id primitive.ObjectID, content, documentPath string, restoredAt time.Time, restoredBy primitive.ObjectID) (models.Message, error) {
+	dao.logger.Info("DAO Level: Attempting to restore message")
+	update := bson.A{
+		bson.M{"$set": bson.M{
+			"history":       appendRevision(models.MessageRevisionRestore, restoredAt, restoredBy),
+			"content":       bson.M{"$literal": content},
+			"document_path": bson.M{"$literal": documentPath},
+			"edited_at":     restoredAt,
+		}},
+		bson.M{"$unset": bson.A{"deleted_at", "deleted_by"}},
+	}
+	return dao.findOneAndUpdate(ctx, bson.M{"_id": id, "case_id": caseID}, update, "restore message")
+}
+
+func (dao *MessageDAO) findOneAndUpdate(ctx context.Context, filter bson.M, update interface{}, action string) (models.Message, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var message models.Message
+	err := dao.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&message)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Warn("Message not found")
+			return models.Message{}, ErrMessageNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to "+action, err)
+		return models.Message{}, err
+	}
+	dao.logger.Info("DAO Level: Successfully completed " + action)
+	return message, nil
+}
+
+// appendRevision builds an aggregation expression that appends the current state
+// of the message to its history array.
+func appendRevision(action string, changedAt time.Time, changedBy primitive.ObjectID) bson.M {
+	return bson.M{"$concatArrays": bson.A{
+		bson.M{"$ifNull": bson.A{"$history", bson.A{}}},
+		bson.A{bson.M{
+			"action":        action,
+			"content":       "$content",
+			"document_path": "$document_path",
+			"changed_at":    changedAt,
+			"changed_by":    changedBy,
+		}},
+	}}
+}
+
+// Search finds live messages of the given cases matching the filter. Text queries
+// are ranked by relevance, other searches return the newest messages first.
+func (dao *MessageDAO) Search(ctx context.Context, caseIDs []primitive.ObjectID, filter models.MessageSearchFilter, limit int64) ([]models.MessageSearchResult, error) {
+	dao.logger.Info("DAO Level: Attempting to search messages")
+	query := liveMessages(bson.M{"case_id": bson.M{"$in": caseIDs}})
+	if filter.Sender != "" {
+		query["sender"] = filter.Sender
+	}
+	createdAt := bson.M{}
+	if !filter.From.IsZero() {
+		createdAt["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		createdAt["$lte"] = filter.To
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+
+	opts := options.Find().SetLimit(limit)
+	if filter.Query != "" {
+		query["$text"] = bson.M{"$search": filter.Query}
+		opts.SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}, "history": 0}).
+			SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}})
+	} else {
+		opts.SetProjection(bson.M{"history": 0}).
+			SetSort(bson.D{{Key: "created_at", Value: -1}})
+	}
+
+	cursor, err := dao.collection.Find(ctx, query, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to search messages", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []models.MessageSearchResult
+	if err := cursor.All(ctx, &results); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode message search results", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully searched messages")
+	return results, nil
+}
+
+// liveMessages narrows a filter to messages that have not been soft-deleted.
+func liveMessages(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+	return filter
+}
+
+// caseMessages matches the live messages of a case, only those of the main
+// conversation when mainLine is set.
+func caseMessages(caseID primitive.ObjectID, mainLine bool) bson.M {
+	filter := liveMessages(bson.M{"case_id": caseID})
+	if mainLine {
+		filter["parent_message_id"] = bson.M{"$exists": false}
+	}
+	return filter
+}
@@ -0,0 +1,179 @@
+package daos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrShareLinkNotFound is returned when no share link matches a token or ID
+var ErrShareLinkNotFound = errors.New("share link not found")
+
+// ShareLinkDAOInterface defines the interface for the ShareLinkDAO
+type ShareLinkDAOInterface interface {
+	EnsureIndexes(ctx context.Context) error
+	Create(ctx context.Context, link *models.ShareLink) error
+	FindByTokenHash(ctx context.Context, tokenHash string) (models.ShareLink, error)
+	FindByCaseID(ctx context.Context, caseID primitive.ObjectID) ([]models.ShareLink, error)
+	CountActive(ctx context.Context, caseID primitive.ObjectID, at time.Time) (int64, error)
+	Revoke(ctx context.Context, caseID, id primitive.ObjectID, revokedAt time.Time, revokedBy primitive.ObjectID) (models.ShareLink, error)
+	RecordAccess(ctx context.Context, id primitive.ObjectID, accessedAt time.Time) error
+	DeleteByCaseID(ctx context.Context, caseID primitive.ObjectID) error
+}
+
+// ShareLinkDAO implements the ShareLinkDAOInterface
+type ShareLinkDAO struct {
+	collection *mongo.Collection
+	logger     logs.Logger
+}
+
+// NewShareLinkDAO creates a new ShareLinkDAO
+func NewShareLinkDAO(db *mongo.Database, logger logs.Logger) *ShareLinkDAO {
+	return &ShareLinkDAO{
+		collection: db.Collection("case_share_links"),
+		logger:     logger,
+	}
+}
+
+// EnsureIndexes creates the indexes the share link queries rely on
+func (dao *ShareLinkDAO) EnsureIndexes(ctx context.Context) error {
+	dao.logger.Info("DAO Level: Attempting to create share link indexes")
+	_, err := dao.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "case_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to create share link indexes", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created share link indexes")
+	return nil
+}
+
+// Create stores a new share link
+func (dao *ShareLinkDAO) Create(ctx context.Context, link *models.ShareLink) error {
+	dao.logger.Info("DAO Level: Attempting to create share link")
+	if link.ID.IsZero() {
+		link.ID = primitive.NewObjectID()
+	}
+	if _, err := dao.collection.InsertOne(ctx, link); err != nil {
+		dao.logger.Error("DAO Level: Failed to create share link", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully created share link")
+	return nil
+}
+
+// FindByTokenHash retrieves the share link issued for a token
+func (dao *ShareLinkDAO) FindByTokenHash(ctx context.Context, tokenHash string) (models.ShareLink, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve share link by token")
+	var result models.ShareLink
+	err := dao.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Warn("Share link not found")
+			return result, ErrShareLinkNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to retrieve share link by token", err)
+		return result, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved share link by token")
+	return result, nil
+}
+
+// FindByCaseID retrieves every share link of a case, newest first, revoked ones included
+func (dao *ShareLinkDAO) FindByCaseID(ctx context.Context, caseID primitive.ObjectID) ([]models.ShareLink, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve share links of case")
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := dao.collection.Find(ctx, bson.M{"case_id": caseID}, opts)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve share links of case", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var links []models.ShareLink
+	if err := cursor.All(ctx, &links); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode share links", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved share links of case")
+	return links, nil
+}
+
+// CountActive counts the share links of a case that are neither revoked nor expired at the given time
+func (dao *ShareLinkDAO) CountActive(ctx context.Context, caseID primitive.ObjectID, at time.Time) (int64, error) {
+	dao.logger.Info("DAO Level: Attempting to count active share links")
+	filter := bson.M{
+		"case_id":    caseID,
+		"revoked_at": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$exists": false}},
+			bson.M{"expires_at": bson.M{"$gt": at}},
+		},
+	}
+	count, err := dao.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to count active share links", err)
+		return 0, err
+	}
+	dao.logger.Info("DAO Level: Successfully counted active share links")
+	return count, nil
+}
+
+// Revoke marks a share link of a case as revoked and returns it. Revoking a link
+// twice keeps the first revocation
+func (dao *ShareLinkDAO) Revoke(ctx context.Context, caseID, id primitive.ObjectID, revokedAt time.Time, revokedBy primitive.ObjectID) (models.ShareLink, error) {
+	dao.logger.Info("DAO Level: Attempting to revoke share link")
+	var result models.ShareLink
+	filter := bson.M{"_id": id, "case_id": caseID}
+	update := bson.A{bson.M{"$set": bson.M{
+		"revoked_at": bson.M{"$ifNull": bson.A{"$revoked_at", revokedAt}},
+		"revoked_by": bson.M{"$ifNull": bson.A{"$revoked_by", revokedBy}},
+	}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := dao.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			dao.logger.Warn("Share link not found")
+			return result, ErrShareLinkNotFound
+		}
+		dao.logger.Error("DAO Level: Failed to revoke share link", err)
+		return result, err
+	}
+	dao.logger.Info("DAO Level: Successfully revoked share link")
+	return result, nil
+}
+
+// RecordAccess counts one access through a share link
+func (dao *ShareLinkDAO) RecordAccess(ctx context.Context, id primitive.ObjectID, accessedAt time.Time) error {
+	dao.logger.Info("DAO Level: Attempting to record share link access")
+	update := bson.M{
+		"$inc": bson.M{"access_count": 1},
+		"$max": bson.M{"last_accessed_at": accessedAt},
+	}
+	if _, err := dao.collection.UpdateByID(ctx, id, update); err != nil {
+		dao.logger.Error("DAO Level: Failed to record share link access", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully recorded share link access")
+	return nil
+}
+
+// DeleteByCaseID permanently deletes every share link of a case
+func (dao *ShareLinkDAO) DeleteByCaseID(ctx context.Context, caseID primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to delete share links of case")
+	if _, err := dao.collection.DeleteMany(ctx, bson.M{"case_id": caseID}); err != nil {
+		dao.logger.Error("DAO Level: Failed to delete share links of case", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully deleted share links of case")
+	return nil
+}
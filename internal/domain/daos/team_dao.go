@@ -15,6 +15,7 @@ import (
 type TeamDAOInterface interface {
 	GetTeamByID(ctx context.Context, id primitive.ObjectID) (*models.Team, error)
 	GetAllTeams(ctx context.Context) ([]models.Team, error)
+	GetTeamsByUserID(ctx context.Context, userID primitive.ObjectID) ([]models.Team, error)
 	CreateTeam(ctx context.Context, team *models.Team) (*models.Team, error)
 	UpdateTeam(ctx context.Context, id primitive.ObjectID, update bson.M) (*mongo.UpdateResult, error)
 	DeleteTeam(ctx context.Context, id primitive.ObjectID) error
@@ -72,6 +73,29 @@ func (dao *TeamDAO) GetAllTeams(ctx context.Context) ([]models.Team, error) {
 	return teams, nil
 }
 
+// GetTeamsByUserID retrieves the teams a user administers or belongs to
+func (dao *TeamDAO) GetTeamsByUserID(ctx context.Context, userID primitive.ObjectID) ([]models.Team, error) {
+	dao.logger.Info("DAO Level: Attempting to retrieve teams by user ID")
+	filter := bson.M{"$or": bson.A{
+		bson.M{"admin_id": userID},
+		bson.M{"members.user_id": userID},
+	}}
+	cursor, err := dao.collection.Find(ctx, filter)
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to retrieve teams by user ID", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var teams []models.Team
+	if err := cursor.All(ctx, &teams); err != nil {
+		dao.logger.Error("DAO Level: Failed to decode teams", err)
+		return nil, err
+	}
+	dao.logger.Info("DAO Level: Successfully retrieved teams by user ID")
+	return teams, nil
+}
+
 // CreateTeam creates a new team in the database
 func (dao *TeamDAO) CreateTeam(ctx context.Context, team *models.Team) (*models.Team, error) {
 	dao.logger.Info("DAO Level: Attempting to create new team")
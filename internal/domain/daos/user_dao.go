@@ -11,6 +11,9 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// ErrUserNotFound is returned when no user matches a lookup
+var ErrUserNotFound = errors.New("user not found")
+
 // UserDAOInterface defines the interface for the UserDAO
 type UserDAOInterface interface {
 	GetUserByID(ctx context.Context, id primitive.ObjectID) (*models.User, error)
@@ -19,6 +22,7 @@ type UserDAOInterface interface {
 	CreateUser(ctx context.Context, user *models.User) (*models.User, error)
 	UpdateUser(ctx context.Context, id primitive.ObjectID, user map[string]interface{}) (*mongo.UpdateResult, error)
 	DeleteUser(ctx context.Context, id primitive.ObjectID) error
+	RemoveCaseID(ctx context.Context, caseID primitive.ObjectID) error
 	GetAllUsers(ctx context.Context) ([]*models.User, error)
 }
 
@@ -44,7 +48,7 @@ func (dao *UserDAO) GetUserByID(ctx context.Context, id primitive.ObjectID) (*mo
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			dao.logger.Warn("User not found")
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		dao.logger.Error("DAO Level: Failed to retrieve user", err)
 		return nil, err
@@ -61,7 +65,7 @@ func (dao *UserDAO) GetUserByEmail(ctx context.Context, email string) (*models.U
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			dao.logger.Warn("User not found")
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		dao.logger.Error("DAO Level: Failed to retrieve user", err)
 		return nil, err
@@ -78,7 +82,7 @@ func (dao *UserDAO) GetUserByCaseID(ctx context.Context, caseID primitive.Object
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			dao.logger.Warn("User not found")
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		dao.logger.Error("DAO Level: Failed to retrieve user", err)
 		return nil, err
@@ -112,6 +116,18 @@ func (dao *UserDAO) UpdateUser(ctx context.Context, id primitive.ObjectID, user
 	return result, nil
 }
 
+// RemoveCaseID pulls a case ID from the case lists of every user referencing it
+func (dao *UserDAO) RemoveCaseID(ctx context.Context, caseID primitive.ObjectID) error {
+	dao.logger.Info("DAO Level: Attempting to remove case reference from users")
+	_, err := dao.collection.UpdateMany(ctx, bson.M{"case_ids": caseID}, bson.M{"$pull": bson.M{"case_ids": caseID}})
+	if err != nil {
+		dao.logger.Error("DAO Level: Failed to remove case reference from users", err)
+		return err
+	}
+	dao.logger.Info("DAO Level: Successfully removed case reference from users")
+	return nil
+}
+
 // DeleteUser deletes a user by their ID from the database
 func (dao *UserDAO) DeleteUser(ctx context.Context, id primitive.ObjectID) error {
 	dao.logger.Info("DAO Level: Attempting to delete user")
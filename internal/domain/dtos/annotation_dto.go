@@ -0,0 +1,75 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TextRange selects characters [start, end) of a message's content, counted in
+// Unicode characters.
+type TextRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// CreateAnnotationRequest annotates a message. Without a range the whole message
+// is highlighted; without a note the annotation is a plain bookmark. Annotations
+// are private unless visibility is set to shared.
+type CreateAnnotationRequest struct {
+	Range      helpers.Nullable[TextRange] `json:"range"`
+	Note       helpers.Nullable[string]    `json:"note"`
+	Visibility helpers.Nullable[string]    `json:"visibility"`
+}
+
+// UpdateAnnotationRequest changes the note or visibility of an annotation. The
+// highlighted range cannot be moved; annotate the new range instead.
+type UpdateAnnotationRequest struct {
+	Note       helpers.Nullable[string] `json:"note"`
+	Visibility helpers.Nullable[string] `json:"visibility"`
+}
+
+// AnnotationQuery selects a page of annotations, newest first. Before is the ID of
+// the last annotation of the previous page.
+type AnnotationQuery struct {
+	MessageID helpers.Nullable[primitive.ObjectID]
+	Before    helpers.Nullable[primitive.ObjectID]
+	Limit     int
+}
+
+// AnnotationResponse describes an annotation. Quote is the highlighted text as it
+// read when the annotation was made.
+type AnnotationResponse struct {
+	ID              helpers.Nullable[primitive.ObjectID] `json:"id"`
+	CaseID          helpers.Nullable[primitive.ObjectID] `json:"case_id"`
+	MessageID       helpers.Nullable[primitive.ObjectID] `json:"message_id"`
+	MessagePosition helpers.Nullable[int]                `json:"message_position"`
+	AuthorID        helpers.Nullable[primitive.ObjectID] `json:"author_id"`
+	Range           helpers.Nullable[TextRange]          `json:"range"`
+	Quote           helpers.Nullable[string]             `json:"quote"`
+	Note            helpers.Nullable[string]             `json:"note"`
+	Visibility      helpers.Nullable[string]             `json:"visibility"`
+	CreatedAt       helpers.Nullable[time.Time]          `json:"created_at"`
+	UpdatedAt       helpers.Nullable[time.Time]          `json:"updated_at"`
+}
+
+// AnnotationPageResponse is one page of annotations. Next is the cursor to pass as
+// before to fetch the following, older page; it is absent on the last page.
+type AnnotationPageResponse struct {
+	Annotations []AnnotationResponse                 `json:"annotations"`
+	Next        helpers.Nullable[primitive.ObjectID] `json:"next"`
+}
+
+// BookmarkResponse is an annotation in a user's cross-case bookmark list, with
+// the name of its case so clients can list it without loading the case.
+type BookmarkResponse struct {
+	AnnotationResponse
+	CaseName helpers.Nullable[string] `json:"case_name"`
+}
+
+// BookmarkPageResponse is one page of a user's bookmarks, newest first.
+type BookmarkPageResponse struct {
+	Bookmarks []BookmarkResponse                   `json:"bookmarks"`
+	Next      helpers.Nullable[primitive.ObjectID] `json:"next"`
+}
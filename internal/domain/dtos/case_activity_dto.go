@@ -0,0 +1,33 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CaseActivityQuery selects a page of a case's activity, newest first. Before is
+// the ID of the last entry of the previous page; Actions keeps only entries of the
+// given action types.
+type CaseActivityQuery struct {
+	Actions []string
+	Before  helpers.Nullable[primitive.ObjectID]
+	Limit   int
+}
+
+// CaseActivityResponse is an entry of a case's activity feed.
+type CaseActivityResponse struct {
+	ID        helpers.Nullable[primitive.ObjectID] `json:"id"`
+	ActorID   helpers.Nullable[primitive.ObjectID] `json:"actor_id"`
+	Action    helpers.Nullable[string]             `json:"action"`
+	Fields    helpers.Nullable[[]string]           `json:"fields"`
+	CreatedAt helpers.Nullable[time.Time]          `json:"created_at"`
+}
+
+// CaseActivityPageResponse is one page of a case's activity. Next is the cursor to
+// pass as before to fetch the following, older page; it is absent on the last page.
+type CaseActivityPageResponse struct {
+	Activities []CaseActivityResponse               `json:"activities"`
+	Next       helpers.Nullable[primitive.ObjectID] `json:"next"`
+}
@@ -1,6 +1,7 @@
 package dtos
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
@@ -9,15 +10,48 @@ import (
 
 // Start of Selection
 type MessageResponse struct {
-	Content      helpers.Nullable[string] `json:"content,omitempty" bson:"content"`
-	Sender       helpers.Nullable[string] `json:"sender,omitempty" bson:"sender"`
-	Recipient    helpers.Nullable[string] `json:"recipient,omitempty" bson:"recipient"`
-	FunctionCall helpers.Nullable[bool]   `json:"function_call,omitempty" bson:"function_call"`
-	DocumentPath helpers.Nullable[string] `json:"document_path,omitempty" bson:"document_path"`
+	ID              helpers.Nullable[primitive.ObjectID]        `json:"id,omitempty" bson:"_id,omitempty"`
+	Content         helpers.Nullable[string]                    `json:"content,omitempty" bson:"content"`
+	Sender          helpers.Nullable[string]                    `json:"sender,omitempty" bson:"sender"`
+	Recipient       helpers.Nullable[string]                    `json:"recipient,omitempty" bson:"recipient"`
+	FunctionCall    helpers.Nullable[bool]                      `json:"function_call,omitempty" bson:"function_call"`
+	ToolCalls       helpers.Nullable[[]ToolCallResponse]        `json:"tool_calls,omitempty" bson:"tool_calls"`
+	ToolResult      helpers.Nullable[ToolResultResponse]        `json:"tool_result,omitempty" bson:"tool_result"`
+	Usage           helpers.Nullable[UsageResponse]             `json:"usage,omitempty" bson:"usage"`
+	DocumentPath    helpers.Nullable[string]                    `json:"document_path,omitempty" bson:"document_path"`
+	DocumentID      helpers.Nullable[primitive.ObjectID]        `json:"document_id,omitempty" bson:"document_id"`
+	AuthorUserID    helpers.Nullable[primitive.ObjectID]        `json:"author_user_id,omitempty" bson:"author_user_id"`
+	CreatedAt       helpers.Nullable[time.Time]                 `json:"created_at,omitempty" bson:"created_at"`
+	EditedAt        helpers.Nullable[time.Time]                 `json:"edited_at,omitempty" bson:"edited_at"`
+	DeletedAt       helpers.Nullable[time.Time]                 `json:"deleted_at,omitempty" bson:"deleted_at"`
+	History         helpers.Nullable[[]MessageRevisionResponse] `json:"history,omitempty" bson:"history"`
+	Position        helpers.Nullable[int]                       `json:"position,omitempty" bson:"-"`
+	ParentMessageID helpers.Nullable[primitive.ObjectID]        `json:"parent_message_id,omitempty" bson:"parent_message_id"`
+	ReplyCount      helpers.Nullable[int]                       `json:"reply_count,omitempty" bson:"-"` // only set on top-level messages
+}
+
+// MessageThreadResponse is a top-level message with the replies in its side thread.
+type MessageThreadResponse struct {
+	Parent  MessageResponse   `json:"parent"`
+	Replies []MessageResponse `json:"replies"`
+}
+
+type MessageRevisionResponse struct {
+	Action       helpers.Nullable[string]             `json:"action" bson:"action"`
+	Content      helpers.Nullable[string]             `json:"content" bson:"content"`
+	DocumentPath helpers.Nullable[string]             `json:"document_path" bson:"document_path"`
+	ChangedAt    helpers.Nullable[time.Time]          `json:"changed_at" bson:"changed_at"`
+	ChangedBy    helpers.Nullable[primitive.ObjectID] `json:"changed_by" bson:"changed_by"`
+}
+
+type EditMessageRequest struct {
+	Content      helpers.Nullable[string] `json:"content" bson:"content,omitempty"`
+	DocumentPath helpers.Nullable[string] `json:"document_path" bson:"document_path,omitempty"`
 }
 
 type CollaboratorResponse struct {
 	ID   helpers.Nullable[primitive.ObjectID] `json:"id" bson:"_id,omitempty"`
+	Role helpers.Nullable[string]             `json:"role" bson:"role"`
 	Edit helpers.Nullable[bool]               `json:"edit" bson:"edit"`
 }
 
@@ -31,6 +65,8 @@ type CreateCaseRequest struct {
 	LastEdit      helpers.Nullable[time.Time]              `json:"last_edit" bson:"last_edit"`
 	Share         helpers.Nullable[bool]                   `json:"share" bson:"share"`
 	IsArchived    helpers.Nullable[bool]                   `json:"is_archived" bson:"is_archived"`
+	Tags          helpers.Nullable[[]string]               `json:"tags" bson:"tags"`
+	TemplateID    helpers.Nullable[primitive.ObjectID]     `json:"template_id" bson:"template_id"`
 }
 
 type CaseResponse struct {
@@ -45,6 +81,25 @@ type CaseResponse struct {
 	LastEdit      helpers.Nullable[time.Time]              `json:"last_edit" bson:"last_edit"`
 	Share         helpers.Nullable[bool]                   `json:"share" bson:"share"`
 	IsArchived    helpers.Nullable[bool]                   `json:"is_archived" bson:"is_archived"`
+	DeletedAt     helpers.Nullable[time.Time]              `json:"deleted_at" bson:"deleted_at"`
+	DeletedBy     helpers.Nullable[primitive.ObjectID]     `json:"deleted_by" bson:"deleted_by"`
+	ForkedFrom    helpers.Nullable[CaseForkOriginResponse] `json:"forked_from" bson:"forked_from"`
+	FolderID      helpers.Nullable[primitive.ObjectID]     `json:"folder_id" bson:"folder_id"`
+	Tags          helpers.Nullable[[]string]               `json:"tags" bson:"tags"`
+}
+
+// CaseForkOriginResponse identifies the case and message a fork starts from.
+type CaseForkOriginResponse struct {
+	CaseID    helpers.Nullable[primitive.ObjectID] `json:"case_id"`
+	MessageID helpers.Nullable[primitive.ObjectID] `json:"message_id"`
+	Position  helpers.Nullable[int]                `json:"position"`
+}
+
+// ForkCaseRequest forks a case at the message with the given position. The fork
+// keeps every message up to and including that one.
+type ForkCaseRequest struct {
+	Position helpers.Nullable[int]    `json:"position"`
+	Name     helpers.Nullable[string] `json:"name"`
 }
 
 type UpdateCaseRequest struct {
@@ -56,12 +111,181 @@ type UpdateCaseRequest struct {
 	LastEdit      helpers.Nullable[time.Time]              `json:"last_edit" bson:"last_edit,omitempty"`
 	Share         helpers.Nullable[bool]                   `json:"share" bson:"share,omitempty"`
 	IsArchived    helpers.Nullable[bool]                   `json:"is_archived" bson:"is_archived,omitempty"`
+	FolderID      helpers.Nullable[primitive.ObjectID]     `json:"folder_id" bson:"folder_id,omitempty"` // "" takes the case out of its folder
+	Tags          helpers.Nullable[[]string]               `json:"tags" bson:"tags,omitempty"`
+}
+
+// CaseListQuery narrows the cases of a user to a folder, including its
+// subfolders, and to cases carrying all of the given tags.
+type CaseListQuery struct {
+	FolderID helpers.Nullable[primitive.ObjectID]
+	Tags     []string
+}
+
+// BulkTagRequest adds and removes tags on several cases at once.
+type BulkTagRequest struct {
+	CaseIDs []primitive.ObjectID `json:"case_ids"`
+	Add     []string             `json:"add"`
+	Remove  []string             `json:"remove"`
+}
+
+// BulkTagResponse reports how many of the requested cases were updated.
+type BulkTagResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+// AppendMessagesRequest carries a batch of messages to append to a case.
+// A single message may also be posted on its own, without the wrapper.
+type AppendMessagesRequest struct {
+	Messages helpers.Nullable[[]MessageResponse] `json:"messages" bson:"messages"`
+}
+
+// Message ordering for paginated history.
+const (
+	MessageOrderNewestFirst = "desc"
+	MessageOrderOldestFirst = "asc"
+)
+
+// MessagePageQuery selects a window of a case's messages. Before and After are
+// message positions used as exclusive cursors.
+type MessagePageQuery struct {
+	Before   helpers.Nullable[int]
+	After    helpers.Nullable[int]
+	Limit    int
+	Order    string
+	MainLine bool // leaves thread replies out
+}
+
+// MessagePageResponse is one page of a case's message history. Before and After
+// are the cursors to pass back to fetch the older and newer neighbouring pages.
+type MessagePageResponse struct {
+	Messages helpers.Nullable[[]MessageResponse] `json:"messages" bson:"messages"`
+	Total    helpers.Nullable[int]               `json:"total" bson:"total"`
+	Order    helpers.Nullable[string]            `json:"order" bson:"order"`
+	Before   helpers.Nullable[int]               `json:"before" bson:"before"`
+	After    helpers.Nullable[int]               `json:"after" bson:"after"`
 }
 
 type AddCollaboratorToCase struct {
 	Edit  helpers.Nullable[bool]   `json:"edit" bson:"edit,omitempty"`
+	Role  helpers.Nullable[string] `json:"role" bson:"role,omitempty"` // takes precedence over edit
 	Email helpers.Nullable[string] `json:"email" bson:"email,omitempty"`
 }
+
+// UpdateCollaboratorRoleRequest changes the role of a collaborator on a case.
+type UpdateCollaboratorRoleRequest struct {
+	Role helpers.Nullable[string] `json:"role" bson:"role"`
+}
 type DeleteCaseRequest struct {
 	ID helpers.Nullable[primitive.ObjectID] `json:"id" bson:"_id"`
 }
+
+// CaseSearchQuery filters a search over the caller's cases. At least one of
+// Query, Sender, From or To must be given.
+type CaseSearchQuery struct {
+	Query   string
+	Sender  string
+	From    helpers.Nullable[time.Time]
+	To      helpers.Nullable[time.Time]
+	AgentID helpers.Nullable[primitive.ObjectID]
+	Limit   int
+}
+
+// MessageSearchMatch is a message that matched a search. Position is the message
+// position within its case; Offset is the character offset of the first matching
+// term within the message content.
+type MessageSearchMatch struct {
+	MessageID helpers.Nullable[primitive.ObjectID] `json:"message_id" bson:"_id"`
+	Position  helpers.Nullable[int]                `json:"position" bson:"position"`
+	Sender    helpers.Nullable[string]             `json:"sender" bson:"sender"`
+	CreatedAt helpers.Nullable[time.Time]          `json:"created_at" bson:"created_at"`
+	Snippet   helpers.Nullable[string]             `json:"snippet" bson:"snippet"`
+	Offset    helpers.Nullable[int]                `json:"offset,omitempty" bson:"offset"`
+}
+
+// DocumentSearchMatch is an uploaded document whose extracted text matched a
+// search. Offset is the character offset of the first matching term within the
+// text.
+type DocumentSearchMatch struct {
+	DocumentID helpers.Nullable[primitive.ObjectID] `json:"document_id" bson:"_id"`
+	FileName   helpers.Nullable[string]             `json:"file_name" bson:"file_name"`
+	Path       helpers.Nullable[string]             `json:"path" bson:"path"`
+	Snippet    helpers.Nullable[string]             `json:"snippet" bson:"snippet"`
+	Offset     helpers.Nullable[int]                `json:"offset,omitempty" bson:"offset"`
+}
+
+// CaseSearchHit is a case matching a search, by name, by message content, by the
+// text of its documents or any of these.
+type CaseSearchHit struct {
+	CaseID    helpers.Nullable[primitive.ObjectID]    `json:"case_id" bson:"_id"`
+	Name      helpers.Nullable[string]                `json:"name" bson:"name"`
+	AgentID   helpers.Nullable[primitive.ObjectID]    `json:"agent_id" bson:"agent_id"`
+	LastEdit  helpers.Nullable[time.Time]             `json:"last_edit" bson:"last_edit"`
+	NameMatch helpers.Nullable[bool]                  `json:"name_match" bson:"name_match"`
+	Score     helpers.Nullable[float64]               `json:"score,omitempty" bson:"score"`
+	Matches   helpers.Nullable[[]MessageSearchMatch]  `json:"matches" bson:"matches"`
+	Documents helpers.Nullable[[]DocumentSearchMatch] `json:"documents" bson:"documents"`
+}
+
+type CaseSearchResponse struct {
+	Results helpers.Nullable[[]CaseSearchHit] `json:"results" bson:"results"`
+	Total   helpers.Nullable[int]             `json:"total" bson:"total"`
+}
+
+// ImportTranscriptRequest creates a case from an Autogen `groupchat.messages` dump.
+// Messages are kept raw so each one can be validated on its own.
+type ImportTranscriptRequest struct {
+	Name      helpers.Nullable[string]             `json:"name" bson:"name"`
+	AgentID   helpers.Nullable[primitive.ObjectID] `json:"agent_id" bson:"agent_id"`
+	Recipient helpers.Nullable[string]             `json:"recipient" bson:"recipient"`
+	Messages  []json.RawMessage                    `json:"messages" bson:"messages"`
+}
+
+// ImportMessageError reports why a message of an imported transcript was skipped.
+type ImportMessageError struct {
+	Index int    `json:"index" bson:"index"`
+	Error string `json:"error" bson:"error"`
+}
+
+type ImportTranscriptResponse struct {
+	Case     *CaseResponse        `json:"case" bson:"case"`
+	Imported int                  `json:"imported" bson:"imported"`
+	Skipped  int                  `json:"skipped" bson:"skipped"`
+	Errors   []ImportMessageError `json:"errors" bson:"errors"`
+}
+
+// CaseVersionResponse describes a recorded version of a case. The snapshot is
+// only included when a single version is requested.
+type CaseVersionResponse struct {
+	Version      helpers.Nullable[int]                `json:"version" bson:"version"`
+	Action       helpers.Nullable[string]             `json:"action" bson:"action"`
+	ActorID      helpers.Nullable[primitive.ObjectID] `json:"actor_id" bson:"actor_id"`
+	CreatedAt    helpers.Nullable[time.Time]          `json:"created_at" bson:"created_at"`
+	Name         helpers.Nullable[string]             `json:"name" bson:"name"`
+	MessageCount helpers.Nullable[int]                `json:"message_count" bson:"message_count"`
+	Snapshot     *CaseResponse                        `json:"snapshot,omitempty" bson:"snapshot,omitempty"`
+}
+
+// CaseFieldChange is a case field whose value differs between two versions.
+type CaseFieldChange struct {
+	Field string      `json:"field" bson:"field"`
+	From  interface{} `json:"from" bson:"from"`
+	To    interface{} `json:"to" bson:"to"`
+}
+
+// MessageChange is a message present in both versions with different content.
+type MessageChange struct {
+	ID   helpers.Nullable[primitive.ObjectID] `json:"id" bson:"_id"`
+	From MessageResponse                      `json:"from" bson:"from"`
+	To   MessageResponse                      `json:"to" bson:"to"`
+}
+
+// CaseVersionDiffResponse lists what changed from one version of a case to another.
+type CaseVersionDiffResponse struct {
+	From            helpers.Nullable[int] `json:"from" bson:"from"`
+	To              helpers.Nullable[int] `json:"to" bson:"to"`
+	Fields          []CaseFieldChange     `json:"fields" bson:"fields"`
+	MessagesAdded   []MessageResponse     `json:"messages_added" bson:"messages_added"`
+	MessagesRemoved []MessageResponse     `json:"messages_removed" bson:"messages_removed"`
+	MessagesChanged []MessageChange       `json:"messages_changed" bson:"messages_changed"`
+}
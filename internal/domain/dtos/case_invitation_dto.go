@@ -0,0 +1,22 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CaseInvitationResponse describes a pending invitation. Its token is only ever
+// sent to the invitee.
+type CaseInvitationResponse struct {
+	ID         helpers.Nullable[primitive.ObjectID] `json:"id"`
+	CaseID     helpers.Nullable[primitive.ObjectID] `json:"case_id"`
+	Email      helpers.Nullable[string]             `json:"email"`
+	Role       helpers.Nullable[string]             `json:"role"`
+	InvitedBy  helpers.Nullable[primitive.ObjectID] `json:"invited_by"`
+	CreatedAt  helpers.Nullable[time.Time]          `json:"created_at"`
+	ExpiresAt  helpers.Nullable[time.Time]          `json:"expires_at"`
+	AcceptedAt helpers.Nullable[time.Time]          `json:"accepted_at"`
+	AcceptedBy helpers.Nullable[primitive.ObjectID] `json:"accepted_by"`
+}
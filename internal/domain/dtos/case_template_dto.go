@@ -0,0 +1,40 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateCaseTemplateRequest creates a personal template, or a team template when
+// team_id is set.
+type CreateCaseTemplateRequest struct {
+	Name     helpers.Nullable[string]             `json:"name"`
+	TeamID   helpers.Nullable[primitive.ObjectID] `json:"team_id"`
+	AgentID  helpers.Nullable[primitive.ObjectID] `json:"agent_id"`
+	Action   helpers.Nullable[string]             `json:"action"`
+	Messages helpers.Nullable[[]MessageResponse]  `json:"messages"`
+	Tags     helpers.Nullable[[]string]           `json:"tags"`
+}
+
+type UpdateCaseTemplateRequest struct {
+	Name     helpers.Nullable[string]             `json:"name"`
+	AgentID  helpers.Nullable[primitive.ObjectID] `json:"agent_id"`
+	Action   helpers.Nullable[string]             `json:"action"`
+	Messages helpers.Nullable[[]MessageResponse]  `json:"messages"`
+	Tags     helpers.Nullable[[]string]           `json:"tags"`
+}
+
+type CaseTemplateResponse struct {
+	ID        helpers.Nullable[primitive.ObjectID] `json:"id"`
+	Name      helpers.Nullable[string]             `json:"name"`
+	CreatorID helpers.Nullable[primitive.ObjectID] `json:"creator_id"`
+	TeamID    helpers.Nullable[primitive.ObjectID] `json:"team_id"`
+	AgentID   helpers.Nullable[primitive.ObjectID] `json:"agent_id"`
+	Action    helpers.Nullable[string]             `json:"action"`
+	Messages  helpers.Nullable[[]MessageResponse]  `json:"messages"`
+	Tags      helpers.Nullable[[]string]           `json:"tags"`
+	CreatedAt helpers.Nullable[time.Time]          `json:"created_at"`
+	UpdatedAt helpers.Nullable[time.Time]          `json:"updated_at"`
+}
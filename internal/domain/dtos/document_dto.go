@@ -0,0 +1,34 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DocumentResponse describes an uploaded document. Path is the value messages use
+// as document_path to refer to it. TextStatus tells whether text could be
+// extracted from it; it is absent until extraction has run.
+type DocumentResponse struct {
+	ID          helpers.Nullable[primitive.ObjectID] `json:"id"`
+	CaseID      helpers.Nullable[primitive.ObjectID] `json:"case_id"`
+	UploaderID  helpers.Nullable[primitive.ObjectID] `json:"uploader_id"`
+	FileName    helpers.Nullable[string]             `json:"file_name"`
+	ContentType helpers.Nullable[string]             `json:"content_type"`
+	Size        helpers.Nullable[int64]              `json:"size"`
+	SHA256      helpers.Nullable[string]             `json:"sha256"`
+	Path        helpers.Nullable[string]             `json:"path"`
+	TextStatus  helpers.Nullable[string]             `json:"text_status"`
+	CreatedAt   helpers.Nullable[time.Time]          `json:"created_at"`
+}
+
+// DocumentTextResponse is the plain text extracted from a document. Length counts
+// its Unicode characters.
+type DocumentTextResponse struct {
+	DocumentID  helpers.Nullable[primitive.ObjectID] `json:"document_id"`
+	FileName    helpers.Nullable[string]             `json:"file_name"`
+	ContentType helpers.Nullable[string]             `json:"content_type"`
+	Length      helpers.Nullable[int]                `json:"length"`
+	Text        helpers.Nullable[string]             `json:"text"`
+}
@@ -0,0 +1,30 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CreateFolderRequest struct {
+	Name     helpers.Nullable[string]             `json:"name"`
+	ParentID helpers.Nullable[primitive.ObjectID] `json:"parent_id"`
+}
+
+// UpdateFolderRequest renames and/or moves a folder. A parent_id of "" moves the
+// folder to the root.
+type UpdateFolderRequest struct {
+	Name     helpers.Nullable[string]             `json:"name"`
+	ParentID helpers.Nullable[primitive.ObjectID] `json:"parent_id"`
+}
+
+type FolderResponse struct {
+	ID        helpers.Nullable[primitive.ObjectID]   `json:"id"`
+	OwnerID   helpers.Nullable[primitive.ObjectID]   `json:"owner_id"`
+	Name      helpers.Nullable[string]               `json:"name"`
+	ParentID  helpers.Nullable[primitive.ObjectID]   `json:"parent_id"`
+	Ancestors helpers.Nullable[[]primitive.ObjectID] `json:"ancestors"`
+	CreatedAt helpers.Nullable[time.Time]            `json:"created_at"`
+	UpdatedAt helpers.Nullable[time.Time]            `json:"updated_at"`
+}
@@ -0,0 +1,34 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateShareLinkRequest creates a public link to a case. Every field is optional:
+// links default to the read_only scope, never expire and need no password.
+type CreateShareLinkRequest struct {
+	Scope     helpers.Nullable[string]    `json:"scope"`
+	ExpiresAt helpers.Nullable[time.Time] `json:"expires_at"`
+	Password  helpers.Nullable[string]    `json:"password"`
+}
+
+// ShareLinkResponse describes a share link. The token is only returned when the
+// link is created; it cannot be recovered afterwards.
+type ShareLinkResponse struct {
+	ID                helpers.Nullable[primitive.ObjectID] `json:"id"`
+	CaseID            helpers.Nullable[primitive.ObjectID] `json:"case_id"`
+	Token             helpers.Nullable[string]             `json:"token"`
+	Scope             helpers.Nullable[string]             `json:"scope"`
+	PasswordProtected helpers.Nullable[bool]               `json:"password_protected"`
+	Active            helpers.Nullable[bool]               `json:"active"`
+	CreatedBy         helpers.Nullable[primitive.ObjectID] `json:"created_by"`
+	CreatedAt         helpers.Nullable[time.Time]          `json:"created_at"`
+	ExpiresAt         helpers.Nullable[time.Time]          `json:"expires_at"`
+	RevokedAt         helpers.Nullable[time.Time]          `json:"revoked_at"`
+	RevokedBy         helpers.Nullable[primitive.ObjectID] `json:"revoked_by"`
+	AccessCount       helpers.Nullable[int64]              `json:"access_count"`
+	LastAccessedAt    helpers.Nullable[time.Time]          `json:"last_accessed_at"`
+}
@@ -0,0 +1,44 @@
+package dtos
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ToolCallResponse is a function or tool invocation an agent asked for.
+// Arguments is the JSON value the function is called with.
+type ToolCallResponse struct {
+	CallID    helpers.Nullable[string]          `json:"call_id" bson:"call_id"`
+	Name      helpers.Nullable[string]          `json:"name" bson:"name"`
+	Arguments helpers.Nullable[json.RawMessage] `json:"arguments" bson:"arguments"`
+}
+
+// ToolResultResponse marks a message as the outcome of the call with CallID. The
+// output is the message content; Status is success or error.
+type ToolResultResponse struct {
+	CallID     helpers.Nullable[string] `json:"call_id" bson:"call_id"`
+	Name       helpers.Nullable[string] `json:"name" bson:"name"`
+	Status     helpers.Nullable[string] `json:"status" bson:"status"`
+	DurationMS helpers.Nullable[int64]  `json:"duration_ms" bson:"duration_ms"`
+}
+
+// ToolInvocationResponse pairs a tool call with its result. Status is pending
+// while no result has been recorded; a result whose call is not in the case is
+// listed on its own.
+type ToolInvocationResponse struct {
+	CallID          helpers.Nullable[string]             `json:"call_id"`
+	Name            helpers.Nullable[string]             `json:"name"`
+	Arguments       helpers.Nullable[json.RawMessage]    `json:"arguments"`
+	Caller          helpers.Nullable[string]             `json:"caller"`
+	CallMessageID   helpers.Nullable[primitive.ObjectID] `json:"call_message_id"`
+	CallPosition    helpers.Nullable[int]                `json:"call_position"`
+	CalledAt        helpers.Nullable[time.Time]          `json:"called_at"`
+	Status          helpers.Nullable[string]             `json:"status"`
+	DurationMS      helpers.Nullable[int64]              `json:"duration_ms"`
+	Output          helpers.Nullable[string]             `json:"output"`
+	ResultMessageID helpers.Nullable[primitive.ObjectID] `json:"result_message_id"`
+	ResultPosition  helpers.Nullable[int]                `json:"result_position"`
+}
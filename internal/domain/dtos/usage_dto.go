@@ -0,0 +1,57 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UsageResponse is what generating a message took. Cost is worked out by the
+// server and ignored on input.
+type UsageResponse struct {
+	Model            helpers.Nullable[string]  `json:"model" bson:"model"`
+	PromptTokens     helpers.Nullable[int64]   `json:"prompt_tokens" bson:"prompt_tokens"`
+	CompletionTokens helpers.Nullable[int64]   `json:"completion_tokens" bson:"completion_tokens"`
+	Cost             helpers.Nullable[float64] `json:"cost" bson:"cost"`
+}
+
+// Usage groupings.
+const (
+	UsageByCase = "case"
+	UsageByUser = "user"
+	UsageByTeam = "team"
+)
+
+type UsageQuery struct {
+	GroupBy string
+	From    helpers.Nullable[time.Time]
+	To      helpers.Nullable[time.Time]
+}
+
+// UsageTotals adds up the usage of a number of messages.
+type UsageTotals struct {
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	TotalTokens      int64   `json:"total_tokens"`
+	Cost             float64 `json:"cost"`
+	Messages         int     `json:"messages"`
+}
+
+// UsageGroupResponse is the usage of one case, user or team. ID is absent for
+// the group of users without a team.
+type UsageGroupResponse struct {
+	ID   helpers.Nullable[primitive.ObjectID] `json:"id"`
+	Name helpers.Nullable[string]             `json:"name,omitempty"`
+	UsageTotals
+}
+
+// UsageReportResponse is the usage of the cases a caller can access, grouped as
+// asked, with the overall total.
+type UsageReportResponse struct {
+	GroupBy string                      `json:"group_by"`
+	From    helpers.Nullable[time.Time] `json:"from,omitempty"`
+	To      helpers.Nullable[time.Time] `json:"to,omitempty"`
+	Groups  []UsageGroupResponse        `json:"groups"`
+	Total   UsageTotals                 `json:"total"`
+}
@@ -2,9 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	http_errors "github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -32,6 +37,17 @@ func (h *BaseHandler) RespondWithError(w http.ResponseWriter, code int, message
 	h.RespondWithJSON(w, code, APIError{Code: code, Message: message})
 }
 
+// RespondWithServiceError maps a SlugError returned by a service onto its HTTP status,
+// falling back to a 500 with the given message for any other error.
+func (h *BaseHandler) RespondWithServiceError(w http.ResponseWriter, err error, fallback string) {
+	var slugErr http_errors.SlugError
+	if errors.As(err, &slugErr) {
+		h.RespondWithError(w, slugErr.HTTPStatus(), slugErr.Error())
+		return
+	}
+	h.RespondWithError(w, http.StatusInternalServerError, fallback)
+}
+
 func (h *BaseHandler) ParseObjectID(r *http.Request, key string, fromHeader bool) (primitive.ObjectID, error) {
 	if fromHeader {
 		return primitive.ObjectIDFromHex(strings.TrimSpace(r.Header.Get("Authorization")))
@@ -39,6 +55,59 @@ func (h *BaseHandler) ParseObjectID(r *http.Request, key string, fromHeader bool
 	return primitive.ObjectIDFromHex(strings.TrimSpace(mux.Vars(r)[key]))
 }
 
+// ParseCallerID reads the caller's user ID from the Authorization header. Clients
+// that cannot set headers, such as browser EventSource and WebSocket connections,
+// may pass it as the user_id query parameter instead.
+func (h *BaseHandler) ParseCallerID(r *http.Request) (primitive.ObjectID, error) {
+	if header := strings.TrimSpace(r.Header.Get("Authorization")); header != "" {
+		return primitive.ObjectIDFromHex(header)
+	}
+	return primitive.ObjectIDFromHex(strings.TrimSpace(r.URL.Query().Get("user_id")))
+}
+
+// ParseIntQuery reads an optional integer query parameter.
+func (h *BaseHandler) ParseIntQuery(r *http.Request, key string) (helpers.Nullable[int], error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(key))
+	if raw == "" {
+		return helpers.Nullable[int]{}, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return helpers.Nullable[int]{}, err
+	}
+	return helpers.Nullable[int]{Value: value, Present: true}, nil
+}
+
+// ParseBoolQuery reads an optional boolean query parameter; an absent one is false.
+func (h *BaseHandler) ParseBoolQuery(r *http.Request, key string) (bool, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(key))
+	if raw == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(raw)
+}
+
+// ParseTimeQuery reads an optional RFC 3339 timestamp or YYYY-MM-DD date query
+// parameter. A bare date resolves to the start of that day (UTC), or to its last
+// instant when endOfDay is set so it can close an inclusive range.
+func (h *BaseHandler) ParseTimeQuery(r *http.Request, key string, endOfDay bool) (helpers.Nullable[time.Time], error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(key))
+	if raw == "" {
+		return helpers.Nullable[time.Time]{}, nil
+	}
+	if value, err := time.Parse(time.RFC3339, raw); err == nil {
+		return helpers.NewNullable(value), nil
+	}
+	value, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return helpers.Nullable[time.Time]{}, err
+	}
+	if endOfDay {
+		value = value.Add(24*time.Hour - time.Nanosecond)
+	}
+	return helpers.NewNullable(value), nil
+}
+
 func (h *BaseHandler) DecodeJSONBody(r *http.Request, v interface{}) error {
 	return json.NewDecoder(r.Body).Decode(v)
 }
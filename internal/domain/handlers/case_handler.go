@@ -2,17 +2,29 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/events"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/services"
+	http_errors "github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type CaseHanler interface {
-	GetAllCases(ctx context.Context) ([]dtos.CaseResponse, error)
+	GetAllCases(ctx context.Context, callerID primitive.ObjectID) ([]dtos.CaseResponse, error)
 	GetCaseByID(ctx context.Context, id primitive.ObjectID) (*dtos.CaseResponse, error)
-	GetCasesByCreatorID(ctx context.Context, creatorID primitive.ObjectID) ([]dtos.CaseResponse, error)
+	GetCasesByCreatorID(ctx context.Context, creatorID primitive.ObjectID, query dtos.CaseListQuery) ([]dtos.CaseResponse, error)
 	CreateCase(ctx context.Context, req *dtos.CreateCaseRequest) (*dtos.CaseResponse, error)
 	UpdateCase(ctx context.Context, id primitive.ObjectID, req *dtos.UpdateCaseRequest) (*dtos.CaseResponse, error)
 	DeleteCase(ctx context.Context, id primitive.ObjectID) (*dtos.CaseResponse, error)
@@ -30,9 +42,14 @@ func NewCaseHandler(service *services.CaseServiceImpl) *CaseHandler {
 }
 
 func (h *CaseHandler) GetAllCases(w http.ResponseWriter, r *http.Request) {
-	cases, err := h.service.GetAllCases(r.Context())
+	callerID, err := h.ParseCallerID(r)
 	if err != nil {
-		h.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve cases")
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+	cases, err := h.service.GetAllCases(r.Context(), callerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve cases")
 		return
 	}
 	h.RespondWithJSON(w, http.StatusOK, cases)
@@ -44,10 +61,21 @@ func (h *CaseHandler) GetCaseByID(w http.ResponseWriter, r *http.Request) {
 		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
 		return
 	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	mainLine, err := h.ParseBoolQuery(r, "main_line")
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid main_line flag")
+		return
+	}
 
-	caseResponse, err := h.service.GetCaseByID(r.Context(), id)
+	caseResponse, err := h.service.GetCaseByID(r.Context(), id, callerID, mainLine)
 	if err != nil {
-		h.RespondWithError(w, http.StatusNotFound, "Case not found")
+		h.RespondWithServiceError(w, err, "Failed to retrieve case")
 		return
 	}
 	h.RespondWithJSON(w, http.StatusOK, caseResponse)
@@ -60,9 +88,19 @@ func (h *CaseHandler) GetCasesByCreatorID(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	cases, err := h.service.GetCasesByCreatorID(r.Context(), creatorID)
+	query := dtos.CaseListQuery{Tags: r.URL.Query()["tag"]}
+	if folder := strings.TrimSpace(r.URL.Query().Get("folder")); folder != "" {
+		folderID, err := primitive.ObjectIDFromHex(folder)
+		if err != nil {
+			h.RespondWithError(w, http.StatusBadRequest, "Invalid folder ID")
+			return
+		}
+		query.FolderID = helpers.NewNullable(folderID)
+	}
+
+	cases, err := h.service.GetCasesByCreatorID(r.Context(), creatorID, query)
 	if err != nil {
-		h.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve cases")
+		h.RespondWithServiceError(w, err, "Failed to retrieve cases")
 		return
 	}
 	h.RespondWithJSON(w, http.StatusOK, cases)
@@ -83,7 +121,7 @@ func (h *CaseHandler) CreateCase(w http.ResponseWriter, r *http.Request) {
 	req.CreatorID.Present = true
 	createdCase, err := h.service.CreateCase(r.Context(), req)
 	if err != nil {
-		h.RespondWithError(w, http.StatusInternalServerError, "Failed to create case")
+		h.RespondWithServiceError(w, err, "Failed to create case")
 		return
 	}
 	h.RespondWithJSON(w, http.StatusCreated, createdCase)
@@ -96,15 +134,21 @@ func (h *CaseHandler) UpdateCase(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
 	var req dtos.UpdateCaseRequest
 	if err := h.DecodeJSONBody(r, &req); err != nil {
 		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
 
-	updatedCase, err := h.service.UpdateCase(r.Context(), id, req)
+	updatedCase, err := h.service.UpdateCase(r.Context(), id, callerID, req)
 	if err != nil {
-		h.RespondWithError(w, http.StatusInternalServerError, "Failed to update case")
+		h.RespondWithServiceError(w, err, "Failed to update case")
 		return
 	}
 	h.RespondWithJSON(w, http.StatusOK, updatedCase)
@@ -117,9 +161,15 @@ func (h *CaseHandler) DeleteCase(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	deletedCase, err := h.service.DeleteCase(r.Context(), id)
+	callerID, err := h.ParseCallerID(r)
 	if err != nil {
-		h.RespondWithError(w, http.StatusInternalServerError, "Failed to delete case")
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	deletedCase, err := h.service.DeleteCase(r.Context(), id, callerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to delete case")
 		return
 	}
 	h.RespondWithJSON(w, http.StatusOK, deletedCase)
@@ -132,15 +182,25 @@ func (h *CaseHandler) AddCollaboratorToCase(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
 	var req dtos.AddCollaboratorToCase
 	if err := h.DecodeJSONBody(r, &req); err != nil {
 		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
-
-	newUser, err := h.service.AddCollaboratorToCase(r.Context(), caseID, req.Email.Value, req.Edit.Value)
+	newUser, invitation, err := h.service.AddCollaboratorToCase(r.Context(), caseID, callerID, req)
 	if err != nil {
-		h.RespondWithError(w, http.StatusInternalServerError, "Failed to add collaborator to case")
+		h.RespondWithServiceError(w, err, "Failed to add collaborator to case")
+		return
+	}
+	// Emails without an account get an invitation instead, resolved once they sign up.
+	if invitation != nil {
+		h.RespondWithJSON(w, http.StatusAccepted, invitation)
 		return
 	}
 	h.RespondWithJSON(w, http.StatusOK, newUser)
@@ -153,16 +213,1176 @@ func (h *CaseHandler) RemoveCollaboratorFromCase(w http.ResponseWriter, r *http.
 		return
 	}
 
-	collaboratorID, err := h.ParseObjectID(r, "collaboratorID", false)
+	collaboratorID, err := h.ParseObjectID(r, "userID", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid collaborator ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	updatedCase, err := h.service.RemoveCollaboratorFromCase(r.Context(), caseID, callerID, collaboratorID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to remove collaborator from case")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, updatedCase)
+}
+
+func (h *CaseHandler) UpdateCollaboratorRole(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	collaboratorID, err := h.ParseObjectID(r, "userID", false)
 	if err != nil {
 		h.RespondWithError(w, http.StatusBadRequest, "Invalid collaborator ID")
 		return
 	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	var req dtos.UpdateCollaboratorRoleRequest
+	if err := h.DecodeJSONBody(r, &req); err != nil || !req.Role.Present {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
 
-	updatedCase, err := h.service.RemoveCollaboratorFromCase(r.Context(), caseID, collaboratorID)
+	updatedCase, err := h.service.UpdateCollaboratorRole(r.Context(), caseID, callerID, collaboratorID, req.Role.Value)
 	if err != nil {
-		h.RespondWithError(w, http.StatusInternalServerError, "Failed to remove collaborator from case")
+		h.RespondWithServiceError(w, err, "Failed to update collaborator role")
 		return
 	}
 	h.RespondWithJSON(w, http.StatusOK, updatedCase)
 }
+
+func (h *CaseHandler) AppendMessages(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Accept either {"messages": [...]} for a batch or a bare message object.
+	var req dtos.AppendMessagesRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	messages := req.Messages.Value
+	if !req.Messages.Present {
+		var message dtos.MessageResponse
+		if err := json.Unmarshal(body, &message); err != nil {
+			h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+		messages = []dtos.MessageResponse{message}
+	}
+
+	authorID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+	stored, err := h.service.AppendMessages(r.Context(), caseID, authorID, messages)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to append messages")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusCreated, stored)
+}
+
+func (h *CaseHandler) GetCaseMessages(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	var query dtos.MessagePageQuery
+	if query.Before, err = h.ParseIntQuery(r, "before"); err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid before cursor")
+		return
+	}
+	if query.After, err = h.ParseIntQuery(r, "after"); err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid after cursor")
+		return
+	}
+	limit, err := h.ParseIntQuery(r, "limit")
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+	query.Limit = limit.Value
+	query.Order = r.URL.Query().Get("order")
+	if query.MainLine, err = h.ParseBoolQuery(r, "main_line"); err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid main_line flag")
+		return
+	}
+
+	page, err := h.service.GetCaseMessages(r.Context(), caseID, callerID, query)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve messages")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, page)
+}
+
+func (h *CaseHandler) EditMessage(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	messageID, err := h.ParseObjectID(r, "msgID", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid message ID")
+		return
+	}
+	editorID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	var req dtos.EditMessageRequest
+	if err := h.DecodeJSONBody(r, &req); err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	message, err := h.service.EditMessage(r.Context(), caseID, messageID, editorID, req)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to edit message")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, message)
+}
+
+func (h *CaseHandler) DeleteMessage(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	messageID, err := h.ParseObjectID(r, "msgID", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid message ID")
+		return
+	}
+	deleterID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	message, err := h.service.DeleteMessage(r.Context(), caseID, messageID, deleterID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to delete message")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, message)
+}
+
+func (h *CaseHandler) GetMessageHistory(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	messageID, err := h.ParseObjectID(r, "msgID", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid message ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	message, err := h.service.GetMessageHistory(r.Context(), caseID, messageID, callerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve message history")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, message)
+}
+
+// GetMessageThread returns a top-level message with the replies in its thread.
+func (h *CaseHandler) GetMessageThread(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	messageID, err := h.ParseObjectID(r, "msgID", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid message ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	thread, err := h.service.GetMessageThread(r.Context(), caseID, messageID, callerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve message thread")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, thread)
+}
+
+func (h *CaseHandler) SearchCases(w http.ResponseWriter, r *http.Request) {
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	values := r.URL.Query()
+	query := dtos.CaseSearchQuery{
+		Query:  values.Get("q"),
+		Sender: values.Get("sender"),
+	}
+	if query.From, err = h.ParseTimeQuery(r, "from", false); err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid from date")
+		return
+	}
+	if query.To, err = h.ParseTimeQuery(r, "to", true); err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid to date")
+		return
+	}
+	if agentID := values.Get("agent_id"); agentID != "" {
+		id, err := primitive.ObjectIDFromHex(agentID)
+		if err != nil {
+			h.RespondWithError(w, http.StatusBadRequest, "Invalid agent ID")
+			return
+		}
+		query.AgentID = helpers.NewNullable(id)
+	}
+	limit, err := h.ParseIntQuery(r, "limit")
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+	query.Limit = limit.Value
+
+	results, err := h.service.SearchCases(r.Context(), callerID, query)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to search cases")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, results)
+}
+
+const sseHeartbeatInterval = 15 * time.Second
+
+// StreamCaseEvents streams the events of a case as Server-Sent Events. Clients
+// resume after a disconnect through the Last-Event-ID header, or the
+// last_event_id query parameter when the header cannot be set.
+func (h *CaseHandler) StreamCaseEvents(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.RespondWithError(w, http.StatusInternalServerError, "Streaming unsupported")
+		return
+	}
+
+	lastEventID := strings.TrimSpace(r.Header.Get("Last-Event-ID"))
+	if lastEventID == "" {
+		lastEventID = strings.TrimSpace(r.URL.Query().Get("last_event_id"))
+	}
+	var since uint64
+	if lastEventID != "" {
+		if since, err = strconv.ParseUint(lastEventID, 10, 64); err != nil {
+			h.RespondWithError(w, http.StatusBadRequest, "Invalid last event ID")
+			return
+		}
+	}
+
+	subscription, replay, err := h.service.SubscribeCaseEvents(r.Context(), caseID, callerID, since)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to subscribe to case events")
+		return
+	}
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	for _, event := range replay {
+		if err := writeServerSentEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return
+			}
+			if err := writeServerSentEvent(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeServerSentEvent(w io.Writer, event events.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+func (h *CaseHandler) ExportCase(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "md"
+	}
+
+	exported, err := h.service.ExportCase(r.Context(), caseID, callerID, format)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to export case")
+		return
+	}
+	w.Header().Set("Content-Type", exported.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exported.FileName))
+	w.WriteHeader(http.StatusOK)
+	w.Write(exported.Content)
+}
+
+// ImportTranscript creates a case for the caller from an Autogen transcript. The
+// body is an import request or a bare message list; for the latter the case name
+// may be passed as the name query parameter.
+func (h *CaseHandler) ImportTranscript(w http.ResponseWriter, r *http.Request) {
+	creatorID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	req, err := services.DecodeImportTranscript(body)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if name := r.URL.Query().Get("name"); name != "" && !req.Name.Present {
+		req.Name = helpers.NewNullable(name)
+	}
+
+	report, err := h.service.ImportTranscript(r.Context(), creatorID, req)
+	if err != nil {
+		var slugErr http_errors.SlugError
+		if report != nil && errors.As(err, &slugErr) {
+			// Nothing was importable: return the per-message report with the error status.
+			h.RespondWithJSON(w, slugErr.HTTPStatus(), report)
+			return
+		}
+		h.RespondWithServiceError(w, err, "Failed to import transcript")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusCreated, report)
+}
+
+func (h *CaseHandler) GetCaseVersions(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	versions, err := h.service.GetCaseVersions(r.Context(), caseID, callerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve case versions")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, versions)
+}
+
+func (h *CaseHandler) GetCaseVersion(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	version, err := strconv.Atoi(mux.Vars(r)["version"])
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid version")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	caseVersion, err := h.service.GetCaseVersion(r.Context(), caseID, callerID, version)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve case version")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, caseVersion)
+}
+
+// DiffCaseVersions compares the versions given by the from and to query parameters.
+func (h *CaseHandler) DiffCaseVersions(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	from, err := h.ParseIntQuery(r, "from")
+	if err != nil || !from.Present {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid or missing from version")
+		return
+	}
+	to, err := h.ParseIntQuery(r, "to")
+	if err != nil || !to.Present {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid or missing to version")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	diff, err := h.service.DiffCaseVersions(r.Context(), caseID, callerID, from.Value, to.Value)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to diff case versions")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, diff)
+}
+
+func (h *CaseHandler) RestoreCaseVersion(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	version, err := strconv.Atoi(mux.Vars(r)["version"])
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid version")
+		return
+	}
+
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	restoredCase, err := h.service.RestoreCaseVersion(r.Context(), caseID, callerID, version)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to restore case version")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, restoredCase)
+}
+
+func (h *CaseHandler) GetTrashedCases(w http.ResponseWriter, r *http.Request) {
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	cases, err := h.service.GetTrashedCases(r.Context(), callerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve trashed cases")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, cases)
+}
+
+func (h *CaseHandler) RestoreCase(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	restoredCase, err := h.service.RestoreCase(r.Context(), caseID, callerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to restore case")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, restoredCase)
+}
+
+func (h *CaseHandler) ForkCase(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	var req dtos.ForkCaseRequest
+	if err := h.DecodeJSONBody(r, &req); err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	forkedCase, err := h.service.ForkCase(r.Context(), caseID, callerID, req)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to fork case")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusCreated, forkedCase)
+}
+
+func (h *CaseHandler) GetCaseForks(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	forks, err := h.service.GetCaseForks(r.Context(), caseID, callerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve case forks")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, forks)
+}
+
+func (h *CaseHandler) GetToolInvocations(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	invocations, err := h.service.GetToolInvocations(r.Context(), caseID, callerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve tool invocations")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, invocations)
+}
+
+func (h *CaseHandler) BulkTagCases(w http.ResponseWriter, r *http.Request) {
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	var req dtos.BulkTagRequest
+	if err := h.DecodeJSONBody(r, &req); err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	result, err := h.service.BulkTagCases(r.Context(), callerID, req)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to tag cases")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, result)
+}
+
+func (h *CaseHandler) CreateShareLink(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	var req dtos.CreateShareLinkRequest
+	if err := h.DecodeJSONBody(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	link, err := h.service.CreateShareLink(r.Context(), caseID, callerID, req)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to create share link")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusCreated, link)
+}
+
+func (h *CaseHandler) GetShareLinks(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	links, err := h.service.GetShareLinks(r.Context(), caseID, callerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve share links")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, links)
+}
+
+func (h *CaseHandler) RevokeShareLink(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	linkID, err := h.ParseObjectID(r, "linkID", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid share link ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	if err := h.service.RevokeShareLink(r.Context(), caseID, linkID, callerID); err != nil {
+		h.RespondWithServiceError(w, err, "Failed to revoke share link")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSharedCase serves the read-only view behind a share link. It needs no user;
+// password protected links take the password in the X-Share-Password header.
+func (h *CaseHandler) GetSharedCase(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSpace(mux.Vars(r)["token"])
+	password := r.Header.Get("X-Share-Password")
+
+	sharedCase, err := h.service.GetSharedCase(r.Context(), token, password)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve shared case")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, sharedCase)
+}
+
+func (h *CaseHandler) GetCaseInvitations(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	invitations, err := h.service.GetCaseInvitations(r.Context(), caseID, callerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve case invitations")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, invitations)
+}
+
+func (h *CaseHandler) RevokeCaseInvitation(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	invitationID, err := h.ParseObjectID(r, "invitationID", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid invitation ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	if err := h.service.RevokeCaseInvitation(r.Context(), caseID, invitationID, callerID); err != nil {
+		h.RespondWithServiceError(w, err, "Failed to revoke case invitation")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AcceptCaseInvitation adds the calling user to the case an invitation token was
+// issued for.
+func (h *CaseHandler) AcceptCaseInvitation(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSpace(mux.Vars(r)["token"])
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	acceptedCase, err := h.service.AcceptCaseInvitation(r.Context(), token, callerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to accept case invitation")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, acceptedCase)
+}
+
+// GetCaseActivity returns a page of a case's activity, newest first. The action
+// query parameter, repeated or comma separated, keeps only the given action types.
+func (h *CaseHandler) GetCaseActivity(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	var query dtos.CaseActivityQuery
+	values := r.URL.Query()
+	for _, value := range values["action"] {
+		for _, action := range strings.Split(value, ",") {
+			if action = strings.TrimSpace(action); action != "" {
+				query.Actions = append(query.Actions, action)
+			}
+		}
+	}
+	if before := values.Get("before"); before != "" {
+		id, err := primitive.ObjectIDFromHex(before)
+		if err != nil {
+			h.RespondWithError(w, http.StatusBadRequest, "Invalid before cursor")
+			return
+		}
+		query.Before = helpers.NewNullable(id)
+	}
+	limit, err := h.ParseIntQuery(r, "limit")
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid limit")
+		return
+	}
+	query.Limit = limit.Value
+
+	page, err := h.service.GetCaseActivity(r.Context(), caseID, callerID, query)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve case activity")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, page)
+}
+
+func (h *CaseHandler) CreateAnnotation(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	messageID, err := h.ParseObjectID(r, "msgID", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid message ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	var req dtos.CreateAnnotationRequest
+	if err := h.DecodeJSONBody(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	annotation, err := h.service.CreateAnnotation(r.Context(), caseID, messageID, callerID, req)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to create annotation")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusCreated, annotation)
+}
+
+func (h *CaseHandler) GetAnnotations(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+	query, ok := h.parseAnnotationQuery(w, r)
+	if !ok {
+		return
+	}
+	if messageID := r.URL.Query().Get("message_id"); messageID != "" {
+		id, err := primitive.ObjectIDFromHex(messageID)
+		if err != nil {
+			h.RespondWithError(w, http.StatusBadRequest, "Invalid message ID")
+			return
+		}
+		query.MessageID = helpers.NewNullable(id)
+	}
+
+	page, err := h.service.GetAnnotations(r.Context(), caseID, callerID, query)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve annotations")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, page)
+}
+
+func (h *CaseHandler) UpdateAnnotation(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	annotationID, err := h.ParseObjectID(r, "annotationID", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid annotation ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	var req dtos.UpdateAnnotationRequest
+	if err := h.DecodeJSONBody(r, &req); err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	annotation, err := h.service.UpdateAnnotation(r.Context(), caseID, annotationID, callerID, req)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to update annotation")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, annotation)
+}
+
+func (h *CaseHandler) DeleteAnnotation(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	annotationID, err := h.ParseObjectID(r, "annotationID", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid annotation ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	if err := h.service.DeleteAnnotation(r.Context(), caseID, annotationID, callerID); err != nil {
+		h.RespondWithServiceError(w, err, "Failed to delete annotation")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CaseHandler) GetUserBookmarks(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+	query, ok := h.parseAnnotationQuery(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.service.GetUserBookmarks(r.Context(), userID, callerID, query)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve bookmarks")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, page)
+}
+
+// parseAnnotationQuery reads the before cursor and page size of an annotation
+// listing, responding with an error when either is invalid.
+func (h *CaseHandler) parseAnnotationQuery(w http.ResponseWriter, r *http.Request) (dtos.AnnotationQuery, bool) {
+	var query dtos.AnnotationQuery
+	if before := r.URL.Query().Get("before"); before != "" {
+		id, err := primitive.ObjectIDFromHex(before)
+		if err != nil {
+			h.RespondWithError(w, http.StatusBadRequest, "Invalid before cursor")
+			return query, false
+		}
+		query.Before = helpers.NewNullable(id)
+	}
+	limit, err := h.ParseIntQuery(r, "limit")
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid limit")
+		return query, false
+	}
+	query.Limit = limit.Value
+	return query, true
+}
+
+// UploadDocument stores the file sent in the "file" field of a multipart form.
+// The part is streamed to storage without being buffered in memory.
+func (h *CaseHandler) UploadDocument(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Expected a multipart form")
+		return
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			h.RespondWithError(w, http.StatusBadRequest, "Missing file field")
+			return
+		}
+		if err != nil {
+			h.RespondWithError(w, http.StatusBadRequest, "Invalid multipart form")
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+		document, err := h.service.UploadDocument(r.Context(), caseID, callerID, part.FileName(), part.Header.Get("Content-Type"), part)
+		part.Close()
+		if err != nil {
+			h.RespondWithServiceError(w, err, "Failed to upload document")
+			return
+		}
+		h.RespondWithJSON(w, http.StatusCreated, document)
+		return
+	}
+}
+
+func (h *CaseHandler) GetCaseDocuments(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	documents, err := h.service.GetCaseDocuments(r.Context(), caseID, callerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve case documents")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, documents)
+}
+
+func (h *CaseHandler) DownloadDocument(w http.ResponseWriter, r *http.Request) {
+	documentID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid document ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	document, err := h.service.DownloadDocument(r.Context(), documentID, callerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to download document")
+		return
+	}
+	defer document.Content.Close()
+	w.Header().Set("Content-Type", document.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(document.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": document.FileName}))
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, document.Content)
+}
+
+func (h *CaseHandler) GetDocumentText(w http.ResponseWriter, r *http.Request) {
+	documentID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid document ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	text, err := h.service.GetDocumentText(r.Context(), documentID, callerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve document text")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, text)
+}
+
+func (h *CaseHandler) DeleteDocument(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	documentID, err := h.ParseObjectID(r, "documentID", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid document ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	if err := h.service.DeleteDocument(r.Context(), caseID, documentID, callerID); err != nil {
+		h.RespondWithServiceError(w, err, "Failed to delete document")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CaseHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	query := dtos.UsageQuery{GroupBy: r.URL.Query().Get("group_by")}
+	if query.GroupBy == "" {
+		query.GroupBy = dtos.UsageByCase
+	}
+	if query.From, err = h.ParseTimeQuery(r, "from", false); err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid from date")
+		return
+	}
+	if query.To, err = h.ParseTimeQuery(r, "to", true); err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid to date")
+		return
+	}
+
+	report, err := h.service.GetUsage(r.Context(), callerID, query)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve usage")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/services"
+)
+
+type CaseTemplateHandler struct {
+	BaseHandler
+	service *services.CaseTemplateServiceImpl
+}
+
+func NewCaseTemplateHandler(service *services.CaseTemplateServiceImpl) *CaseTemplateHandler {
+	return &CaseTemplateHandler{service: service}
+}
+
+func (h *CaseTemplateHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	templates, err := h.service.GetTemplates(r.Context(), userID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve case templates")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, templates)
+}
+
+func (h *CaseTemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	var req dtos.CreateCaseTemplateRequest
+	if err := h.DecodeJSONBody(r, &req); err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	template, err := h.service.CreateTemplate(r.Context(), userID, req)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to create case template")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusCreated, template)
+}
+
+func (h *CaseTemplateHandler) GetTemplateByID(w http.ResponseWriter, r *http.Request) {
+	id, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+	userID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	template, err := h.service.GetTemplateByID(r.Context(), id, userID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve case template")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, template)
+}
+
+func (h *CaseTemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+	userID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	var req dtos.UpdateCaseTemplateRequest
+	if err := h.DecodeJSONBody(r, &req); err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	template, err := h.service.UpdateTemplate(r.Context(), id, userID, req)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to update case template")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, template)
+}
+
+func (h *CaseTemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+	userID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	if err := h.service.DeleteTemplate(r.Context(), id, userID); err != nil {
+		h.RespondWithServiceError(w, err, "Failed to delete case template")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/services"
+)
+
+type FolderHandler struct {
+	BaseHandler
+	service *services.FolderServiceImpl
+}
+
+func NewFolderHandler(service *services.FolderServiceImpl) *FolderHandler {
+	return &FolderHandler{service: service}
+}
+
+func (h *FolderHandler) GetFolders(w http.ResponseWriter, r *http.Request) {
+	ownerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	folders, err := h.service.GetFolders(r.Context(), ownerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve folders")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, folders)
+}
+
+func (h *FolderHandler) CreateFolder(w http.ResponseWriter, r *http.Request) {
+	ownerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	var req dtos.CreateFolderRequest
+	if err := h.DecodeJSONBody(r, &req); err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	folder, err := h.service.CreateFolder(r.Context(), ownerID, req)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to create folder")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusCreated, folder)
+}
+
+func (h *FolderHandler) GetFolderByID(w http.ResponseWriter, r *http.Request) {
+	id, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid folder ID")
+		return
+	}
+	ownerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	folder, err := h.service.GetFolderByID(r.Context(), id, ownerID)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to retrieve folder")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, folder)
+}
+
+func (h *FolderHandler) UpdateFolder(w http.ResponseWriter, r *http.Request) {
+	id, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid folder ID")
+		return
+	}
+	ownerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	var req dtos.UpdateFolderRequest
+	if err := h.DecodeJSONBody(r, &req); err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	folder, err := h.service.UpdateFolder(r.Context(), id, ownerID, req)
+	if err != nil {
+		h.RespondWithServiceError(w, err, "Failed to update folder")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusOK, folder)
+}
+
+func (h *FolderHandler) DeleteFolder(w http.ResponseWriter, r *http.Request) {
+	id, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid folder ID")
+		return
+	}
+	ownerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+
+	if err := h.service.DeleteFolder(r.Context(), id, ownerID); err != nil {
+		h.RespondWithServiceError(w, err, "Failed to delete folder")
+		return
+	}
+	h.RespondWithJSON(w, http.StatusNoContent, nil)
+}
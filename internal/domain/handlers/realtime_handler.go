@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/realtime"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/services"
+	"golang.org/x/net/websocket"
+)
+
+type RealtimeHandler struct {
+	BaseHandler
+	hub         *realtime.Hub
+	caseService *services.CaseServiceImpl
+}
+
+func NewRealtimeHandler(hub *realtime.Hub, caseService *services.CaseServiceImpl) *RealtimeHandler {
+	return &RealtimeHandler{hub: hub, caseService: caseService}
+}
+
+// ConnectCase upgrades the request to a WebSocket shared by the collaborators of a
+// case. The caller must have access to the case.
+func (h *RealtimeHandler) ConnectCase(w http.ResponseWriter, r *http.Request) {
+	caseID, err := h.ParseObjectID(r, "id", false)
+	if err != nil {
+		h.RespondWithError(w, http.StatusBadRequest, "Invalid case ID")
+		return
+	}
+	callerID, err := h.ParseCallerID(r)
+	if err != nil {
+		h.RespondWithError(w, http.StatusUnauthorized, "Invalid or missing user ID")
+		return
+	}
+	if err := h.caseService.AuthorizeCaseAccess(r.Context(), caseID, callerID); err != nil {
+		h.RespondWithServiceError(w, err, "Failed to authorize case access")
+		return
+	}
+
+	server := websocket.Server{
+		Handler: func(conn *websocket.Conn) {
+			h.hub.Serve(conn, caseID, callerID)
+		},
+	}
+	server.ServeHTTP(w, r)
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Annotation highlights a message of a case, or a range of characters within it,
+// and optionally attaches a note. Private annotations are only visible to their
+// author; shared ones to everyone with access to the case. An annotation without
+// a note is a plain bookmark.
+type Annotation struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CaseID          primitive.ObjectID `json:"case_id" bson:"case_id"`
+	MessageID       primitive.ObjectID `json:"message_id" bson:"message_id"`
+	MessageSequence int                `json:"message_sequence" bson:"message_seq"`
+	AuthorID        primitive.ObjectID `json:"author_id" bson:"author_id"`
+	Range           *TextRange         `json:"range,omitempty" bson:"range,omitempty"`
+	Quote           string             `json:"quote,omitempty" bson:"quote,omitempty"` // highlighted text when the range was set
+	Note            string             `json:"note,omitempty" bson:"note,omitempty"`
+	Visibility      string             `json:"visibility" bson:"visibility"`
+	CreatedAt       time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// TextRange selects characters [Start, End) of a message's content. Offsets count
+// Unicode characters, not bytes.
+type TextRange struct {
+	Start int `json:"start" bson:"start"`
+	End   int `json:"end" bson:"end"`
+}
+
+// Annotation visibilities.
+const (
+	AnnotationVisibilityPrivate = "private"
+	AnnotationVisibilityShared  = "shared"
+)
+
+// ValidAnnotationVisibility reports whether v is a known annotation visibility.
+func ValidAnnotationVisibility(v string) bool {
+	return v == AnnotationVisibilityPrivate || v == AnnotationVisibilityShared
+}
+
+// VisibleTo reports whether a user with access to the case may see the annotation.
+func (a Annotation) VisibleTo(userID primitive.ObjectID) bool {
+	return a.Visibility == AnnotationVisibilityShared || a.AuthorID == userID
+}
+
+// AnnotationFilter selects annotations, newest first. Before is an exclusive
+// cursor on the annotation ID; empty fields do not filter.
+type AnnotationFilter struct {
+	MessageID primitive.ObjectID
+	Before    primitive.ObjectID
+	Limit     int
+}
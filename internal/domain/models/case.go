@@ -10,7 +10,9 @@ type Case struct {
 	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	Name          string             `json:"name" bson:"name"`
 	CreatorID     primitive.ObjectID `json:"creator_id" bson:"creator_id"`
-	Messages      []Message          `json:"messages" bson:"messages"`
+	Messages      []Message          `json:"messages" bson:"messages,omitempty"` // legacy embedded messages; new ones live in case_messages
+	MessageSeq    int                `json:"message_seq" bson:"message_seq"`     // next message sequence number
+	VersionSeq    int                `json:"version_seq" bson:"version_seq"`     // last recorded version number
 	Collaborators []Collaborators    `json:"collaborators" bson:"collaborators"`
 	Action        string             `json:"action" bson:"action"`
 	AgentID       primitive.ObjectID `json:"agent_id" bson:"agent_id"`
@@ -18,17 +20,112 @@ type Case struct {
 	LastEdit      time.Time          `json:"last_edit" bson:"last_edit"`
 	Share         bool               `json:"share" bson:"share"`
 	IsArchived    bool               `json:"is_archived" bson:"is_archived"`
+	DeletedAt     time.Time          `json:"deleted_at" bson:"deleted_at,omitempty"` // set while the case is in the trash
+	DeletedBy     primitive.ObjectID `json:"deleted_by" bson:"deleted_by,omitempty"`
+	ForkedFrom    *CaseForkOrigin    `json:"forked_from" bson:"forked_from,omitempty"`
+	FolderID      primitive.ObjectID `json:"folder_id" bson:"folder_id,omitempty"`
+	Tags          []string           `json:"tags" bson:"tags,omitempty"`
+}
+
+// CaseListFilter narrows a case listing to a set of folders and to cases carrying
+// every one of the given tags. Empty fields do not filter.
+type CaseListFilter struct {
+	FolderIDs []primitive.ObjectID
+	Tags      []string
+}
+
+// CaseForkOrigin points at the case and message a fork was taken from.
+type CaseForkOrigin struct {
+	CaseID    primitive.ObjectID `json:"case_id" bson:"case_id"`
+	MessageID primitive.ObjectID `json:"message_id" bson:"message_id"`
+	Position  int                `json:"position" bson:"position"`
 }
 
 type Collaborators struct {
 	ID   primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	Edit bool               `json:"edit" bson:"edit"`
+	Role string             `json:"role" bson:"role,omitempty"`
+	Edit bool               `json:"edit" bson:"edit"` // kept in step with Role for older clients
 }
 
 type Message struct {
-	Sender       string `json:"sender" bson:"sender"`
-	Recipient    string `json:"recipient" bson:"recipient"`
-	Content      string `json:"content" bson:"content"`
-	DocumentPath string `json:"document_path" bson:"document_path"`
-	FunctionCall bool   `json:"function_call" bson:"function_call"`
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CaseID          primitive.ObjectID `json:"case_id" bson:"case_id,omitempty"`
+	Sequence        int                `json:"seq" bson:"seq"`
+	Sender          string             `json:"sender" bson:"sender"`
+	Recipient       string             `json:"recipient" bson:"recipient"`
+	Content         string             `json:"content" bson:"content"`
+	DocumentPath    string             `json:"document_path" bson:"document_path"`
+	DocumentID      primitive.ObjectID `json:"document_id" bson:"document_id,omitempty"` // set when the document was uploaded to the case
+	FunctionCall    bool               `json:"function_call" bson:"function_call"`       // kept in step with ToolCalls and ToolResult for older clients
+	ToolCalls       []ToolCall         `json:"tool_calls" bson:"tool_calls,omitempty"`
+	ToolResult      *ToolResult        `json:"tool_result" bson:"tool_result,omitempty"`
+	Usage           *TokenUsage        `json:"usage" bson:"usage,omitempty"` // set on messages generated by a model
+	AuthorUserID    primitive.ObjectID `json:"author_user_id" bson:"author_user_id,omitempty"`
+	ParentMessageID primitive.ObjectID `json:"parent_message_id" bson:"parent_message_id,omitempty"` // set on replies in the side thread of a top-level message
+	CreatedAt       time.Time          `json:"created_at" bson:"created_at"`
+	EditedAt        time.Time          `json:"edited_at" bson:"edited_at,omitempty"`
+	DeletedAt       time.Time          `json:"deleted_at" bson:"deleted_at,omitempty"`
+	DeletedBy       primitive.ObjectID `json:"deleted_by" bson:"deleted_by,omitempty"`
+	History         []MessageRevision  `json:"history" bson:"history,omitempty"`
+}
+
+// AtRevision returns the message as it stood after the given number of
+// revisions, going back through its history. Messages are always live at the
+// revisions versions refer to.
+func (m Message) AtRevision(revision int) Message {
+	if revision >= len(m.History) {
+		return m
+	}
+	past := m
+	past.Content = m.History[revision].Content
+	past.DocumentPath = m.History[revision].DocumentPath
+	past.History = m.History[:revision:revision]
+	past.EditedAt = time.Time{}
+	if revision > 0 {
+		past.EditedAt = m.History[revision-1].ChangedAt
+	}
+	past.DeletedAt = time.Time{}
+	past.DeletedBy = primitive.NilObjectID
+	return past
+}
+
+// IsReply reports whether the message belongs to a side thread rather than the
+// main conversation.
+func (m Message) IsReply() bool {
+	return !m.ParentMessageID.IsZero()
+}
+
+// MessageRevision records the state of a message before an edit or a delete.
+type MessageRevision struct {
+	Action       string             `json:"action" bson:"action"` // "edit", "delete" or "restore"
+	Content      string             `json:"content" bson:"content"`
+	DocumentPath string             `json:"document_path" bson:"document_path"`
+	ChangedAt    time.Time          `json:"changed_at" bson:"changed_at"`
+	ChangedBy    primitive.ObjectID `json:"changed_by" bson:"changed_by,omitempty"`
+}
+
+const (
+	MessageRevisionEdit    = "edit"
+	MessageRevisionDelete  = "delete"
+	MessageRevisionRestore = "restore"
+)
+
+// CaseSearchResult is a case whose name matched a text search.
+type CaseSearchResult struct {
+	ID    primitive.ObjectID `bson:"_id"`
+	Score float64            `bson:"score"`
+}
+
+// MessageSearchResult is a message that matched a search, with its text score.
+type MessageSearchResult struct {
+	Message `bson:",inline"`
+	Score   float64 `bson:"score"`
+}
+
+// MessageSearchFilter narrows a message search. Empty fields are ignored.
+type MessageSearchFilter struct {
+	Query  string
+	Sender string
+	From   time.Time
+	To     time.Time
 }
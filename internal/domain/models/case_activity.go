@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CaseActivity records who changed a case, how and when. Fields lists the case
+// fields the change touched.
+type CaseActivity struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CaseID    primitive.ObjectID `json:"case_id" bson:"case_id"`
+	ActorID   primitive.ObjectID `json:"actor_id" bson:"actor_id,omitempty"`
+	Action    string             `json:"action" bson:"action"`
+	Fields    []string           `json:"fields,omitempty" bson:"fields,omitempty"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// CaseActivityFilter selects a page of a case's activity, newest first. Before is
+// an exclusive cursor on the activity ID; empty fields do not filter.
+type CaseActivityFilter struct {
+	Actions []string
+	Before  primitive.ObjectID
+	Limit   int
+}
+
+// Case mutations recorded in the activity feed without a version.
+const (
+	CaseActionCreateShareLink    = "create_share_link"
+	CaseActionRevokeShareLink    = "revoke_share_link"
+	CaseActionInviteCollaborator = "invite_collaborator"
+	CaseActionRevokeInvitation   = "revoke_invitation"
+	CaseActionUploadDocument     = "upload_document"
+	CaseActionDeleteDocument     = "delete_document"
+)
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CaseInvitation invites someone, by email, to collaborate on a case before they
+// necessarily have an account. Only a hash of its token is stored.
+type CaseInvitation struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CaseID     primitive.ObjectID `json:"case_id" bson:"case_id"`
+	Email      string             `json:"email" bson:"email"` // normalized to lower case
+	Role       string             `json:"role" bson:"role"`
+	TokenHash  string             `json:"-" bson:"token_hash"`
+	InvitedBy  primitive.ObjectID `json:"invited_by" bson:"invited_by"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+	ExpiresAt  time.Time          `json:"expires_at" bson:"expires_at"`
+	AcceptedAt time.Time          `json:"accepted_at" bson:"accepted_at,omitempty"`
+	AcceptedBy primitive.ObjectID `json:"accepted_by" bson:"accepted_by,omitempty"`
+}
+
+// Pending reports whether the invitation can still be accepted at the given time.
+func (i CaseInvitation) Pending(at time.Time) bool {
+	return i.AcceptedAt.IsZero() && at.Before(i.ExpiresAt)
+}
@@ -0,0 +1,61 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Case roles, from most to least privileged. The creator of a case is always
+// one of its owners.
+const (
+	CaseRoleOwner     = "owner"     // manages collaborators, deletes and restores the case
+	CaseRoleEditor    = "editor"    // changes the case and its messages
+	CaseRoleCommenter = "commenter" // reads the case and comments on it
+	CaseRoleViewer    = "viewer"    // reads the case
+)
+
+var caseRoleRanks = map[string]int{
+	CaseRoleViewer:    1,
+	CaseRoleCommenter: 2,
+	CaseRoleEditor:    3,
+	CaseRoleOwner:     4,
+}
+
+// ValidCaseRole reports whether role is one of the case roles.
+func ValidCaseRole(role string) bool {
+	_, ok := caseRoleRanks[role]
+	return ok
+}
+
+// CaseRoleAllows reports whether role grants at least what required does. An
+// empty role allows nothing.
+func CaseRoleAllows(role, required string) bool {
+	rank, ok := caseRoleRanks[role]
+	return ok && rank >= caseRoleRanks[required]
+}
+
+// EffectiveRole returns the collaborator's role. Collaborators stored before
+// roles existed only carry the edit flag, which maps to editor or viewer.
+func (c Collaborators) EffectiveRole() string {
+	if ValidCaseRole(c.Role) {
+		return c.Role
+	}
+	if c.Edit {
+		return CaseRoleEditor
+	}
+	return CaseRoleViewer
+}
+
+// RoleOf returns the role a user holds on the case, or an empty string when
+// they neither created it nor collaborate on it.
+func (c Case) RoleOf(userID primitive.ObjectID) string {
+	if userID.IsZero() {
+		return ""
+	}
+	if c.CreatorID == userID {
+		return CaseRoleOwner
+	}
+	for _, collaborator := range c.Collaborators {
+		if collaborator.ID == userID {
+			return collaborator.EffectiveRole()
+		}
+	}
+	return ""
+}
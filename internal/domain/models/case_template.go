@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CaseTemplate pre-seeds new cases with an agent, an action, opening messages and
+// tags. Templates without a TeamID are personal to their creator; team templates
+// are available to every member of the team.
+type CaseTemplate struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name      string             `json:"name" bson:"name"`
+	CreatorID primitive.ObjectID `json:"creator_id" bson:"creator_id"`
+	TeamID    primitive.ObjectID `json:"team_id" bson:"team_id,omitempty"`
+	AgentID   primitive.ObjectID `json:"agent_id" bson:"agent_id,omitempty"`
+	Action    string             `json:"action" bson:"action,omitempty"`
+	Messages  []Message          `json:"messages" bson:"messages"`
+	Tags      []string           `json:"tags" bson:"tags,omitempty"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+}
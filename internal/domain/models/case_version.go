@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CaseVersion is an immutable snapshot of a case taken after a mutation. The
+// snapshot holds the case fields; messages are referenced by ID and revision, and
+// read back from the messages themselves. Versions recorded before references
+// were introduced embed their messages in the snapshot instead.
+type CaseVersion struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CaseID       primitive.ObjectID `json:"case_id" bson:"case_id"`
+	Version      int                `json:"version" bson:"version"`
+	Action       string             `json:"action" bson:"action"`
+	ActorID      primitive.ObjectID `json:"actor_id" bson:"actor_id,omitempty"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+	MessageCount int                `json:"message_count" bson:"message_count"`
+	Snapshot     Case               `json:"snapshot" bson:"snapshot"`
+	Messages     []MessageRef       `json:"messages" bson:"messages,omitempty"`
+}
+
+// MessageRef points at a live message as it stood when a version was recorded:
+// Revision is the number of entries its history had at the time.
+type MessageRef struct {
+	ID       primitive.ObjectID `json:"id" bson:"id"`
+	Revision int                `json:"revision" bson:"revision"`
+}
+
+// Case mutations recorded in the version history.
+const (
+	CaseActionCreate                 = "create"
+	CaseActionUpdate                 = "update"
+	CaseActionAppendMessages         = "append_messages"
+	CaseActionEditMessage            = "edit_message"
+	CaseActionDeleteMessage          = "delete_message"
+	CaseActionAddCollaborator        = "add_collaborator"
+	CaseActionRemoveCollaborator     = "remove_collaborator"
+	CaseActionUpdateCollaboratorRole = "update_collaborator_role"
+	CaseActionDelete                 = "delete"
+	CaseActionRestore                = "restore"
+	CaseActionRestoreVersion         = "restore_version"
+	CaseActionTag                    = "tag"
+)
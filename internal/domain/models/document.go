@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Document describes a file uploaded to a case. The content itself lives in the
+// blob store under StorageKey and is shared by every document with the same
+// SHA-256 digest. A document removed from its case while messages still refer
+// to it is only marked as deleted so those messages keep working. The text
+// extracted from the content is kept with the metadata under Text; TextStatus
+// tells whether extraction has run and how it went.
+type Document struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CaseID      primitive.ObjectID `json:"case_id" bson:"case_id"`
+	UploaderID  primitive.ObjectID `json:"uploader_id" bson:"uploader_id"`
+	FileName    string             `json:"file_name" bson:"file_name"`
+	ContentType string             `json:"content_type" bson:"content_type"`
+	Size        int64              `json:"size" bson:"size"`
+	SHA256      string             `json:"sha256" bson:"sha256"`
+	StorageKey  string             `json:"-" bson:"storage_key"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+	DeletedAt   time.Time          `json:"deleted_at" bson:"deleted_at,omitempty"`
+	TextStatus  string             `json:"text_status" bson:"text_status,omitempty"`
+	Text        string             `json:"-" bson:"text,omitempty"`
+}
+
+// Text extraction outcomes. Documents uploaded before extraction existed have no
+// status until their text is first requested.
+const (
+	DocumentTextExtracted   = "extracted"
+	DocumentTextUnsupported = "unsupported"
+	DocumentTextFailed      = "failed"
+)
+
+// DocumentSearchResult is a document whose extracted text matched a search, with
+// its text score.
+type DocumentSearchResult struct {
+	Document `bson:",inline"`
+	Score    float64 `bson:"score"`
+}
+
+// DocumentBlob is the stored content behind one SHA-256 digest. RefCount counts
+// the documents sharing it; the content is removed when the last one goes. State
+// tells whether the content is known to be stored, and Generation changes each
+// time the record is turned into a tombstone or revived from one.
+type DocumentBlob struct {
+	SHA256      string    `json:"sha256" bson:"_id"`
+	StorageKey  string    `json:"storage_key" bson:"storage_key"`
+	ContentType string    `json:"content_type" bson:"content_type"`
+	Size        int64     `json:"size" bson:"size"`
+	RefCount    int64     `json:"ref_count" bson:"ref_count"`
+	State       string    `json:"state" bson:"state,omitempty"`
+	Generation  int64     `json:"generation" bson:"generation"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" bson:"updated_at,omitempty"`
+}
+
+// Document blob states. Blobs recorded before states existed have none and count
+// as stored.
+const (
+	DocumentBlobPending  = "pending"  // recorded, content not stored yet
+	DocumentBlobStored   = "stored"   // content stored
+	DocumentBlobDeleting = "deleting" // tombstone kept while the content is deleted
+)
+
+// DocumentPath is the document path under which messages refer to an uploaded
+// document. It resolves against the API base URL to the download endpoint.
+func DocumentPath(id primitive.ObjectID) string {
+	return "documents/" + id.Hex()
+}
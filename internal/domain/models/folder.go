@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Folder groups a user's cases. Folders nest; Ancestors lists the folder's
+// parents from the root down so a whole subtree can be matched in one query.
+type Folder struct {
+	ID        primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	OwnerID   primitive.ObjectID   `json:"owner_id" bson:"owner_id"`
+	Name      string               `json:"name" bson:"name"`
+	ParentID  primitive.ObjectID   `json:"parent_id" bson:"parent_id,omitempty"`
+	Ancestors []primitive.ObjectID `json:"ancestors" bson:"ancestors"`
+	CreatedAt time.Time            `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at" bson:"updated_at"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ShareLink grants access to a case through an unguessable token, without an
+// account. Only a hash of the token is stored.
+type ShareLink struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CaseID         primitive.ObjectID `json:"case_id" bson:"case_id"`
+	TokenHash      string             `json:"-" bson:"token_hash"`
+	Scope          string             `json:"scope" bson:"scope"`
+	PasswordHash   string             `json:"-" bson:"password_hash,omitempty"`
+	CreatedBy      primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedAt      time.Time          `json:"created_at" bson:"created_at"`
+	ExpiresAt      time.Time          `json:"expires_at" bson:"expires_at,omitempty"`
+	RevokedAt      time.Time          `json:"revoked_at" bson:"revoked_at,omitempty"`
+	RevokedBy      primitive.ObjectID `json:"revoked_by" bson:"revoked_by,omitempty"`
+	AccessCount    int64              `json:"access_count" bson:"access_count"`
+	LastAccessedAt time.Time          `json:"last_accessed_at" bson:"last_accessed_at,omitempty"`
+}
+
+// Share link scopes.
+const (
+	ShareScopeReadOnly = "read_only"
+)
+
+// Active reports whether the link can still be used at the given time.
+func (l ShareLink) Active(at time.Time) bool {
+	if !l.RevokedAt.IsZero() {
+		return false
+	}
+	return l.ExpiresAt.IsZero() || at.Before(l.ExpiresAt)
+}
@@ -0,0 +1,42 @@
+package models
+
+// ToolCall is a function or tool invocation an agent asked for. Arguments holds
+// the JSON arguments as produced by the model. CallID links the call to the
+// message carrying its result; function calls of older agents have none.
+type ToolCall struct {
+	CallID    string `json:"call_id" bson:"call_id,omitempty"`
+	Name      string `json:"name" bson:"name"`
+	Arguments string `json:"arguments" bson:"arguments"`
+}
+
+// ToolResult marks a message as the outcome of a tool invocation; the output
+// itself is the message content. DurationMS is nil when the runtime did not
+// report how long the invocation took.
+type ToolResult struct {
+	CallID     string `json:"call_id" bson:"call_id,omitempty"`
+	Name       string `json:"name" bson:"name,omitempty"`
+	Status     string `json:"status" bson:"status"`
+	DurationMS *int64 `json:"duration_ms" bson:"duration_ms,omitempty"`
+}
+
+// Tool invocation statuses. A call without a result is pending.
+const (
+	ToolStatusSuccess = "success"
+	ToolStatusError   = "error"
+	ToolStatusPending = "pending"
+)
+
+// ValidToolResultStatus reports whether a result can carry the status.
+func ValidToolResultStatus(status string) bool {
+	return status == ToolStatusSuccess || status == ToolStatusError
+}
+
+// IsToolCall reports whether the message asks for tool invocations.
+func (m Message) IsToolCall() bool {
+	return len(m.ToolCalls) > 0
+}
+
+// IsToolResult reports whether the message carries the outcome of a tool invocation.
+func (m Message) IsToolResult() bool {
+	return m.ToolResult != nil
+}
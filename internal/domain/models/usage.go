@@ -0,0 +1,21 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// TokenUsage is what generating a message took. Cost is worked out when the
+// message is stored, so later price changes leave past usage untouched.
+type TokenUsage struct {
+	Model            string  `json:"model" bson:"model,omitempty"`
+	PromptTokens     int64   `json:"prompt_tokens" bson:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens" bson:"completion_tokens"`
+	Cost             float64 `json:"cost" bson:"cost"`
+}
+
+// CaseUsage totals the token usage of the messages of a case.
+type CaseUsage struct {
+	CaseID           primitive.ObjectID `json:"case_id" bson:"_id"`
+	PromptTokens     int64              `json:"prompt_tokens" bson:"prompt_tokens"`
+	CompletionTokens int64              `json:"completion_tokens" bson:"completion_tokens"`
+	Cost             float64            `json:"cost" bson:"cost"`
+	Messages         int                `json:"messages" bson:"messages"`
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AnnotationRepository manages the annotations and bookmarks made on case messages.
+type AnnotationRepository struct {
+	annotationDAO *daos.AnnotationDAO
+	logger        logs.Logger
+}
+
+// NewAnnotationRepository creates a new instance of the annotation repository.
+func NewAnnotationRepository(annotationDAO *daos.AnnotationDAO, logger logs.Logger) *AnnotationRepository {
+	return &AnnotationRepository{
+		annotationDAO: annotationDAO,
+		logger:        logger,
+	}
+}
+
+// CreateAnnotation stores a new annotation.
+func (r *AnnotationRepository) CreateAnnotation(ctx context.Context, annotation *models.Annotation) error {
+	return r.annotationDAO.Create(ctx, annotation)
+}
+
+// GetAnnotationByID retrieves an annotation of a case.
+func (r *AnnotationRepository) GetAnnotationByID(ctx context.Context, caseID, id primitive.ObjectID) (models.Annotation, error) {
+	return r.annotationDAO.FindByID(ctx, caseID, id)
+}
+
+// GetVisibleAnnotations retrieves the annotations of a case a user may see, newest first.
+func (r *AnnotationRepository) GetVisibleAnnotations(ctx context.Context, caseID, userID primitive.ObjectID, filter models.AnnotationFilter) ([]models.Annotation, error) {
+	return r.annotationDAO.FindVisible(ctx, caseID, userID, filter)
+}
+
+// GetAnnotationsByAuthor retrieves the annotations a user made on the given cases, newest first.
+func (r *AnnotationRepository) GetAnnotationsByAuthor(ctx context.Context, authorID primitive.ObjectID, caseIDs []primitive.ObjectID, filter models.AnnotationFilter) ([]models.Annotation, error) {
+	if len(caseIDs) == 0 {
+		return nil, nil
+	}
+	return r.annotationDAO.FindByAuthor(ctx, authorID, caseIDs, filter)
+}
+
+// UpdateAnnotation sets fields of an annotation and returns the updated annotation.
+func (r *AnnotationRepository) UpdateAnnotation(ctx context.Context, caseID, id primitive.ObjectID, updates map[string]interface{}) (models.Annotation, error) {
+	r.logger.Info("Repository Level: Attempting to update annotation")
+	annotation, err := r.annotationDAO.Update(ctx, caseID, id, bson.M{"$set": updates})
+	if err != nil {
+		r.logger.Error("Repository Level: Failed to update annotation", err)
+		return annotation, err
+	}
+	r.logger.Info("Repository Level: Successfully updated annotation")
+	return annotation, nil
+}
+
+// DeleteAnnotation permanently removes an annotation of a case.
+func (r *AnnotationRepository) DeleteAnnotation(ctx context.Context, caseID, id primitive.ObjectID) error {
+	return r.annotationDAO.Delete(ctx, caseID, id)
+}
+
+// DeleteAnnotations permanently removes the annotations of a case.
+func (r *AnnotationRepository) DeleteAnnotations(ctx context.Context, caseID primitive.ObjectID) error {
+	return r.annotationDAO.DeleteByCaseID(ctx, caseID)
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CaseActivityRepository keeps the activity feed of cases.
+type CaseActivityRepository struct {
+	activityDAO *daos.CaseActivityDAO
+	logger      logs.Logger
+}
+
+// NewCaseActivityRepository creates a new instance of the case activity repository.
+func NewCaseActivityRepository(activityDAO *daos.CaseActivityDAO, logger logs.Logger) *CaseActivityRepository {
+	return &CaseActivityRepository{
+		activityDAO: activityDAO,
+		logger:      logger,
+	}
+}
+
+// RecordActivity stores an activity entry for a case, stamped with the current time.
+func (r *CaseActivityRepository) RecordActivity(ctx context.Context, caseID, actorID primitive.ObjectID, action string, fields []string) (models.CaseActivity, error) {
+	r.logger.Info("Repository Level: Attempting to record case activity")
+	activity := models.CaseActivity{
+		CaseID:    caseID,
+		ActorID:   actorID,
+		Action:    action,
+		Fields:    fields,
+		CreatedAt: time.Now(),
+	}
+	if err := r.activityDAO.Create(ctx, &activity); err != nil {
+		r.logger.Error("Repository Level: Failed to record case activity", err)
+		return models.CaseActivity{}, err
+	}
+	r.logger.Info("Repository Level: Successfully recorded case activity")
+	return activity, nil
+}
+
+// GetActivity retrieves a page of a case's activity, newest first.
+func (r *CaseActivityRepository) GetActivity(ctx context.Context, caseID primitive.ObjectID, filter models.CaseActivityFilter) ([]models.CaseActivity, error) {
+	return r.activityDAO.FindByCaseID(ctx, caseID, filter)
+}
+
+// DeleteActivity permanently removes the activity of a case.
+func (r *CaseActivityRepository) DeleteActivity(ctx context.Context, caseID primitive.ObjectID) error {
+	return r.activityDAO.DeleteByCaseID(ctx, caseID)
+}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CaseInvitationRepository manages pending case invitations and turns them into
+// collaborator entries once accepted.
+type CaseInvitationRepository struct {
+	invitationDAO *daos.CaseInvitationDAO
+	caseDAO       *daos.CaseDAO
+	logger        logs.Logger
+}
+
+// NewCaseInvitationRepository creates a new instance of the case invitation repository.
+func NewCaseInvitationRepository(invitationDAO *daos.CaseInvitationDAO, caseDAO *daos.CaseDAO, logger logs.Logger) *CaseInvitationRepository {
+	return &CaseInvitationRepository{
+		invitationDAO: invitationDAO,
+		caseDAO:       caseDAO,
+		logger:        logger,
+	}
+}
+
+// CreateInvitation stores a new invitation, replacing any earlier pending
+// invitation of the same email to the same case.
+func (r *CaseInvitationRepository) CreateInvitation(ctx context.Context, invitation *models.CaseInvitation) error {
+	r.logger.Info("Repository Level: Attempting to create case invitation")
+	if err := r.invitationDAO.DeletePending(ctx, invitation.CaseID, invitation.Email); err != nil {
+		r.logger.Error("Repository Level: Failed to replace pending case invitations", err)
+		return err
+	}
+	if err := r.invitationDAO.Create(ctx, invitation); err != nil {
+		r.logger.Error("Repository Level: Failed to create case invitation", err)
+		return err
+	}
+	r.logger.Info("Repository Level: Successfully created case invitation")
+	return nil
+}
+
+// GetInvitationByTokenHash retrieves the invitation issued for a token.
+func (r *CaseInvitationRepository) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (models.CaseInvitation, error) {
+	return r.invitationDAO.FindByTokenHash(ctx, tokenHash)
+}
+
+// GetPendingInvitationsForEmail retrieves the invitations addressed to an email
+// that can still be accepted.
+func (r *CaseInvitationRepository) GetPendingInvitationsForEmail(ctx context.Context, email string) ([]models.CaseInvitation, error) {
+	return r.invitationDAO.FindPendingByEmail(ctx, email, time.Now())
+}
+
+// GetPendingInvitations retrieves the invitations to a case that can still be accepted.
+func (r *CaseInvitationRepository) GetPendingInvitations(ctx context.Context, caseID primitive.ObjectID) ([]models.CaseInvitation, error) {
+	return r.invitationDAO.FindPendingByCaseID(ctx, caseID, time.Now())
+}
+
+// MarkInvitationAccepted claims an invitation for a user without adding them to
+// the case.
+func (r *CaseInvitationRepository) MarkInvitationAccepted(ctx context.Context, invitation models.CaseInvitation, userID primitive.ObjectID, acceptedAt time.Time) error {
+	return r.invitationDAO.MarkAccepted(ctx, invitation.ID, userID, acceptedAt)
+}
+
+// AcceptInvitation claims the invitation for the user, then adds them to the case
+// with the invited role, reporting whether they were added. Claiming first means a
+// token used twice at once adds one collaborator at most. A user who already
+// collaborates on the case keeps their current role. If the case is gone or in the
+// trash, the claim is released so the invitation can still be used later.
+func (r *CaseInvitationRepository) AcceptInvitation(ctx context.Context, invitation models.CaseInvitation, userID primitive.ObjectID, acceptedAt time.Time) (bool, error) {
+	r.logger.Info("Repository Level: Attempting to accept case invitation")
+	if err := r.invitationDAO.MarkAccepted(ctx, invitation.ID, userID, acceptedAt); err != nil {
+		r.logger.Error("Repository Level: Failed to mark case invitation as accepted", err)
+		return false, err
+	}
+	_, err := r.caseDAO.AddCollaborator(ctx, invitation.CaseID, models.Collaborators{
+		ID:   userID,
+		Role: invitation.Role,
+		Edit: models.CaseRoleAllows(invitation.Role, models.CaseRoleEditor),
+	})
+	if err == nil {
+		r.logger.Info("Repository Level: Successfully accepted case invitation")
+		return true, nil
+	}
+	if errors.Is(err, daos.ErrCollaboratorExists) {
+		// The case may not be live, or the user may already be on it.
+		caseModel, findErr := r.caseDAO.FindByID(ctx, invitation.CaseID)
+		if findErr == nil && caseModel.RoleOf(userID) != "" {
+			r.logger.Info("Repository Level: Successfully accepted case invitation of an existing collaborator")
+			return false, nil
+		}
+		err = findErr
+		if err == nil {
+			err = daos.ErrCollaboratorNotFound
+		}
+	}
+	r.logger.Error("Repository Level: Failed to add invited collaborator", err)
+	if unmarkErr := r.invitationDAO.UnmarkAccepted(ctx, invitation.ID, userID, acceptedAt); unmarkErr != nil {
+		r.logger.Error("Repository Level: Failed to release case invitation", unmarkErr)
+	}
+	return false, err
+}
+
+// RevokeInvitation deletes a pending invitation to a case.
+func (r *CaseInvitationRepository) RevokeInvitation(ctx context.Context, caseID, id primitive.ObjectID) error {
+	return r.invitationDAO.Delete(ctx, caseID, id)
+}
+
+// DeleteInvitations permanently removes every invitation to a case.
+func (r *CaseInvitationRepository) DeleteInvitations(ctx context.Context, caseID primitive.ObjectID) error {
+	return r.invitationDAO.DeleteByCaseID(ctx, caseID)
+}
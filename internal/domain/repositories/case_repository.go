@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
@@ -27,8 +28,8 @@ func (r *CaseRepository) GetCaseByID(ctx context.Context, id primitive.ObjectID)
 	return r.caseDAO.FindByID(ctx, id)
 }
 
-func (r *CaseRepository) GetCasesByCreatorID(ctx context.Context, creatorID primitive.ObjectID) ([]models.Case, error) {
-	return r.caseDAO.FindByCreatorID(ctx, creatorID)
+func (r *CaseRepository) GetCasesByCreatorID(ctx context.Context, creatorID primitive.ObjectID, filter models.CaseListFilter) ([]models.Case, error) {
+	return r.caseDAO.FindByCreatorID(ctx, creatorID, filter)
 }
 
 func (r *CaseRepository) CreateCase(ctx context.Context, caseModel models.Case) (*mongo.InsertOneResult, error) {
@@ -43,10 +44,50 @@ func (r *CaseRepository) DeleteCase(ctx context.Context, id primitive.ObjectID)
 	return r.caseDAO.Delete(ctx, id)
 }
 
-func (r *CaseRepository) AddCollaboratorToCase(ctx context.Context, id primitive.ObjectID, collaborator map[string]interface{}) (*mongo.UpdateResult, error) {
+func (r *CaseRepository) AddCollaboratorToCase(ctx context.Context, id primitive.ObjectID, collaborator models.Collaborators) (*mongo.UpdateResult, error) {
 	return r.caseDAO.AddCollaborator(ctx, id, collaborator)
 }
 
 func (r *CaseRepository) RemoveCollaboratorFromCase(ctx context.Context, id primitive.ObjectID, collaboratorID primitive.ObjectID) (*mongo.UpdateResult, error) {
 	return r.caseDAO.RemoveCollaborator(ctx, id, collaboratorID)
 }
+
+func (r *CaseRepository) UpdateCollaboratorRole(ctx context.Context, id, collaboratorID primitive.ObjectID, role string) error {
+	return r.caseDAO.UpdateCollaboratorRole(ctx, id, collaboratorID, role)
+}
+
+func (r *CaseRepository) GetAccessibleCases(ctx context.Context, userID primitive.ObjectID, agentID primitive.ObjectID) ([]models.Case, error) {
+	return r.caseDAO.FindAccessible(ctx, userID, agentID)
+}
+
+func (r *CaseRepository) SearchCaseNames(ctx context.Context, caseIDs []primitive.ObjectID, query string) ([]models.CaseSearchResult, error) {
+	return r.caseDAO.SearchByName(ctx, caseIDs, query)
+}
+
+func (r *CaseRepository) TrashCase(ctx context.Context, id primitive.ObjectID, deletedAt time.Time, deletedBy primitive.ObjectID) error {
+	return r.caseDAO.Trash(ctx, id, deletedAt, deletedBy)
+}
+
+func (r *CaseRepository) RestoreCase(ctx context.Context, id primitive.ObjectID, lastEdit time.Time) error {
+	return r.caseDAO.Restore(ctx, id, lastEdit)
+}
+
+func (r *CaseRepository) GetTrashedCaseByID(ctx context.Context, id primitive.ObjectID) (models.Case, error) {
+	return r.caseDAO.FindTrashedByID(ctx, id)
+}
+
+func (r *CaseRepository) GetTrashedCases(ctx context.Context, userID primitive.ObjectID) ([]models.Case, error) {
+	return r.caseDAO.FindTrashed(ctx, userID)
+}
+
+func (r *CaseRepository) GetCasesTrashedBefore(ctx context.Context, cutoff time.Time) ([]models.Case, error) {
+	return r.caseDAO.FindTrashedBefore(ctx, cutoff)
+}
+
+func (r *CaseRepository) GetCaseForks(ctx context.Context, caseID, userID primitive.ObjectID) ([]models.Case, error) {
+	return r.caseDAO.FindForks(ctx, caseID, userID)
+}
+
+func (r *CaseRepository) UpdateCaseTags(ctx context.Context, caseIDs []primitive.ObjectID, userID primitive.ObjectID, add, remove []string) ([]primitive.ObjectID, error) {
+	return r.caseDAO.UpdateTags(ctx, caseIDs, userID, add, remove)
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CaseTemplateRepository stores personal and team case templates.
+type CaseTemplateRepository struct {
+	templateDAO *daos.CaseTemplateDAO
+	teamDAO     *daos.TeamDAO
+	logger      logs.Logger
+}
+
+// NewCaseTemplateRepository creates a new instance of the case template repository.
+func NewCaseTemplateRepository(templateDAO *daos.CaseTemplateDAO, teamDAO *daos.TeamDAO, logger logs.Logger) *CaseTemplateRepository {
+	return &CaseTemplateRepository{
+		templateDAO: templateDAO,
+		teamDAO:     teamDAO,
+		logger:      logger,
+	}
+}
+
+// CreateTemplate stores a new case template.
+func (r *CaseTemplateRepository) CreateTemplate(ctx context.Context, template *models.CaseTemplate) error {
+	return r.templateDAO.Create(ctx, template)
+}
+
+// GetTemplateByID retrieves a case template by its ID.
+func (r *CaseTemplateRepository) GetTemplateByID(ctx context.Context, id primitive.ObjectID) (models.CaseTemplate, error) {
+	return r.templateDAO.FindByID(ctx, id)
+}
+
+// GetTemplatesForUser retrieves the personal templates of a user and the
+// templates of every team they belong to.
+func (r *CaseTemplateRepository) GetTemplatesForUser(ctx context.Context, userID primitive.ObjectID) ([]models.CaseTemplate, error) {
+	r.logger.Info("Repository Level: Attempting to retrieve case templates for user")
+	teams, err := r.teamDAO.GetTeamsByUserID(ctx, userID)
+	if err != nil {
+		r.logger.Error("Repository Level: Failed to retrieve user teams", err)
+		return nil, err
+	}
+	teamIDs := make([]primitive.ObjectID, len(teams))
+	for i, team := range teams {
+		teamIDs[i] = team.ID
+	}
+	templates, err := r.templateDAO.FindAvailable(ctx, userID, teamIDs)
+	if err != nil {
+		r.logger.Error("Repository Level: Failed to retrieve case templates", err)
+		return nil, err
+	}
+	r.logger.Info("Repository Level: Successfully retrieved case templates for user")
+	return templates, nil
+}
+
+// GetTeamsForUser retrieves the teams a user administers or belongs to.
+func (r *CaseTemplateRepository) GetTeamsForUser(ctx context.Context, userID primitive.ObjectID) ([]models.Team, error) {
+	return r.teamDAO.GetTeamsByUserID(ctx, userID)
+}
+
+// UpdateTemplate applies an update document to a case template.
+func (r *CaseTemplateRepository) UpdateTemplate(ctx context.Context, id primitive.ObjectID, updates bson.M) error {
+	return r.templateDAO.Update(ctx, id, updates)
+}
+
+// DeleteTemplate deletes a case template.
+func (r *CaseTemplateRepository) DeleteTemplate(ctx context.Context, id primitive.ObjectID) error {
+	return r.templateDAO.Delete(ctx, id)
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CaseVersionRepository keeps the immutable version history of cases.
+type CaseVersionRepository struct {
+	versionDAO *daos.CaseVersionDAO
+	caseDAO    *daos.CaseDAO
+	logger     logs.Logger
+}
+
+// NewCaseVersionRepository creates a new instance of the case version repository.
+func NewCaseVersionRepository(versionDAO *daos.CaseVersionDAO, caseDAO *daos.CaseDAO, logger logs.Logger) *CaseVersionRepository {
+	return &CaseVersionRepository{
+		versionDAO: versionDAO,
+		caseDAO:    caseDAO,
+		logger:     logger,
+	}
+}
+
+// CreateVersion reserves the next version number of a case and stores the
+// snapshot of its fields under it, together with references to its messages.
+func (r *CaseVersionRepository) CreateVersion(ctx context.Context, snapshot models.Case, messages []models.MessageRef, action string, actorID primitive.ObjectID) (models.CaseVersion, error) {
+	r.logger.Info("Repository Level: Attempting to create case version")
+	number, err := r.caseDAO.ReserveVersion(ctx, snapshot.ID)
+	if err != nil {
+		r.logger.Error("Repository Level: Failed to reserve case version", err)
+		return models.CaseVersion{}, err
+	}
+	snapshot.VersionSeq = number
+	snapshot.Messages = nil
+	version := models.CaseVersion{
+		CaseID:       snapshot.ID,
+		Version:      number,
+		Action:       action,
+		ActorID:      actorID,
+		CreatedAt:    time.Now(),
+		MessageCount: len(messages),
+		Snapshot:     snapshot,
+		Messages:     messages,
+	}
+	if err := r.versionDAO.Create(ctx, &version); err != nil {
+		r.logger.Error("Repository Level: Failed to store case version", err)
+		return models.CaseVersion{}, err
+	}
+	r.logger.Info("Repository Level: Successfully created case version")
+	return version, nil
+}
+
+// GetVersions retrieves the version summaries of a case, newest first.
+func (r *CaseVersionRepository) GetVersions(ctx context.Context, caseID primitive.ObjectID) ([]models.CaseVersion, error) {
+	return r.versionDAO.FindByCaseID(ctx, caseID)
+}
+
+// GetVersion retrieves a single version of a case with its snapshot.
+func (r *CaseVersionRepository) GetVersion(ctx context.Context, caseID primitive.ObjectID, version int) (models.CaseVersion, error) {
+	return r.versionDAO.FindByVersion(ctx, caseID, version)
+}
+
+// DeleteVersions permanently removes the version history of a case.
+func (r *CaseVersionRepository) DeleteVersions(ctx context.Context, caseID primitive.ObjectID) error {
+	return r.versionDAO.DeleteByCaseID(ctx, caseID)
+}
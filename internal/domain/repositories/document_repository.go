@@ -0,0 +1,311 @@
+package repositories
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/storage"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// blobTombstoneTTL is how long uploads wait for the content they share to be
+	// deleted before taking it over, in case the deletion was interrupted.
+	blobTombstoneTTL = 10 * time.Minute
+	// blobRetryInterval is how often an upload checks whether a deletion finished.
+	blobRetryInterval = 100 * time.Millisecond
+)
+
+// DocumentRepository keeps uploaded documents: their metadata in the database and
+// their content in the blob store. Content is stored once per SHA-256 digest and
+// reference counted across the documents that share it.
+type DocumentRepository struct {
+	documentDAO *daos.DocumentDAO
+	blobDAO     *daos.DocumentBlobDAO
+	messageDAO  *daos.MessageDAO
+	blobs       storage.BlobStore
+	logger      logs.Logger
+}
+
+// NewDocumentRepository creates a new instance of the document repository.
+func NewDocumentRepository(documentDAO *daos.DocumentDAO, blobDAO *daos.DocumentBlobDAO, messageDAO *daos.MessageDAO, blobs storage.BlobStore, logger logs.Logger) *DocumentRepository {
+	return &DocumentRepository{
+		documentDAO: documentDAO,
+		blobDAO:     blobDAO,
+		messageDAO:  messageDAO,
+		blobs:       blobs,
+		logger:      logger,
+	}
+}
+
+// StoreDocument records a new document, filling in its size, SHA-256 digest and
+// storage key. The content is spooled to a temporary file to compute the digest
+// and only written to the blob store when it is not known to be stored yet.
+func (r *DocumentRepository) StoreDocument(ctx context.Context, document *models.Document, content io.Reader) error {
+	r.logger.Info("Repository Level: Attempting to store document")
+	spool, err := os.CreateTemp("", "document-*")
+	if err != nil {
+		r.logger.Error("Repository Level: Failed to create document spool file", err)
+		return err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	digest := sha256.New()
+	size, err := io.Copy(io.MultiWriter(spool, digest), content)
+	if err != nil {
+		r.logger.Error("Repository Level: Failed to read document content", err)
+		return err
+	}
+	document.Size = size
+	document.SHA256 = hex.EncodeToString(digest.Sum(nil))
+	document.StorageKey = blobKey(document.SHA256)
+
+	pending, err := r.acquireBlob(ctx, *document, time.Now())
+	if err != nil {
+		r.logger.Error("Repository Level: Failed to reference document blob", err)
+		return err
+	}
+	if pending {
+		// The document is only recorded once its content is known to be stored,
+		// so uploads of content still pending store it too.
+		if _, err := spool.Seek(0, io.SeekStart); err != nil {
+			r.logger.Error("Repository Level: Failed to rewind document spool file", err)
+			r.releaseBlob(ctx, *document)
+			return err
+		}
+		if err := r.blobs.Put(ctx, document.StorageKey, spool, document.ContentType); err != nil {
+			r.logger.Error("Repository Level: Failed to store document content", err)
+			r.releaseBlob(ctx, *document)
+			return err
+		}
+		if err := r.blobDAO.MarkStored(ctx, document.SHA256, time.Now()); err != nil {
+			// Later uploads of the content store it again until it is marked.
+			r.logger.Error("Repository Level: Failed to mark document blob as stored", err)
+		}
+	}
+
+	if document.ID.IsZero() {
+		document.ID = primitive.NewObjectID()
+	}
+	if err := r.documentDAO.Create(ctx, document); err != nil {
+		r.logger.Error("Repository Level: Failed to store document metadata", err)
+		r.releaseBlob(ctx, *document)
+		return err
+	}
+	r.logger.Info("Repository Level: Successfully stored document")
+	return nil
+}
+
+// CopyDocument records a copy of a document on another case. The copy shares the
+// content of the original and stays marked as deleted if the original was.
+func (r *DocumentRepository) CopyDocument(ctx context.Context, document models.Document, caseID, uploaderID primitive.ObjectID) (models.Document, error) {
+	r.logger.Info("Repository Level: Attempting to copy document")
+	copied := document
+	copied.ID = primitive.NewObjectID()
+	copied.CaseID = caseID
+	copied.UploaderID = uploaderID
+	copied.CreatedAt = time.Now()
+	if copied.TextStatus == models.DocumentTextExtracted {
+		// Metadata is loaded without its text; an unreadable text is extracted
+		// again when the copy's text is first requested.
+		original, err := r.documentDAO.FindWithText(ctx, document.ID)
+		if err != nil {
+			r.logger.Warn("Repository Level: Text of copied document not found")
+			copied.TextStatus = ""
+		}
+		copied.Text = original.Text
+	}
+	if copied.StorageKey == blobKey(copied.SHA256) {
+		pending, err := r.acquireBlob(ctx, copied, copied.CreatedAt)
+		if err != nil {
+			r.logger.Error("Repository Level: Failed to reference document blob", err)
+			return models.Document{}, err
+		}
+		if pending {
+			// The original was only recorded once the content was stored.
+			if err := r.blobDAO.MarkStored(ctx, copied.SHA256, copied.CreatedAt); err != nil {
+				r.logger.Error("Repository Level: Failed to mark document blob as stored", err)
+			}
+		}
+	}
+	if err := r.documentDAO.Create(ctx, &copied); err != nil {
+		r.logger.Error("Repository Level: Failed to store document copy", err)
+		r.releaseBlob(ctx, copied)
+		return models.Document{}, err
+	}
+	r.logger.Info("Repository Level: Successfully copied document")
+	return copied, nil
+}
+
+// GetDocumentByID retrieves the metadata of a document.
+func (r *DocumentRepository) GetDocumentByID(ctx context.Context, id primitive.ObjectID) (models.Document, error) {
+	return r.documentDAO.FindByID(ctx, id)
+}
+
+// GetCaseDocuments retrieves the metadata of the documents of a case, newest
+// first, optionally including those removed from the case.
+func (r *DocumentRepository) GetCaseDocuments(ctx context.Context, caseID primitive.ObjectID, includeDeleted bool) ([]models.Document, error) {
+	return r.documentDAO.FindByCaseID(ctx, caseID, includeDeleted)
+}
+
+// GetDocumentWithText retrieves a document together with its extracted text.
+func (r *DocumentRepository) GetDocumentWithText(ctx context.Context, id primitive.ObjectID) (models.Document, error) {
+	return r.documentDAO.FindWithText(ctx, id)
+}
+
+// GetExtractedText returns the text already extracted from content with the given
+// digest by any document sharing it, and whether there was one.
+func (r *DocumentRepository) GetExtractedText(ctx context.Context, sha256 string) (string, bool, error) {
+	document, err := r.documentDAO.FindExtractedBySHA256(ctx, sha256)
+	if errors.Is(err, daos.ErrDocumentNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return document.Text, true, nil
+}
+
+// SetDocumentText stores the outcome of the text extraction of a document.
+func (r *DocumentRepository) SetDocumentText(ctx context.Context, id primitive.ObjectID, status, text string) error {
+	return r.documentDAO.SetText(ctx, id, status, text)
+}
+
+// SearchDocuments finds live documents of the given cases whose text matches a
+// text search, most relevant first.
+func (r *DocumentRepository) SearchDocuments(ctx context.Context, caseIDs []primitive.ObjectID, query string, limit int) ([]models.DocumentSearchResult, error) {
+	return r.documentDAO.Search(ctx, caseIDs, query, int64(limit))
+}
+
+// OpenDocument returns a reader over the content of a document. The caller closes it.
+func (r *DocumentRepository) OpenDocument(ctx context.Context, document models.Document) (io.ReadCloser, error) {
+	return r.blobs.Open(ctx, document.StorageKey)
+}
+
+// DeleteDocument removes a document from its case. While live messages still
+// refer to it the document is only marked as deleted; otherwise it is removed and
+// its content released.
+func (r *DocumentRepository) DeleteDocument(ctx context.Context, caseID, id primitive.ObjectID) error {
+	r.logger.Info("Repository Level: Attempting to delete document")
+	document, err := r.documentDAO.FindByID(ctx, id)
+	if err != nil {
+		r.logger.Error("Repository Level: Failed to retrieve document", err)
+		return err
+	}
+	if document.CaseID != caseID || !document.DeletedAt.IsZero() {
+		return daos.ErrDocumentNotFound
+	}
+	references, err := r.messageDAO.CountByDocumentID(ctx, id)
+	if err != nil {
+		r.logger.Error("Repository Level: Failed to count document references", err)
+		return err
+	}
+	if references > 0 {
+		if err := r.documentDAO.SoftDelete(ctx, caseID, id, time.Now()); err != nil {
+			r.logger.Error("Repository Level: Failed to soft-delete document", err)
+			return err
+		}
+		r.logger.Info("Repository Level: Successfully soft-deleted document still referenced by messages")
+		return nil
+	}
+	if err := r.documentDAO.Delete(ctx, caseID, id); err != nil {
+		r.logger.Error("Repository Level: Failed to delete document metadata", err)
+		return err
+	}
+	r.releaseBlob(ctx, document)
+	r.logger.Info("Repository Level: Successfully deleted document")
+	return nil
+}
+
+// DeleteDocuments permanently removes every document of a case, releasing their content.
+func (r *DocumentRepository) DeleteDocuments(ctx context.Context, caseID primitive.ObjectID) error {
+	r.logger.Info("Repository Level: Attempting to delete case documents")
+	documents, err := r.documentDAO.FindByCaseID(ctx, caseID, true)
+	if err != nil {
+		r.logger.Error("Repository Level: Failed to retrieve case documents", err)
+		return err
+	}
+	if err := r.documentDAO.DeleteByCaseID(ctx, caseID); err != nil {
+		r.logger.Error("Repository Level: Failed to delete case documents", err)
+		return err
+	}
+	for _, document := range documents {
+		r.releaseBlob(ctx, document)
+	}
+	r.logger.Info("Repository Level: Successfully deleted case documents")
+	return nil
+}
+
+// acquireBlob takes a reference on the content of a document, reporting whether
+// the content is pending and must be stored by the caller. While the content is
+// being deleted it waits for the deletion to finish, and takes the content over
+// if the deletion has not finished within blobTombstoneTTL.
+func (r *DocumentRepository) acquireBlob(ctx context.Context, document models.Document, acquiredAt time.Time) (bool, error) {
+	blob := models.DocumentBlob{
+		SHA256:      document.SHA256,
+		StorageKey:  document.StorageKey,
+		ContentType: document.ContentType,
+		Size:        document.Size,
+		CreatedAt:   acquiredAt,
+	}
+	for {
+		pending, err := r.blobDAO.Acquire(ctx, blob)
+		if !errors.Is(err, daos.ErrDocumentBlobDeleting) {
+			return pending, err
+		}
+		revived, err := r.blobDAO.Revive(ctx, blob, time.Now().Add(-blobTombstoneTTL))
+		if err != nil || revived {
+			return revived, err
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(blobRetryInterval):
+		}
+	}
+}
+
+// releaseBlob drops the reference a document holds on its content and deletes the
+// content once nothing refers to it. Documents stored before content was shared
+// own their blob outright. The blob record stays behind as a tombstone until the
+// content is gone, so a new upload of the same content cannot be deleted with it.
+// Failures only leave an unreachable blob behind, so they are logged.
+func (r *DocumentRepository) releaseBlob(ctx context.Context, document models.Document) {
+	if document.StorageKey != blobKey(document.SHA256) {
+		r.deleteBlob(ctx, document.StorageKey)
+		return
+	}
+	tombstone, released, err := r.blobDAO.Release(ctx, document.SHA256, time.Now())
+	if err != nil {
+		r.logger.Error("Repository Level: Failed to release document blob", err)
+		return
+	}
+	if !released || !r.deleteBlob(ctx, document.StorageKey) {
+		return
+	}
+	if err := r.blobDAO.Remove(ctx, tombstone); err != nil {
+		r.logger.Error("Repository Level: Failed to remove document blob tombstone", err)
+	}
+}
+
+func (r *DocumentRepository) deleteBlob(ctx context.Context, key string) bool {
+	if err := r.blobs.Delete(ctx, key); err != nil {
+		r.logger.Error("Repository Level: Failed to delete document content", err)
+		return false
+	}
+	return true
+}
+
+// blobKey is the blob store key of the content with the given SHA-256 digest.
+func blobKey(sha256 string) string {
+	return "blobs/sha256/" + sha256
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FolderRepository manages the folder tree of each user.
+type FolderRepository struct {
+	folderDAO daos.FolderDAOInterface
+	caseDAO   *daos.CaseDAO
+	logger    logs.Logger
+}
+
+// NewFolderRepository creates a new instance of the folder repository.
+func NewFolderRepository(folderDAO daos.FolderDAOInterface, caseDAO *daos.CaseDAO, logger logs.Logger) *FolderRepository {
+	return &FolderRepository{
+		folderDAO: folderDAO,
+		caseDAO:   caseDAO,
+		logger:    logger,
+	}
+}
+
+// CreateFolder stores a new folder.
+func (r *FolderRepository) CreateFolder(ctx context.Context, folder *models.Folder) error {
+	return r.folderDAO.Create(ctx, folder)
+}
+
+// GetFolderByID retrieves a folder by its ID.
+func (r *FolderRepository) GetFolderByID(ctx context.Context, id primitive.ObjectID) (models.Folder, error) {
+	return r.folderDAO.FindByID(ctx, id)
+}
+
+// GetFoldersByOwnerID retrieves every folder of a user.
+func (r *FolderRepository) GetFoldersByOwnerID(ctx context.Context, ownerID primitive.ObjectID) ([]models.Folder, error) {
+	return r.folderDAO.FindByOwnerID(ctx, ownerID)
+}
+
+// GetSubtreeIDs returns a folder's ID followed by the IDs of every folder below it.
+func (r *FolderRepository) GetSubtreeIDs(ctx context.Context, id primitive.ObjectID) ([]primitive.ObjectID, error) {
+	descendants, err := r.folderDAO.FindDescendantIDs(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return append([]primitive.ObjectID{id}, descendants...), nil
+}
+
+// UpdateFolder applies an update document to a folder.
+func (r *FolderRepository) UpdateFolder(ctx context.Context, id primitive.ObjectID, updates bson.M) error {
+	return r.folderDAO.Update(ctx, id, updates)
+}
+
+// MoveFolder places a folder under a new parent, or at the root when parent is
+// nil, and rewrites the ancestors of the whole subtree accordingly.
+func (r *FolderRepository) MoveFolder(ctx context.Context, folder models.Folder, parent *models.Folder) (models.Folder, error) {
+	r.logger.Info("Repository Level: Attempting to move folder")
+	update := bson.M{"$unset": bson.M{"parent_id": ""}, "$set": bson.M{"ancestors": []primitive.ObjectID{}}}
+	ancestors := []primitive.ObjectID{}
+	folder.ParentID = primitive.NilObjectID
+	if parent != nil {
+		ancestors = append(append(ancestors, parent.Ancestors...), parent.ID)
+		folder.ParentID = parent.ID
+		update = bson.M{"$set": bson.M{"parent_id": parent.ID, "ancestors": ancestors}}
+	}
+	if err := r.folderDAO.Update(ctx, folder.ID, update); err != nil {
+		r.logger.Error("Repository Level: Failed to move folder", err)
+		return folder, err
+	}
+	if err := r.folderDAO.ReplaceAncestors(ctx, folder.ID, folder.Ancestors, ancestors); err != nil {
+		r.logger.Error("Repository Level: Failed to update subfolder ancestors", err)
+		return folder, err
+	}
+	folder.Ancestors = ancestors
+	r.logger.Info("Repository Level: Successfully moved folder")
+	return folder, nil
+}
+
+// DeleteFolder removes a folder. Its subfolders and cases move up to its parent,
+// or to the root for a top-level folder.
+func (r *FolderRepository) DeleteFolder(ctx context.Context, folder models.Folder) error {
+	r.logger.Info("Repository Level: Attempting to delete folder")
+	if err := r.folderDAO.Reparent(ctx, folder.ID, folder.ParentID); err != nil {
+		r.logger.Error("Repository Level: Failed to move subfolders", err)
+		return err
+	}
+	if err := r.caseDAO.MoveToFolder(ctx, folder.ID, folder.ParentID); err != nil {
+		r.logger.Error("Repository Level: Failed to move folder cases", err)
+		return err
+	}
+	if err := r.folderDAO.Delete(ctx, folder.ID); err != nil {
+		r.logger.Error("Repository Level: Failed to delete folder", err)
+		return err
+	}
+	r.logger.Info("Repository Level: Successfully deleted folder")
+	return nil
+}
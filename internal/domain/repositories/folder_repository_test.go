@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...zap.Field)        {}
+func (nopLogger) Info(string, ...zap.Field)         {}
+func (nopLogger) Warn(string, ...zap.Field)         {}
+func (nopLogger) Error(string, error, ...zap.Field) {}
+func (nopLogger) Sync()                             {}
+
+// memoryFolderDAO keeps folders in memory, applying the updates MoveFolder
+// sends the way MongoDB would.
+type memoryFolderDAO struct {
+	folders map[primitive.ObjectID]*models.Folder
+}
+
+func (d *memoryFolderDAO) EnsureIndexes(context.Context) error { return nil }
+
+func (d *memoryFolderDAO) Create(_ context.Context, folder *models.Folder) error {
+	stored := *folder
+	d.folders[folder.ID] = &stored
+	return nil
+}
+
+func (d *memoryFolderDAO) FindByID(_ context.Context, id primitive.ObjectID) (models.Folder, error) {
+	folder, ok := d.folders[id]
+	if !ok {
+		return models.Folder{}, daos.ErrFolderNotFound
+	}
+	return *folder, nil
+}
+
+func (d *memoryFolderDAO) FindByOwnerID(context.Context, primitive.ObjectID) ([]models.Folder, error) {
+	return nil, nil
+}
+
+func (d *memoryFolderDAO) FindDescendantIDs(context.Context, primitive.ObjectID) ([]primitive.ObjectID, error) {
+	return nil, nil
+}
+
+func (d *memoryFolderDAO) Update(_ context.Context, id primitive.ObjectID, updates bson.M) error {
+	folder, ok := d.folders[id]
+	if !ok {
+		return daos.ErrFolderNotFound
+	}
+	if set, ok := updates["$set"].(bson.M); ok {
+		if parentID, ok := set["parent_id"].(primitive.ObjectID); ok {
+			folder.ParentID = parentID
+		}
+		if ancestors, ok := set["ancestors"].([]primitive.ObjectID); ok {
+			folder.Ancestors = slices.Clone(ancestors)
+		}
+	}
+	if unset, ok := updates["$unset"].(bson.M); ok {
+		if _, ok := unset["parent_id"]; ok {
+			folder.ParentID = primitive.NilObjectID
+		}
+	}
+	return nil
+}
+
+// ReplaceAncestors mirrors the $pullAll of the old ancestors followed by the
+// $push of the new ones at position 0 on every folder below id.
+func (d *memoryFolderDAO) ReplaceAncestors(_ context.Context, id primitive.ObjectID, oldAncestors, newAncestors []primitive.ObjectID) error {
+	for _, folder := range d.folders {
+		if !slices.Contains(folder.Ancestors, id) {
+			continue
+		}
+		kept := slices.DeleteFunc(slices.Clone(folder.Ancestors), func(ancestor primitive.ObjectID) bool {
+			return slices.Contains(oldAncestors, ancestor)
+		})
+		folder.Ancestors = append(slices.Clone(newAncestors), kept...)
+	}
+	return nil
+}
+
+func (d *memoryFolderDAO) Reparent(context.Context, primitive.ObjectID, primitive.ObjectID) error {
+	return nil
+}
+
+func (d *memoryFolderDAO) Delete(context.Context, primitive.ObjectID) error { return nil }
+
+func TestMoveFolderRewritesSubtreeAncestors(t *testing.T) {
+	// a
+	// └─ b
+	//    └─ c
+	//       └─ d
+	// e
+	// └─ f
+	a, b, c, d, e, f := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(),
+		primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	tree := map[primitive.ObjectID][]primitive.ObjectID{
+		a: {},
+		b: {a},
+		c: {a, b},
+		d: {a, b, c},
+		e: {},
+		f: {e},
+	}
+	names := map[primitive.ObjectID]string{a: "a", b: "b", c: "c", d: "d", e: "e", f: "f"}
+
+	tests := []struct {
+		name   string
+		folder primitive.ObjectID
+		parent primitive.ObjectID
+		wanted map[primitive.ObjectID][]primitive.ObjectID
+	}{
+		{
+			name:   "into another tree",
+			folder: b,
+			parent: f,
+			wanted: map[primitive.ObjectID][]primitive.ObjectID{
+				a: {}, b: {e, f}, c: {e, f, b}, d: {e, f, b, c}, e: {}, f: {e},
+			},
+		},
+		{
+			name:   "to the root",
+			folder: c,
+			wanted: map[primitive.ObjectID][]primitive.ObjectID{
+				a: {}, b: {a}, c: {}, d: {c}, e: {}, f: {e},
+			},
+		},
+		{
+			name:   "root folder under a deep folder",
+			folder: e,
+			parent: d,
+			wanted: map[primitive.ObjectID][]primitive.ObjectID{
+				a: {}, b: {a}, c: {a, b}, d: {a, b, c}, e: {a, b, c, d}, f: {a, b, c, d, e},
+			},
+		},
+		{
+			name:   "up to a shallower parent",
+			folder: d,
+			parent: a,
+			wanted: map[primitive.ObjectID][]primitive.ObjectID{
+				a: {}, b: {a}, c: {a, b}, d: {a}, e: {}, f: {e},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dao := &memoryFolderDAO{folders: map[primitive.ObjectID]*models.Folder{}}
+			for id, ancestors := range tree {
+				folder := models.Folder{ID: id, Ancestors: slices.Clone(ancestors)}
+				if len(ancestors) > 0 {
+					folder.ParentID = ancestors[len(ancestors)-1]
+				}
+				dao.folders[id] = &folder
+			}
+			repo := NewFolderRepository(dao, nil, nopLogger{})
+
+			var parent *models.Folder
+			if !tt.parent.IsZero() {
+				stored := *dao.folders[tt.parent]
+				parent = &stored
+			}
+			moved, err := repo.MoveFolder(context.Background(), *dao.folders[tt.folder], parent)
+			if err != nil {
+				t.Fatalf("MoveFolder() error = %v", err)
+			}
+			if moved.ParentID != tt.parent {
+				t.Errorf("moved parent = %s, want %s", names[moved.ParentID], names[tt.parent])
+			}
+			if !reflect.DeepEqual(moved.Ancestors, tt.wanted[tt.folder]) {
+				t.Errorf("moved ancestors = %v, want %v", moved.Ancestors, tt.wanted[tt.folder])
+			}
+			for id, wanted := range tt.wanted {
+				folder := dao.folders[id]
+				if !reflect.DeepEqual(folder.Ancestors, wanted) {
+					t.Errorf("ancestors of %s = %v, want %v", names[id], folder.Ancestors, wanted)
+				}
+				wantedParent := primitive.NilObjectID
+				if len(wanted) > 0 {
+					wantedParent = wanted[len(wanted)-1]
+				}
+				if folder.ParentID != wantedParent {
+					t.Errorf("parent of %s = %s, want %s", names[id], names[folder.ParentID], names[wantedParent])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MessageRepository stores case messages in their own collection, ordered by a
+// per-case sequence number reserved on the case document.
+type MessageRepository struct {
+	messageDAO daos.MessageDAOInterface
+	caseDAO    *daos.CaseDAO
+	logger     logs.Logger
+}
+
+// NewMessageRepository creates a new instance of the message repository.
+func NewMessageRepository(messageDAO daos.MessageDAOInterface, caseDAO *daos.CaseDAO, logger logs.Logger) *MessageRepository {
+	return &MessageRepository{
+		messageDAO: messageDAO,
+		caseDAO:    caseDAO,
+		logger:     logger,
+	}
+}
+
+// AppendMessages reserves sequence numbers on the case and stores the messages under them.
+// The case's last edit time is updated in the same operation as the reservation.
+func (r *MessageRepository) AppendMessages(ctx context.Context, caseID primitive.ObjectID, messages []models.Message, lastEdit time.Time) ([]models.Message, error) {
+	r.logger.Info("Repository Level: Attempting to append messages")
+	first, err := r.caseDAO.ReserveMessageSequence(ctx, caseID, len(messages), lastEdit)
+	if err != nil {
+		r.logger.Error("Repository Level: Failed to reserve message sequence", err)
+		return nil, err
+	}
+	for i := range messages {
+		messages[i].CaseID = caseID
+		messages[i].Sequence = first + i
+	}
+	if err := r.messageDAO.InsertMany(ctx, messages); err != nil {
+		r.logger.Error("Repository Level: Failed to insert messages", err)
+		return nil, err
+	}
+	r.logger.Info("Repository Level: Successfully appended messages")
+	return messages, nil
+}
+
+// SyncMessages brings the messages of a case in line with the given ones without
+// removing any stored message, so histories, threads and annotations survive.
+// Live messages missing from the list are soft-deleted, listed messages whose
+// content differs or that were deleted are restored to the listed content, and
+// listed messages the case does not have yet are appended.
+func (r *MessageRepository) SyncMessages(ctx context.Context, caseID primitive.ObjectID, messages []models.Message, changedAt time.Time, changedBy primitive.ObjectID) error {
+	r.logger.Info("Repository Level: Attempting to sync messages")
+	stored, err := r.messageDAO.FindAllByCaseID(ctx, caseID)
+	if err != nil {
+		r.logger.Error("Repository Level: Failed to retrieve messages", err)
+		return err
+	}
+	existing := make(map[primitive.ObjectID]models.Message, len(stored))
+	for _, message := range stored {
+		existing[message.ID] = message
+	}
+	listed := make(map[primitive.ObjectID]bool, len(messages))
+	var added []models.Message
+	for _, message := range messages {
+		listed[message.ID] = true
+		current, ok := existing[message.ID]
+		if !ok {
+			added = append(added, message)
+			continue
+		}
+		if current.DeletedAt.IsZero() && current.Content == message.Content && current.DocumentPath == message.DocumentPath {
+			continue
+		}
+		if _, err := r.messageDAO.Restore(ctx, caseID, message.ID, message.Content, message.DocumentPath, changedAt, changedBy); err != nil {
+			r.logger.Error("Repository Level: Failed to restore message", err)
+			return err
+		}
+	}
+	for _, message := range stored {
+		if listed[message.ID] || !message.DeletedAt.IsZero() {
+			continue
+		}
+		if _, err := r.messageDAO.SoftDelete(ctx, caseID, message.ID, changedAt, changedBy); err != nil && !errors.Is(err, daos.ErrMessageNotFound) {
+			r.logger.Error("Repository Level: Failed to delete message", err)
+			return err
+		}
+	}
+	if len(added) > 0 {
+		if _, err := r.AppendMessages(ctx, caseID, added, changedAt); err != nil {
+			return err
+		}
+	}
+	r.logger.Info("Repository Level: Successfully synced messages")
+	return nil
+}
+
+// GetMessagesByCaseID retrieves every message of a case in sequence order.
+func (r *MessageRepository) GetMessagesByCaseID(ctx context.Context, caseID primitive.ObjectID) ([]models.Message, error) {
+	return r.messageDAO.FindByCaseID(ctx, caseID)
+}
+
+// GetAllMessagesByCaseID retrieves every message of a case, soft-deleted ones included.
+func (r *MessageRepository) GetAllMessagesByCaseID(ctx context.Context, caseID primitive.ObjectID) ([]models.Message, error) {
+	return r.messageDAO.FindAllByCaseID(ctx, caseID)
+}
+
+// GetMessageRefs retrieves the IDs and revision numbers of the live messages of a case.
+func (r *MessageRepository) GetMessageRefs(ctx context.Context, caseID primitive.ObjectID) ([]models.MessageRef, error) {
+	return r.messageDAO.FindRefs(ctx, caseID)
+}
+
+// GetMessagesByCaseIDs retrieves the messages of several cases grouped by case ID.
+func (r *MessageRepository) GetMessagesByCaseIDs(ctx context.Context, caseIDs []primitive.ObjectID) (map[primitive.ObjectID][]models.Message, error) {
+	messages, err := r.messageDAO.FindByCaseIDs(ctx, caseIDs)
+	if err != nil {
+		return nil, err
+	}
+	grouped := make(map[primitive.ObjectID][]models.Message, len(caseIDs))
+	for _, message := range messages {
+		grouped[message.CaseID] = append(grouped[message.CaseID], message)
+	}
+	return grouped, nil
+}
+
+// GetMessagesBefore retrieves up to limit messages with a sequence lower than before,
+// in ascending order. With mainLine set, thread replies are left out, here and in
+// the other paging methods.
+func (r *MessageRepository) GetMessagesBefore(ctx context.Context, caseID primitive.ObjectID, before, limit int, mainLine bool) ([]models.Message, error) {
+	messages, err := r.messageDAO.FindPage(ctx, caseID, bson.M{"$lt": before}, int64(limit), false, mainLine)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(messages)
+	return messages, nil
+}
+
+// GetMessagesAfter retrieves up to limit messages with a sequence greater than after,
+// in ascending order.
+func (r *MessageRepository) GetMessagesAfter(ctx context.Context, caseID primitive.ObjectID, after, limit int, mainLine bool) ([]models.Message, error) {
+	return r.messageDAO.FindPage(ctx, caseID, bson.M{"$gt": after}, int64(limit), true, mainLine)
+}
+
+// GetFirstMessages retrieves the oldest limit messages of a case in ascending order.
+func (r *MessageRepository) GetFirstMessages(ctx context.Context, caseID primitive.ObjectID, limit int, mainLine bool) ([]models.Message, error) {
+	return r.messageDAO.FindPage(ctx, caseID, nil, int64(limit), true, mainLine)
+}
+
+// GetLastMessages retrieves the newest limit messages of a case in ascending order.
+func (r *MessageRepository) GetLastMessages(ctx context.Context, caseID primitive.ObjectID, limit int, mainLine bool) ([]models.Message, error) {
+	messages, err := r.messageDAO.FindPage(ctx, caseID, nil, int64(limit), false, mainLine)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(messages)
+	return messages, nil
+}
+
+// HasMessagesBefore reports whether a case has messages older than the given sequence.
+func (r *MessageRepository) HasMessagesBefore(ctx context.Context, caseID primitive.ObjectID, seq int, mainLine bool) (bool, error) {
+	return r.messageDAO.Exists(ctx, caseID, bson.M{"$lt": seq}, mainLine)
+}
+
+// HasMessagesAfter reports whether a case has messages newer than the given sequence.
+func (r *MessageRepository) HasMessagesAfter(ctx context.Context, caseID primitive.ObjectID, seq int, mainLine bool) (bool, error) {
+	return r.messageDAO.Exists(ctx, caseID, bson.M{"$gt": seq}, mainLine)
+}
+
+// CountMessages returns the number of messages stored for a case.
+func (r *MessageRepository) CountMessages(ctx context.Context, caseID primitive.ObjectID, mainLine bool) (int64, error) {
+	return r.messageDAO.CountByCaseID(ctx, caseID, mainLine)
+}
+
+// GetReplies retrieves the live replies in the thread of a message in sequence order.
+func (r *MessageRepository) GetReplies(ctx context.Context, caseID, parentID primitive.ObjectID) ([]models.Message, error) {
+	return r.messageDAO.FindReplies(ctx, caseID, parentID)
+}
+
+// GetToolMessages retrieves the live messages of a case that ask for tool calls
+// or carry tool results, in conversation order.
+func (r *MessageRepository) GetToolMessages(ctx context.Context, caseID primitive.ObjectID) ([]models.Message, error) {
+	return r.messageDAO.FindToolMessages(ctx, caseID)
+}
+
+// SumUsage totals the token usage of the messages of each of the given cases
+// created between from and to.
+func (r *MessageRepository) SumUsage(ctx context.Context, caseIDs []primitive.ObjectID, from, to time.Time) ([]models.CaseUsage, error) {
+	if len(caseIDs) == 0 {
+		return []models.CaseUsage{}, nil
+	}
+	return r.messageDAO.SumUsage(ctx, caseIDs, from, to)
+}
+
+// CountReplies counts the live replies to each of the given messages.
+func (r *MessageRepository) CountReplies(ctx context.Context, caseID primitive.ObjectID, parentIDs []primitive.ObjectID) (map[primitive.ObjectID]int, error) {
+	if len(parentIDs) == 0 {
+		return map[primitive.ObjectID]int{}, nil
+	}
+	return r.messageDAO.CountReplies(ctx, caseID, parentIDs)
+}
+
+// DeleteMessagesByCaseID deletes every message of a case.
+func (r *MessageRepository) DeleteMessagesByCaseID(ctx context.Context, caseID primitive.ObjectID) error {
+	return r.messageDAO.DeleteByCaseID(ctx, caseID)
+}
+
+// GetMessageByID retrieves a single message of a case, including soft-deleted ones.
+func (r *MessageRepository) GetMessageByID(ctx context.Context, caseID, messageID primitive.ObjectID) (models.Message, error) {
+	return r.messageDAO.FindByID(ctx, caseID, messageID)
+}
+
+// EditMessage replaces the content of a message and records the previous version.
+func (r *MessageRepository) EditMessage(ctx context.Context, caseID, messageID primitive.ObjectID, content, documentPath string, editedAt time.Time, editedBy primitive.ObjectID) (models.Message, error) {
+	return r.messageDAO.UpdateContent(ctx, caseID, messageID, content, documentPath, editedAt, editedBy)
+}
+
+// DeleteMessage soft-deletes a message, keeping it and its history for review.
+func (r *MessageRepository) DeleteMessage(ctx context.Context, caseID, messageID primitive.ObjectID, deletedAt time.Time, deletedBy primitive.ObjectID) (models.Message, error) {
+	return r.messageDAO.SoftDelete(ctx, caseID, messageID, deletedAt, deletedBy)
+}
+
+// SearchMessages finds live messages of the given cases matching the filter.
+func (r *MessageRepository) SearchMessages(ctx context.Context, caseIDs []primitive.ObjectID, filter models.MessageSearchFilter, limit int) ([]models.MessageSearchResult, error) {
+	return r.messageDAO.Search(ctx, caseIDs, filter, int64(limit))
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ShareLinkRepository manages the public share links of cases and keeps the
+// share flag of each case in step with them.
+type ShareLinkRepository struct {
+	shareLinkDAO *daos.ShareLinkDAO
+	caseDAO      *daos.CaseDAO
+	logger       logs.Logger
+}
+
+// NewShareLinkRepository creates a new instance of the share link repository.
+func NewShareLinkRepository(shareLinkDAO *daos.ShareLinkDAO, caseDAO *daos.CaseDAO, logger logs.Logger) *ShareLinkRepository {
+	return &ShareLinkRepository{
+		shareLinkDAO: shareLinkDAO,
+		caseDAO:      caseDAO,
+		logger:       logger,
+	}
+}
+
+// CreateShareLink stores a new share link and marks its case as shared.
+func (r *ShareLinkRepository) CreateShareLink(ctx context.Context, link *models.ShareLink) error {
+	r.logger.Info("Repository Level: Attempting to create share link")
+	if err := r.shareLinkDAO.Create(ctx, link); err != nil {
+		r.logger.Error("Repository Level: Failed to create share link", err)
+		return err
+	}
+	if _, err := r.caseDAO.Update(ctx, link.CaseID, map[string]interface{}{"share": true}); err != nil {
+		r.logger.Error("Repository Level: Failed to mark case as shared", err)
+		return err
+	}
+	r.logger.Info("Repository Level: Successfully created share link")
+	return nil
+}
+
+// GetShareLinkByTokenHash retrieves the share link issued for a token.
+func (r *ShareLinkRepository) GetShareLinkByTokenHash(ctx context.Context, tokenHash string) (models.ShareLink, error) {
+	return r.shareLinkDAO.FindByTokenHash(ctx, tokenHash)
+}
+
+// GetShareLinks retrieves every share link of a case, newest first.
+func (r *ShareLinkRepository) GetShareLinks(ctx context.Context, caseID primitive.ObjectID) ([]models.ShareLink, error) {
+	return r.shareLinkDAO.FindByCaseID(ctx, caseID)
+}
+
+// RevokeShareLink revokes a share link of a case. The case stays marked as
+// shared only while it has other active links.
+func (r *ShareLinkRepository) RevokeShareLink(ctx context.Context, caseID, id primitive.ObjectID, revokedBy primitive.ObjectID) (models.ShareLink, error) {
+	r.logger.Info("Repository Level: Attempting to revoke share link")
+	now := time.Now()
+	link, err := r.shareLinkDAO.Revoke(ctx, caseID, id, now, revokedBy)
+	if err != nil {
+		r.logger.Error("Repository Level: Failed to revoke share link", err)
+		return link, err
+	}
+	active, err := r.shareLinkDAO.CountActive(ctx, caseID, now)
+	if err != nil {
+		r.logger.Error("Repository Level: Failed to count active share links", err)
+		return link, err
+	}
+	if _, err := r.caseDAO.Update(ctx, caseID, map[string]interface{}{"share": active > 0}); err != nil {
+		r.logger.Error("Repository Level: Failed to update case share flag", err)
+		return link, err
+	}
+	r.logger.Info("Repository Level: Successfully revoked share link")
+	return link, nil
+}
+
+// RecordAccess counts one access through a share link.
+func (r *ShareLinkRepository) RecordAccess(ctx context.Context, id primitive.ObjectID, accessedAt time.Time) error {
+	return r.shareLinkDAO.RecordAccess(ctx, id, accessedAt)
+}
+
+// DeleteShareLinks permanently removes the share links of a case.
+func (r *ShareLinkRepository) DeleteShareLinks(ctx context.Context, caseID primitive.ObjectID) error {
+	return r.shareLinkDAO.DeleteByCaseID(ctx, caseID)
+}
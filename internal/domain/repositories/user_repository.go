@@ -15,6 +15,7 @@ type UserRepository interface {
 	FindUserByCaseID(ctx context.Context, caseID primitive.ObjectID) (*models.User, error)
 	TotalUsers(ctx context.Context) ([]*models.User, error)
 	DeleteUser(ctx context.Context, userID primitive.ObjectID) error
+	RemoveCaseReferences(ctx context.Context, caseID primitive.ObjectID) error
 	CreateUser(ctx context.Context, user *models.User) (*models.User, error)
 	UpdateUser(ctx context.Context, userID primitive.ObjectID, updates map[string]interface{}) (*models.User, error)
 }
@@ -56,6 +57,11 @@ func (r *UserRepositoryImpl) DeleteUser(ctx context.Context, userID primitive.Ob
 	return r.userDAO.DeleteUser(ctx, userID)
 }
 
+// RemoveCaseReferences removes a case from the case lists of all users.
+func (r *UserRepositoryImpl) RemoveCaseReferences(ctx context.Context, caseID primitive.ObjectID) error {
+	return r.userDAO.RemoveCaseID(ctx, caseID)
+}
+
 // CreateUser creates a new user.
 func (r *UserRepositoryImpl) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
 	return r.userDAO.CreateUser(ctx, user)
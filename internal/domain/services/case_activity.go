@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"slices"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultActivityPageSize = 50
+	maxActivityPageSize     = 200
+)
+
+// recordActivity adds an entry to the activity feed of a case. Like version
+// snapshots, failures are only logged since the change has already been applied.
+func (s *CaseServiceImpl) recordActivity(ctx context.Context, caseID, actorID primitive.ObjectID, action string, fields ...string) {
+	if _, err := s.activityRepo.RecordActivity(ctx, caseID, actorID, action, fields); err != nil {
+		s.logger.Error("Service Level: Failed to record case activity", err, zap.String("action", action))
+	}
+}
+
+// GetCaseActivity returns a page of a case's activity, newest first, optionally
+// limited to some action types.
+func (s *CaseServiceImpl) GetCaseActivity(ctx context.Context, id, callerID primitive.ObjectID, query dtos.CaseActivityQuery) (*dtos.CaseActivityPageResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve case activity")
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultActivityPageSize
+	}
+	if limit > maxActivityPageSize {
+		limit = maxActivityPageSize
+	}
+	if err := s.AuthorizeCaseAccess(ctx, id, callerID); err != nil {
+		return nil, err
+	}
+
+	// One extra entry tells whether an older page exists.
+	activities, err := s.activityRepo.GetActivity(ctx, id, models.CaseActivityFilter{
+		Actions: query.Actions,
+		Before:  query.Before.Value,
+		Limit:   limit + 1,
+	})
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve case activity", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve case activity", "get_case_activity_failed")
+	}
+	page := &dtos.CaseActivityPageResponse{}
+	if len(activities) > limit {
+		activities = activities[:limit]
+		page.Next = helpers.NewNullable(activities[limit-1].ID)
+	}
+	page.Activities = s.mapper.CaseActivitiesToDTO(activities)
+	s.logger.Info("Service Level: Successfully retrieved case activity")
+	return page, nil
+}
+
+// updatedFields lists, in a stable order, the case fields an update document
+// sets, leaving out the last edit time that every update bumps.
+func updatedFields(updates map[string]interface{}) []string {
+	fields := make([]string, 0, len(updates))
+	for field := range updates {
+		if field != "last_edit" {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+	return fields
+}
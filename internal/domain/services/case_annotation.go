@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultAnnotationPageSize = 50
+	maxAnnotationPageSize     = 200
+)
+
+// CreateAnnotation highlights a message, or a range of its characters, for the
+// caller. Any reader may keep private annotations; sharing one with the other
+// collaborators takes at least the commenter role.
+func (s *CaseServiceImpl) CreateAnnotation(ctx context.Context, id, messageID, callerID primitive.ObjectID, request dtos.CreateAnnotationRequest) (*dtos.AnnotationResponse, error) {
+	s.logger.Info("Service Level: Attempting to create annotation")
+	visibility := request.Visibility.OrElse(models.AnnotationVisibilityPrivate)
+	if !models.ValidAnnotationVisibility(visibility) {
+		return nil, errors.NewIncorrectInputError("Visibility must be private or shared", "invalid_annotation_visibility")
+	}
+	if err := s.AuthorizeCaseRole(ctx, id, callerID, annotationRole(visibility)); err != nil {
+		return nil, err
+	}
+	message, err := s.messageRepo.GetMessageByID(ctx, id, messageID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve message to annotate", err)
+		return nil, messageError(err)
+	}
+	if !message.DeletedAt.IsZero() {
+		return nil, errors.NewIncorrectInputError("Cannot annotate a deleted message", "message_deleted")
+	}
+
+	now := time.Now()
+	annotation := models.Annotation{
+		CaseID:          id,
+		MessageID:       message.ID,
+		MessageSequence: message.Sequence,
+		AuthorID:        callerID,
+		Note:            request.Note.Value,
+		Visibility:      visibility,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if request.Range.Present {
+		textRange := models.TextRange{Start: request.Range.Value.Start, End: request.Range.Value.End}
+		content := []rune(message.Content)
+		if textRange.Start < 0 || textRange.End <= textRange.Start || textRange.End > len(content) {
+			return nil, errors.NewIncorrectInputError("Range must select characters of the message", "invalid_annotation_range")
+		}
+		annotation.Range = &textRange
+		annotation.Quote = string(content[textRange.Start:textRange.End])
+	}
+	if err := s.annotationRepo.CreateAnnotation(ctx, &annotation); err != nil {
+		s.logger.Error("Service Level: Failed to create annotation", err)
+		return nil, errors.NewDatabaseError("Failed to create annotation", "create_annotation_failed")
+	}
+	response := s.mapper.AnnotationToDTO(annotation)
+	s.logger.Info("Service Level: Successfully created annotation")
+	return &response, nil
+}
+
+// GetAnnotations returns a page of the annotations of a case the caller can see:
+// every shared annotation and their own private ones, newest first, optionally
+// limited to one message.
+func (s *CaseServiceImpl) GetAnnotations(ctx context.Context, id, callerID primitive.ObjectID, query dtos.AnnotationQuery) (*dtos.AnnotationPageResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve annotations")
+	if err := s.AuthorizeCaseAccess(ctx, id, callerID); err != nil {
+		return nil, err
+	}
+	limit := annotationPageSize(query.Limit)
+	// One extra annotation tells whether an older page exists.
+	annotations, err := s.annotationRepo.GetVisibleAnnotations(ctx, id, callerID, models.AnnotationFilter{
+		MessageID: query.MessageID.Value,
+		Before:    query.Before.Value,
+		Limit:     limit + 1,
+	})
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve annotations", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve annotations", "get_annotations_failed")
+	}
+	page := &dtos.AnnotationPageResponse{}
+	if len(annotations) > limit {
+		annotations = annotations[:limit]
+		page.Next = helpers.NewNullable(annotations[limit-1].ID)
+	}
+	page.Annotations = s.mapper.AnnotationsToDTO(annotations)
+	s.logger.Info("Service Level: Successfully retrieved annotations")
+	return page, nil
+}
+
+// UpdateAnnotation changes the note or visibility of an annotation. Only its
+// author may change it, and sharing it takes at least the commenter role.
+func (s *CaseServiceImpl) UpdateAnnotation(ctx context.Context, id, annotationID, callerID primitive.ObjectID, request dtos.UpdateAnnotationRequest) (*dtos.AnnotationResponse, error) {
+	s.logger.Info("Service Level: Attempting to update annotation")
+	if request.Visibility.Present && !models.ValidAnnotationVisibility(request.Visibility.Value) {
+		return nil, errors.NewIncorrectInputError("Visibility must be private or shared", "invalid_annotation_visibility")
+	}
+	caseModel, annotation, err := s.authorizeAnnotation(ctx, id, annotationID, callerID)
+	if err != nil {
+		return nil, err
+	}
+	if annotation.AuthorID != callerID {
+		return nil, errors.NewForbiddenError("Only the author can change an annotation", "annotation_not_author")
+	}
+	visibility := request.Visibility.OrElse(annotation.Visibility)
+	if err := checkCaseRole(caseModel, callerID, annotationRole(visibility)); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"visibility": visibility,
+		"updated_at": time.Now(),
+	}
+	if request.Note.Present {
+		updates["note"] = request.Note.Value
+	}
+	updated, err := s.annotationRepo.UpdateAnnotation(ctx, id, annotationID, updates)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to update annotation", err)
+		return nil, annotationError(err, "Failed to update annotation", "update_annotation_failed")
+	}
+	response := s.mapper.AnnotationToDTO(updated)
+	s.logger.Info("Service Level: Successfully updated annotation")
+	return &response, nil
+}
+
+// DeleteAnnotation removes an annotation. Authors may delete their own annotations
+// and owners may also remove shared ones from their case.
+func (s *CaseServiceImpl) DeleteAnnotation(ctx context.Context, id, annotationID, callerID primitive.ObjectID) error {
+	s.logger.Info("Service Level: Attempting to delete annotation")
+	caseModel, annotation, err := s.authorizeAnnotation(ctx, id, annotationID, callerID)
+	if err != nil {
+		return err
+	}
+	if annotation.AuthorID != callerID && caseModel.RoleOf(callerID) != models.CaseRoleOwner {
+		return errors.NewForbiddenError("Only the author or an owner can delete an annotation", "annotation_not_author")
+	}
+	if err := s.annotationRepo.DeleteAnnotation(ctx, id, annotationID); err != nil {
+		s.logger.Error("Service Level: Failed to delete annotation", err)
+		return annotationError(err, "Failed to delete annotation", "delete_annotation_failed")
+	}
+	s.logger.Info("Service Level: Successfully deleted annotation")
+	return nil
+}
+
+// GetUserBookmarks returns a page of the annotations a user made across all the
+// cases they can still access, newest first. Users can only list their own.
+func (s *CaseServiceImpl) GetUserBookmarks(ctx context.Context, userID, callerID primitive.ObjectID, query dtos.AnnotationQuery) (*dtos.BookmarkPageResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve user bookmarks")
+	if userID != callerID {
+		return nil, errors.NewForbiddenError("You can only list your own bookmarks", "bookmarks_access_denied")
+	}
+	limit := annotationPageSize(query.Limit)
+	accessible, err := s.caseRepo.GetAccessibleCases(ctx, userID, primitive.NilObjectID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve accessible cases", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve bookmarks", "get_bookmarks_failed")
+	}
+	caseNames := make(map[primitive.ObjectID]string, len(accessible))
+	caseIDs := make([]primitive.ObjectID, 0, len(accessible))
+	for _, caseModel := range accessible {
+		caseNames[caseModel.ID] = caseModel.Name
+		caseIDs = append(caseIDs, caseModel.ID)
+	}
+	annotations, err := s.annotationRepo.GetAnnotationsByAuthor(ctx, userID, caseIDs, models.AnnotationFilter{
+		Before: query.Before.Value,
+		Limit:  limit + 1,
+	})
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve bookmarks", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve bookmarks", "get_bookmarks_failed")
+	}
+
+	page := &dtos.BookmarkPageResponse{Bookmarks: make([]dtos.BookmarkResponse, 0, len(annotations))}
+	if len(annotations) > limit {
+		annotations = annotations[:limit]
+		page.Next = helpers.NewNullable(annotations[limit-1].ID)
+	}
+	for _, annotation := range annotations {
+		page.Bookmarks = append(page.Bookmarks, dtos.BookmarkResponse{
+			AnnotationResponse: s.mapper.AnnotationToDTO(annotation),
+			CaseName:           helpers.NewNullable(caseNames[annotation.CaseID]),
+		})
+	}
+	s.logger.Info("Service Level: Successfully retrieved user bookmarks")
+	return page, nil
+}
+
+// authorizeAnnotation loads a case the caller can read together with one of its
+// annotations. Private annotations of other users are reported as not found.
+func (s *CaseServiceImpl) authorizeAnnotation(ctx context.Context, id, annotationID, callerID primitive.ObjectID) (models.Case, models.Annotation, error) {
+	caseModel, err := s.authorizeCase(ctx, id, callerID, models.CaseRoleViewer)
+	if err != nil {
+		return models.Case{}, models.Annotation{}, err
+	}
+	annotation, err := s.annotationRepo.GetAnnotationByID(ctx, id, annotationID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve annotation", err)
+		return models.Case{}, models.Annotation{}, annotationError(err, "Failed to retrieve annotation", "get_annotation_failed")
+	}
+	if !annotation.VisibleTo(callerID) {
+		return models.Case{}, models.Annotation{}, errors.NewNotFoundError("Annotation not found", "annotation_not_found")
+	}
+	return caseModel, annotation, nil
+}
+
+// annotationRole is the least role needed to hold an annotation with the given visibility.
+func annotationRole(visibility string) string {
+	if visibility == models.AnnotationVisibilityShared {
+		return models.CaseRoleCommenter
+	}
+	return models.CaseRoleViewer
+}
+
+func annotationPageSize(limit int) int {
+	if limit <= 0 {
+		return defaultAnnotationPageSize
+	}
+	if limit > maxAnnotationPageSize {
+		return maxAnnotationPageSize
+	}
+	return limit
+}
+
+func annotationError(err error, message, slug string) error {
+	if stderrors.Is(err, daos.ErrAnnotationNotFound) {
+		return errors.NewNotFoundError("Annotation not found", "annotation_not_found")
+	}
+	return errors.NewDatabaseError(message, slug)
+}
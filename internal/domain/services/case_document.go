@@ -0,0 +1,279 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/extract"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/storage"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxDocumentSize is the largest document that can be uploaded.
+const maxDocumentSize = 50 << 20
+
+// errDocumentTooLarge is returned by the upload reader once maxDocumentSize is exceeded.
+var errDocumentTooLarge = stderrors.New("document exceeds the maximum size")
+
+// DocumentContent is the content of a stored document ready to be downloaded. The
+// caller closes Content.
+type DocumentContent struct {
+	Content     io.ReadCloser
+	ContentType string
+	FileName    string
+	Size        int64
+}
+
+// UploadDocument stores a file on a case and extracts its text for search.
+// Editors and owners may upload. When no usable content type is given it is
+// guessed from the file extension.
+func (s *CaseServiceImpl) UploadDocument(ctx context.Context, id, callerID primitive.ObjectID, fileName, contentType string, content io.Reader) (*dtos.DocumentResponse, error) {
+	s.logger.Info("Service Level: Attempting to upload document")
+	fileName = strings.TrimSpace(path.Base(strings.ReplaceAll(fileName, "\\", "/")))
+	if fileName == "" || fileName == "." || fileName == "/" {
+		return nil, errors.NewIncorrectInputError("File name is required", "document_name_required")
+	}
+	if err := s.AuthorizeCaseRole(ctx, id, callerID, models.CaseRoleEditor); err != nil {
+		return nil, err
+	}
+
+	document := models.Document{
+		CaseID:      id,
+		UploaderID:  callerID,
+		FileName:    fileName,
+		ContentType: documentContentType(fileName, contentType),
+		CreatedAt:   time.Now(),
+	}
+	if err := s.documentRepo.StoreDocument(ctx, &document, &sizeLimitedReader{r: content, remaining: maxDocumentSize}); err != nil {
+		if stderrors.Is(err, errDocumentTooLarge) {
+			return nil, errors.NewIncorrectInputError("Document must be at most 50 MB", "document_too_large")
+		}
+		s.logger.Error("Service Level: Failed to store document", err)
+		return nil, errors.NewDatabaseError("Failed to upload document", "upload_document_failed")
+	}
+	s.extractText(ctx, &document)
+	s.recordActivity(ctx, id, callerID, models.CaseActionUploadDocument, "documents")
+	response := s.mapper.DocumentToDTO(document)
+	s.logger.Info("Service Level: Successfully uploaded document")
+	return &response, nil
+}
+
+// GetCaseDocuments lists the documents uploaded to a case, newest first.
+func (s *CaseServiceImpl) GetCaseDocuments(ctx context.Context, id, callerID primitive.ObjectID) ([]dtos.DocumentResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve case documents")
+	if err := s.AuthorizeCaseAccess(ctx, id, callerID); err != nil {
+		return nil, err
+	}
+	documents, err := s.documentRepo.GetCaseDocuments(ctx, id, false)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve case documents", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve case documents", "get_case_documents_failed")
+	}
+	s.logger.Info("Service Level: Successfully retrieved case documents")
+	return s.mapper.DocumentsToDTO(documents), nil
+}
+
+// DownloadDocument opens the content of a document for a caller with access to
+// its case. Documents removed from the case stay downloadable while messages
+// still refer to them.
+func (s *CaseServiceImpl) DownloadDocument(ctx context.Context, documentID, callerID primitive.ObjectID) (*DocumentContent, error) {
+	s.logger.Info("Service Level: Attempting to download document")
+	document, err := s.documentRepo.GetDocumentByID(ctx, documentID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve document", err)
+		return nil, documentError(err, "Failed to download document", "download_document_failed")
+	}
+	if err := s.AuthorizeCaseAccess(ctx, document.CaseID, callerID); err != nil {
+		return nil, err
+	}
+	content, err := s.documentRepo.OpenDocument(ctx, document)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to open document content", err)
+		if stderrors.Is(err, storage.ErrBlobNotFound) {
+			return nil, errors.NewNotFoundError("Document content not found", "document_content_not_found")
+		}
+		return nil, errors.NewDatabaseError("Failed to download document", "download_document_failed")
+	}
+	s.logger.Info("Service Level: Successfully opened document")
+	return &DocumentContent{
+		Content:     content,
+		ContentType: document.ContentType,
+		FileName:    document.FileName,
+		Size:        document.Size,
+	}, nil
+}
+
+// GetDocumentText returns the text extracted from a document for a caller with
+// access to its case. Documents whose text was never extracted, such as those
+// uploaded before extraction existed, are extracted on first request.
+func (s *CaseServiceImpl) GetDocumentText(ctx context.Context, documentID, callerID primitive.ObjectID) (*dtos.DocumentTextResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve document text")
+	document, err := s.documentRepo.GetDocumentWithText(ctx, documentID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve document", err)
+		return nil, documentError(err, "Failed to retrieve document text", "get_document_text_failed")
+	}
+	if err := s.AuthorizeCaseAccess(ctx, document.CaseID, callerID); err != nil {
+		return nil, err
+	}
+	if document.TextStatus == "" {
+		s.extractText(ctx, &document)
+	}
+	switch document.TextStatus {
+	case models.DocumentTextExtracted:
+	case models.DocumentTextUnsupported:
+		return nil, errors.NewIncorrectInputError("Text cannot be extracted from this type of document", "document_text_unsupported")
+	case models.DocumentTextFailed:
+		return nil, errors.NewIncorrectInputError("The document could not be read", "document_text_unreadable")
+	default:
+		return nil, errors.NewDatabaseError("Failed to retrieve document text", "get_document_text_failed")
+	}
+	response := s.mapper.DocumentTextToDTO(document)
+	s.logger.Info("Service Level: Successfully retrieved document text")
+	return &response, nil
+}
+
+// DeleteDocument removes a document from a case. Editors and owners may delete
+// documents. A document that messages still refer to is hidden from the case
+// rather than removed, so those messages keep working.
+func (s *CaseServiceImpl) DeleteDocument(ctx context.Context, id, documentID, callerID primitive.ObjectID) error {
+	s.logger.Info("Service Level: Attempting to delete document")
+	if err := s.AuthorizeCaseRole(ctx, id, callerID, models.CaseRoleEditor); err != nil {
+		return err
+	}
+	if err := s.documentRepo.DeleteDocument(ctx, id, documentID); err != nil {
+		s.logger.Error("Service Level: Failed to delete document", err)
+		return documentError(err, "Failed to delete document", "delete_document_failed")
+	}
+	s.recordActivity(ctx, id, callerID, models.CaseActionDeleteDocument, "documents")
+	s.logger.Info("Service Level: Successfully deleted document")
+	return nil
+}
+
+// resolveDocuments checks that the documents new messages refer to were uploaded
+// to the same case and not removed from it, and points the document path of those messages at them when
+// it is not set.
+func (s *CaseServiceImpl) resolveDocuments(ctx context.Context, id primitive.ObjectID, messages []models.Message) error {
+	checked := make(map[primitive.ObjectID]bool)
+	for i := range messages {
+		documentID := messages[i].DocumentID
+		if documentID.IsZero() {
+			continue
+		}
+		if !checked[documentID] {
+			document, err := s.documentRepo.GetDocumentByID(ctx, documentID)
+			if err != nil && !stderrors.Is(err, daos.ErrDocumentNotFound) {
+				s.logger.Error("Service Level: Failed to retrieve message document", err)
+				return errors.NewDatabaseError("Failed to append messages", "append_messages_failed")
+			}
+			if err != nil || document.CaseID != id || !document.DeletedAt.IsZero() {
+				return errors.NewNotFoundError("Document not found", "document_not_found")
+			}
+			checked[documentID] = true
+		}
+		if messages[i].DocumentPath == "" {
+			messages[i].DocumentPath = models.DocumentPath(documentID)
+		}
+	}
+	return nil
+}
+
+// extractText runs a document through text extraction and stores the outcome
+// with its metadata. When the content cannot be read the document is left
+// without a text status, so extraction is tried again on the next request for
+// its text.
+func (s *CaseServiceImpl) extractText(ctx context.Context, document *models.Document) {
+	status, text, err := s.documentText(ctx, *document)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to read document for text extraction", err)
+		return
+	}
+	if err := s.documentRepo.SetDocumentText(ctx, document.ID, status, text); err != nil {
+		s.logger.Error("Service Level: Failed to store document text", err)
+		return
+	}
+	document.TextStatus, document.Text = status, text
+}
+
+// documentText extracts the text of a document and returns it with its text
+// status. Text already extracted from the same content is reused rather than
+// parsed again. An error means the content could not be read.
+func (s *CaseServiceImpl) documentText(ctx context.Context, document models.Document) (string, string, error) {
+	if !extract.Supported(document.ContentType, document.FileName) {
+		return models.DocumentTextUnsupported, "", nil
+	}
+	text, found, err := s.documentRepo.GetExtractedText(ctx, document.SHA256)
+	if err != nil {
+		return "", "", err
+	}
+	if found {
+		return models.DocumentTextExtracted, text, nil
+	}
+
+	content, err := s.documentRepo.OpenDocument(ctx, document)
+	if err != nil {
+		return "", "", err
+	}
+	defer content.Close()
+	data, err := io.ReadAll(io.LimitReader(content, maxDocumentSize))
+	if err != nil {
+		return "", "", err
+	}
+	text, err = extract.Text(bytes.NewReader(data), int64(len(data)), document.ContentType, document.FileName)
+	if err != nil {
+		s.logger.Warn("Service Level: Failed to extract document text: " + err.Error())
+		return models.DocumentTextFailed, "", nil
+	}
+	return models.DocumentTextExtracted, text, nil
+}
+
+// documentContentType keeps a declared content type unless it is missing or the
+// generic binary type, in which case the file extension decides.
+func documentContentType(fileName, declared string) string {
+	if mediaType, _, err := mime.ParseMediaType(declared); err == nil && mediaType != "application/octet-stream" {
+		return declared
+	}
+	if guessed := mime.TypeByExtension(path.Ext(fileName)); guessed != "" {
+		return guessed
+	}
+	return "application/octet-stream"
+}
+
+func documentError(err error, message, slug string) error {
+	if stderrors.Is(err, daos.ErrDocumentNotFound) {
+		return errors.NewNotFoundError("Document not found", "document_not_found")
+	}
+	return errors.NewDatabaseError(message, slug)
+}
+
+// sizeLimitedReader fails with errDocumentTooLarge once more than remaining bytes
+// have been read.
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, errDocumentTooLarge
+	}
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, errDocumentTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/export"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CaseExport is a rendered case transcript ready to be downloaded.
+type CaseExport struct {
+	Content     []byte
+	ContentType string
+	FileName    string
+}
+
+// ExportCase renders a case, its participants and all of its live messages in the
+// requested format for a caller with access to the case.
+func (s *CaseServiceImpl) ExportCase(ctx context.Context, id, callerID primitive.ObjectID, format string) (*CaseExport, error) {
+	s.logger.Info("Service Level: Attempting to export case")
+	contentType, extension, ok := export.ContentType(format)
+	if !ok {
+		return nil, errors.NewIncorrectInputError("Format must be md, html or json", "invalid_export_format")
+	}
+	if err := s.AuthorizeCaseAccess(ctx, id, callerID); err != nil {
+		return nil, err
+	}
+
+	caseModel, err := s.caseRepo.GetCaseByID(ctx, id)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve case for export", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve case", "get_case_failed")
+	}
+	messages, err := s.messageRepo.GetMessagesByCaseID(ctx, id)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve case messages for export", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve messages", "get_messages_failed")
+	}
+
+	transcript := export.Transcript{
+		ID:            caseModel.ID.Hex(),
+		Name:          caseModel.Name,
+		Creator:       s.exportParticipant(ctx, caseModel.CreatorID),
+		Collaborators: make([]export.Participant, 0, len(caseModel.Collaborators)),
+		Messages:      make([]export.MessageEntry, 0, len(messages)),
+		CreationDate:  caseModel.CreationDate,
+		LastEdit:      caseModel.LastEdit,
+		ExportedAt:    time.Now(),
+	}
+	if !caseModel.AgentID.IsZero() {
+		if agent, err := s.agentRepo.GetAgentByID(ctx, caseModel.AgentID); err == nil {
+			transcript.Agent = &export.Participant{ID: agent.ID.Hex(), Name: agent.Name}
+		} else {
+			s.logger.Warn("Service Level: Agent of exported case not found")
+		}
+	}
+	for _, collaborator := range caseModel.Collaborators {
+		participant := s.exportParticipant(ctx, collaborator.ID)
+		participant.Role = collaborator.EffectiveRole()
+		participant.Edit = models.CaseRoleAllows(participant.Role, models.CaseRoleEditor)
+		transcript.Collaborators = append(transcript.Collaborators, *participant)
+	}
+	positions := make(map[primitive.ObjectID]int, len(messages))
+	for _, message := range messages {
+		positions[message.ID] = message.Sequence
+	}
+	documentNames := s.exportDocumentNames(ctx, id)
+	for _, message := range messages {
+		entry := exportMessage(message)
+		if parentPosition, ok := positions[message.ParentMessageID]; ok && message.IsReply() {
+			entry.ReplyTo = &parentPosition
+		}
+		entry.DocumentName = documentNames[message.DocumentID]
+		transcript.Messages = append(transcript.Messages, entry)
+	}
+
+	content, err := s.exporter.Render(format, transcript)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to render case export", err)
+		return nil, errors.NewSlugError("Failed to render case export", "export_render_failed", errors.ErrorTypeUnknown)
+	}
+	s.logger.Info("Service Level: Successfully exported case")
+	return &CaseExport{
+		Content:     content,
+		ContentType: contentType,
+		FileName:    fmt.Sprintf("%s.%s", exportFileName(caseModel), extension),
+	}, nil
+}
+
+// exportParticipant describes a user by name and email, falling back to the bare
+// ID when the user no longer exists.
+func (s *CaseServiceImpl) exportParticipant(ctx context.Context, userID primitive.ObjectID) *export.Participant {
+	participant := &export.Participant{ID: userID.Hex(), Name: userID.Hex()}
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil || user == nil {
+		return participant
+	}
+	if name := strings.TrimSpace(user.FirstName + " " + user.LastName); name != "" {
+		participant.Name = name
+	}
+	participant.Email = user.Email
+	return participant
+}
+
+// exportDocumentNames maps the documents uploaded to a case, including those
+// removed from it, to their file names. Without them exported links fall back to the last element of the document path.
+func (s *CaseServiceImpl) exportDocumentNames(ctx context.Context, caseID primitive.ObjectID) map[primitive.ObjectID]string {
+	documents, err := s.documentRepo.GetCaseDocuments(ctx, caseID, true)
+	if err != nil {
+		s.logger.Warn("Service Level: Documents of exported case not found")
+		return nil
+	}
+	names := make(map[primitive.ObjectID]string, len(documents))
+	for _, document := range documents {
+		names[document.ID] = document.FileName
+	}
+	return names
+}
+
+func exportMessage(message models.Message) export.MessageEntry {
+	entry := export.MessageEntry{
+		ID:           message.ID.Hex(),
+		Position:     message.Sequence,
+		Sender:       message.Sender,
+		Recipient:    message.Recipient,
+		Content:      message.Content,
+		FunctionCall: message.FunctionCall,
+		DocumentPath: message.DocumentPath,
+		CreatedAt:    message.CreatedAt,
+		Edited:       !message.EditedAt.IsZero(),
+	}
+	for _, call := range message.ToolCalls {
+		entry.ToolCalls = append(entry.ToolCalls, export.ToolCallEntry{
+			CallID:    call.CallID,
+			Name:      call.Name,
+			Arguments: json.RawMessage(call.Arguments),
+		})
+	}
+	if result := message.ToolResult; result != nil {
+		entry.ToolResult = &export.ToolResultEntry{
+			CallID:     result.CallID,
+			Name:       result.Name,
+			Status:     result.Status,
+			DurationMS: result.DurationMS,
+		}
+	}
+	return entry
+}
+
+// exportFileName derives a download file name from the case name.
+func exportFileName(caseModel models.Case) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		case r == ' ':
+			return '-'
+		}
+		return -1
+	}, strings.TrimSpace(caseModel.Name))
+	if name == "" {
+		return "case-" + caseModel.ID.Hex()
+	}
+	return name
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ForkCase copies a case, up to the message at the requested position, into a new
+// case owned by the caller. The fork keeps the agent of the original and records
+// where it was taken from; collaborators are not carried over.
+func (s *CaseServiceImpl) ForkCase(ctx context.Context, id, callerID primitive.ObjectID, request dtos.ForkCaseRequest) (*dtos.CaseResponse, error) {
+	s.logger.Info("Service Level: Attempting to fork case")
+	if !request.Position.Present || request.Position.Value < 0 {
+		return nil, errors.NewIncorrectInputError("A message position is required", "fork_position_required")
+	}
+	if err := s.AuthorizeCaseAccess(ctx, id, callerID); err != nil {
+		return nil, err
+	}
+	source, err := s.caseRepo.GetCaseByID(ctx, id)
+	if err != nil {
+		if stderrors.Is(err, daos.ErrCaseNotFound) {
+			return nil, errors.NewNotFoundError("Case not found", "case_not_found")
+		}
+		s.logger.Error("Service Level: Failed to retrieve case to fork", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve case", "get_case_failed")
+	}
+	messages, err := s.messageRepo.GetMessagesByCaseID(ctx, id)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve messages to fork", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve case messages", "get_case_messages_failed")
+	}
+
+	position := request.Position.Value
+	var origin *models.CaseForkOrigin
+	copied := make([]models.Message, 0, len(messages))
+	// Copies get new IDs, so thread replies are pointed at the copy of their parent.
+	copiedIDs := make(map[primitive.ObjectID]primitive.ObjectID, len(messages))
+	for _, message := range messages {
+		if message.Sequence > position {
+			break
+		}
+		forked := forkMessage(message)
+		copiedIDs[message.ID] = forked.ID
+		if message.IsReply() {
+			parentID, ok := copiedIDs[message.ParentMessageID]
+			if !ok {
+				// The parent was deleted before the fork; the reply has no thread to join.
+				continue
+			}
+			forked.ParentMessageID = parentID
+		}
+		copied = append(copied, forked)
+		if message.Sequence == position {
+			origin = &models.CaseForkOrigin{CaseID: id, MessageID: message.ID, Position: position}
+		}
+	}
+	if origin == nil {
+		return nil, errors.NewNotFoundError("No message at this position", "message_not_found")
+	}
+
+	forkID := primitive.NewObjectID()
+	if err := s.forkDocuments(ctx, forkID, callerID, copied); err != nil {
+		s.logger.Error("Service Level: Failed to copy documents to fork", err)
+		s.releaseForkDocuments(ctx, forkID)
+		return nil, errors.NewDatabaseError("Failed to fork case", "fork_case_failed")
+	}
+
+	now := time.Now()
+	fork := &models.Case{
+		ID:            forkID,
+		Name:          request.Name.OrElse(source.Name + " (fork)"),
+		CreatorID:     callerID,
+		Messages:      copied,
+		Collaborators: []models.Collaborators{},
+		Action:        source.Action,
+		AgentID:       source.AgentID,
+		CreationDate:  now,
+		LastEdit:      now,
+		ForkedFrom:    origin,
+	}
+	forkedCase, err := s.insertCase(ctx, fork)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to store forked case", err)
+		s.releaseForkDocuments(ctx, forkID)
+		return nil, errors.NewDatabaseError("Failed to fork case", "fork_case_failed")
+	}
+	s.logger.Info("Service Level: Successfully forked case")
+	return forkedCase, nil
+}
+
+// GetCaseForks lists the forks of a case that the caller can see, newest first.
+// Messages are not included.
+func (s *CaseServiceImpl) GetCaseForks(ctx context.Context, id, callerID primitive.ObjectID) ([]dtos.CaseResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve case forks")
+	if err := s.AuthorizeCaseAccess(ctx, id, callerID); err != nil {
+		return nil, err
+	}
+	forks, err := s.caseRepo.GetCaseForks(ctx, id, callerID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve case forks", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve case forks", "get_case_forks_failed")
+	}
+	caseResponses := s.mapper.CasesToDTO(forks)
+	if caseResponses == nil {
+		caseResponses = []dtos.CaseResponse{}
+	}
+	s.logger.Info("Service Level: Successfully retrieved case forks")
+	return caseResponses, nil
+}
+
+// forkDocuments copies the documents the forked messages refer to into the fork,
+// sharing their content, and points the messages at the copies. Document paths
+// derived from the original document follow the copy.
+func (s *CaseServiceImpl) forkDocuments(ctx context.Context, forkID, callerID primitive.ObjectID, messages []models.Message) error {
+	copiedIDs := make(map[primitive.ObjectID]primitive.ObjectID)
+	for i := range messages {
+		documentID := messages[i].DocumentID
+		if documentID.IsZero() {
+			continue
+		}
+		copiedID, ok := copiedIDs[documentID]
+		if !ok {
+			document, err := s.documentRepo.GetDocumentByID(ctx, documentID)
+			if stderrors.Is(err, daos.ErrDocumentNotFound) {
+				messages[i].DocumentID = primitive.NilObjectID
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			copiedDocument, err := s.documentRepo.CopyDocument(ctx, document, forkID, callerID)
+			if err != nil {
+				return err
+			}
+			copiedID = copiedDocument.ID
+			copiedIDs[documentID] = copiedID
+		}
+		messages[i].DocumentID = copiedID
+		if messages[i].DocumentPath == models.DocumentPath(documentID) {
+			messages[i].DocumentPath = models.DocumentPath(copiedID)
+		}
+	}
+	return nil
+}
+
+// releaseForkDocuments removes the document copies of a fork that could not be stored.
+func (s *CaseServiceImpl) releaseForkDocuments(ctx context.Context, forkID primitive.ObjectID) {
+	if err := s.documentRepo.DeleteDocuments(ctx, forkID); err != nil {
+		s.logger.Error("Service Level: Failed to remove documents of unstored fork", err)
+	}
+}
+
+// forkMessage copies a message into a fork under a new ID. The copy starts with a
+// clean edit history; its document is pointed at the fork's copy by forkDocuments.
+func forkMessage(message models.Message) models.Message {
+	return models.Message{
+		ID:           primitive.NewObjectID(),
+		Sender:       message.Sender,
+		Recipient:    message.Recipient,
+		Content:      message.Content,
+		DocumentPath: message.DocumentPath,
+		DocumentID:   message.DocumentID,
+		FunctionCall: message.FunctionCall,
+		ToolCalls:    message.ToolCalls,
+		ToolResult:   message.ToolResult,
+		AuthorUserID: message.AuthorUserID,
+		CreatedAt:    message.CreatedAt,
+	}
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultImportRecipient is the name Autogen gives the GroupChatManager, which
+// receives every message of a group chat.
+const defaultImportRecipient = "chat_manager"
+
+// autogenMessage is one entry of Autogen's `groupchat.messages` list. Content is
+// a string, null for pure function calls, or a list of parts for multimodal agents.
+// Tool results either answer a single call through ToolCallID or bundle several
+// answers in ToolResponses.
+type autogenMessage struct {
+	Name          string                `json:"name"`
+	Role          string                `json:"role"`
+	Content       json.RawMessage       `json:"content"`
+	FunctionCall  *autogenFunction      `json:"function_call"`
+	ToolCalls     []autogenToolCall     `json:"tool_calls"`
+	ToolCallID    string                `json:"tool_call_id"`
+	ToolResponses []autogenToolResponse `json:"tool_responses"`
+}
+
+// autogenFunction is a function invocation. Arguments are usually a string of
+// JSON, as produced by the model.
+type autogenFunction struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+type autogenToolCall struct {
+	ID       string          `json:"id"`
+	Function autogenFunction `json:"function"`
+}
+
+type autogenToolResponse struct {
+	ToolCallID string          `json:"tool_call_id"`
+	Content    json.RawMessage `json:"content"`
+}
+
+type autogenContentPart struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+var autogenRoles = map[string]bool{
+	"user":      true,
+	"assistant": true,
+	"system":    true,
+	"function":  true,
+	"tool":      true,
+}
+
+// DecodeImportTranscript reads an import request: either the request object or
+// a bare Autogen message list, as found in `groupchat.messages` dumps.
+func DecodeImportTranscript(data []byte) (dtos.ImportTranscriptRequest, error) {
+	var request dtos.ImportTranscriptRequest
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		err := json.Unmarshal(trimmed, &request.Messages)
+		return request, err
+	}
+	err := json.Unmarshal(trimmed, &request)
+	return request, err
+}
+
+// ImportTranscript creates a case for the caller from an Autogen transcript.
+// Invalid messages are skipped and reported; the import only fails when no
+// message can be imported.
+func (s *CaseServiceImpl) ImportTranscript(ctx context.Context, creatorID primitive.ObjectID, request dtos.ImportTranscriptRequest) (*dtos.ImportTranscriptResponse, error) {
+	s.logger.Info("Service Level: Attempting to import transcript")
+	recipient := request.Recipient.OrElse(defaultImportRecipient)
+	report := &dtos.ImportTranscriptResponse{Errors: []dtos.ImportMessageError{}}
+
+	messages := make([]dtos.MessageResponse, 0, len(request.Messages))
+	for i, raw := range request.Messages {
+		parsed, err := parseAutogenMessage(raw, recipient)
+		if err != nil {
+			report.Errors = append(report.Errors, dtos.ImportMessageError{Index: i, Error: err.Error()})
+			continue
+		}
+		messages = append(messages, parsed...)
+		report.Imported++
+	}
+	report.Skipped = len(report.Errors)
+	if len(messages) == 0 {
+		s.logger.Warn("Service Level: Transcript contains no importable messages")
+		return report, errors.NewIncorrectInputError("The transcript contains no valid messages", "import_no_valid_messages")
+	}
+
+	createRequest := dtos.CreateCaseRequest{
+		Name:      helpers.NewNullable(request.Name.OrElse("Imported transcript " + time.Now().Format("2006-01-02"))),
+		CreatorID: helpers.NewNullable(creatorID),
+		Messages:  helpers.NewNullable(messages),
+		AgentID:   request.AgentID,
+	}
+	created, err := s.CreateCase(ctx, createRequest)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to create case for transcript", err)
+		return nil, errors.NewDatabaseError("Failed to create case", "import_create_case_failed")
+	}
+	report.Case = created
+	s.logger.Info("Service Level: Successfully imported transcript")
+	return report, nil
+}
+
+// parseAutogenMessage validates an Autogen message and maps it onto message DTOs.
+// Function and tool calls are kept as structured calls, and tool results as
+// results linked to their call. A message bundling several tool responses
+// becomes one message per response.
+func parseAutogenMessage(raw json.RawMessage, recipient string) ([]dtos.MessageResponse, error) {
+	var source autogenMessage
+	if err := json.Unmarshal(raw, &source); err != nil {
+		return nil, fmt.Errorf("message is not a JSON object: %v", err)
+	}
+	if source.Role != "" && !autogenRoles[source.Role] {
+		return nil, fmt.Errorf("unknown role %q", source.Role)
+	}
+	sender := strings.TrimSpace(source.Name)
+	if sender == "" {
+		sender = source.Role
+	}
+	if sender == "" {
+		return nil, fmt.Errorf("message has neither a name nor a role")
+	}
+
+	message := dtos.MessageResponse{
+		Sender:    helpers.NewNullable(sender),
+		Recipient: helpers.NewNullable(recipient),
+	}
+	if len(source.ToolResponses) > 0 {
+		messages := make([]dtos.MessageResponse, 0, len(source.ToolResponses))
+		for _, response := range source.ToolResponses {
+			content, err := autogenContent(response.Content)
+			if err != nil {
+				return nil, err
+			}
+			result := message
+			result.Content = helpers.Nullable[string]{Value: content, Present: true}
+			result.FunctionCall = helpers.NewNullable(true)
+			result.ToolResult = autogenToolResult(response.ToolCallID, "", content)
+			messages = append(messages, result)
+		}
+		return messages, nil
+	}
+
+	content, err := autogenContent(source.Content)
+	if err != nil {
+		return nil, err
+	}
+	var calls []dtos.ToolCallResponse
+	if source.FunctionCall != nil {
+		call, err := autogenCall("", *source.FunctionCall)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+	}
+	for _, toolCall := range source.ToolCalls {
+		call, err := autogenCall(toolCall.ID, toolCall.Function)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+	}
+	switch {
+	case len(calls) > 0:
+		message.ToolCalls = helpers.NewNullable(calls)
+	case source.Role == "tool":
+		message.ToolResult = autogenToolResult(source.ToolCallID, "", content)
+	case source.Role == "function":
+		// Results of older function calls carry the function name as the name.
+		message.ToolResult = autogenToolResult("", sender, content)
+	}
+	if content == "" && len(calls) == 0 {
+		return nil, fmt.Errorf("message has no content")
+	}
+	message.Content = helpers.Nullable[string]{Value: content, Present: true}
+	message.FunctionCall = helpers.NewNullable(len(calls) > 0 || source.Role == "function" || source.Role == "tool")
+	return []dtos.MessageResponse{message}, nil
+}
+
+// autogenCall maps a function invocation onto a tool call. Arguments given as a
+// string of JSON are unwrapped; other non-empty strings are kept as JSON strings.
+func autogenCall(callID string, function autogenFunction) (dtos.ToolCallResponse, error) {
+	name := strings.TrimSpace(function.Name)
+	if name == "" {
+		return dtos.ToolCallResponse{}, fmt.Errorf("function call has no name")
+	}
+	arguments := bytes.TrimSpace(function.Arguments)
+	var encoded string
+	if err := json.Unmarshal(arguments, &encoded); err == nil {
+		if strings.TrimSpace(encoded) == "" {
+			arguments = nil
+		} else if json.Valid([]byte(encoded)) {
+			arguments = []byte(encoded)
+		}
+	}
+	if isJSONNull(arguments) {
+		arguments = nil
+	} else if !json.Valid(arguments) {
+		return dtos.ToolCallResponse{}, fmt.Errorf("invalid arguments for function %q", name)
+	}
+	return dtos.ToolCallResponse{
+		CallID:    helpers.NewNullable(strings.TrimSpace(callID)),
+		Name:      helpers.NewNullable(name),
+		Arguments: helpers.NewNullable(json.RawMessage(arguments)),
+	}, nil
+}
+
+// autogenToolResult describes the outcome of a call. Results that name neither
+// their call nor their function cannot be linked and are left absent. Autogen
+// does not record whether a call succeeded; its function executor reports
+// failures as content starting with "Error:", which is taken as the error status.
+func autogenToolResult(callID, name, content string) helpers.Nullable[dtos.ToolResultResponse] {
+	callID = strings.TrimSpace(callID)
+	if callID == "" && name == "" {
+		return helpers.Nullable[dtos.ToolResultResponse]{}
+	}
+	status := models.ToolStatusSuccess
+	if strings.HasPrefix(content, "Error:") {
+		status = models.ToolStatusError
+	}
+	return helpers.NewNullable(dtos.ToolResultResponse{
+		CallID: helpers.NewNullable(callID),
+		Name:   helpers.NewNullable(name),
+		Status: helpers.NewNullable(status),
+	})
+}
+
+// autogenContent flattens Autogen message content into plain text.
+func autogenContent(raw json.RawMessage) (string, error) {
+	if isJSONNull(raw) {
+		return "", nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return strings.TrimSpace(text), nil
+	}
+	var parts []autogenContentPart
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return "", fmt.Errorf("content must be a string or a list of parts")
+	}
+	texts := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part.Type == "text" && strings.TrimSpace(part.Text) != "" {
+			texts = append(texts, strings.TrimSpace(part.Text))
+		}
+	}
+	return strings.Join(texts, "\n\n"), nil
+}
+
+func isJSONNull(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null"))
+}
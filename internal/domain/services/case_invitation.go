@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/notify"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// caseInvitationTTL is how long an invitation can be accepted.
+const caseInvitationTTL = 14 * 24 * time.Hour
+
+// inviteCollaborator stores a pending invitation for an email that has no account
+// yet and hands it to the notifier. A failed delivery is only logged: the
+// invitation still resolves when the invitee signs up with that email.
+func (s *CaseServiceImpl) inviteCollaborator(ctx context.Context, caseModel models.Case, actorID primitive.ObjectID, email, role string) (*dtos.CaseInvitationResponse, error) {
+	s.logger.Info("Service Level: Attempting to invite collaborator to case")
+	token, err := newSecretToken()
+	if err != nil {
+		s.logger.Error("Service Level: Failed to generate invitation token", err)
+		return nil, errors.NewSlugError("Failed to invite collaborator", "invite_collaborator_failed", errors.ErrorTypeUnknown)
+	}
+	now := time.Now()
+	invitation := models.CaseInvitation{
+		CaseID:    caseModel.ID,
+		Email:     normalizeEmail(email),
+		Role:      role,
+		TokenHash: hashSecretToken(token),
+		InvitedBy: actorID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(caseInvitationTTL),
+	}
+	if err := s.inviteRepo.CreateInvitation(ctx, &invitation); err != nil {
+		s.logger.Error("Service Level: Failed to store case invitation", err)
+		return nil, errors.NewDatabaseError("Failed to invite collaborator", "invite_collaborator_failed")
+	}
+	s.recordActivity(ctx, caseModel.ID, actorID, models.CaseActionInviteCollaborator)
+	if s.notifier != nil {
+		err := s.notifier.NotifyCaseInvitation(ctx, notify.CaseInvitation{
+			Email:       invitation.Email,
+			CaseID:      caseModel.ID.Hex(),
+			CaseName:    caseModel.Name,
+			Role:        role,
+			InviterName: s.exportParticipant(ctx, actorID).Name,
+			Token:       token,
+			ExpiresAt:   invitation.ExpiresAt,
+		})
+		if err != nil {
+			s.logger.Error("Service Level: Failed to deliver case invitation", err, zap.String("invitation_id", invitation.ID.Hex()))
+		}
+	}
+	response := s.mapper.CaseInvitationToDTO(invitation)
+	s.logger.Info("Service Level: Successfully invited collaborator to case")
+	return &response, nil
+}
+
+// GetCaseInvitations lists the pending invitations to a case. Only owners may
+// list them.
+func (s *CaseServiceImpl) GetCaseInvitations(ctx context.Context, id, callerID primitive.ObjectID) ([]dtos.CaseInvitationResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve case invitations")
+	if err := s.AuthorizeCaseRole(ctx, id, callerID, models.CaseRoleOwner); err != nil {
+		return nil, err
+	}
+	invitations, err := s.inviteRepo.GetPendingInvitations(ctx, id)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve case invitations", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve case invitations", "get_case_invitations_failed")
+	}
+	s.logger.Info("Service Level: Successfully retrieved case invitations")
+	return s.mapper.CaseInvitationsToDTO(invitations), nil
+}
+
+// RevokeCaseInvitation withdraws a pending invitation. Only owners may revoke
+// invitations.
+func (s *CaseServiceImpl) RevokeCaseInvitation(ctx context.Context, id, invitationID, callerID primitive.ObjectID) error {
+	s.logger.Info("Service Level: Attempting to revoke case invitation")
+	if err := s.AuthorizeCaseRole(ctx, id, callerID, models.CaseRoleOwner); err != nil {
+		return err
+	}
+	if err := s.inviteRepo.RevokeInvitation(ctx, id, invitationID); err != nil {
+		if stderrors.Is(err, daos.ErrInvitationNotFound) {
+			return errors.NewNotFoundError("Invitation not found", "case_invitation_not_found")
+		}
+		s.logger.Error("Service Level: Failed to revoke case invitation", err)
+		return errors.NewDatabaseError("Failed to revoke case invitation", "revoke_case_invitation_failed")
+	}
+	s.recordActivity(ctx, id, callerID, models.CaseActionRevokeInvitation)
+	s.logger.Info("Service Level: Successfully revoked case invitation")
+	return nil
+}
+
+// AcceptCaseInvitation makes the caller a collaborator on the case an invitation
+// token was issued for. Holding the token is enough: the caller's email does not
+// have to match the invited one.
+func (s *CaseServiceImpl) AcceptCaseInvitation(ctx context.Context, token string, callerID primitive.ObjectID) (*dtos.CaseResponse, error) {
+	s.logger.Info("Service Level: Attempting to accept case invitation")
+	notFound := errors.NewNotFoundError("Invitation not found", "case_invitation_not_found")
+	if token == "" {
+		return nil, notFound
+	}
+	invitation, err := s.inviteRepo.GetInvitationByTokenHash(ctx, hashSecretToken(token))
+	if err != nil {
+		if stderrors.Is(err, daos.ErrInvitationNotFound) {
+			return nil, notFound
+		}
+		s.logger.Error("Service Level: Failed to retrieve case invitation", err)
+		return nil, errors.NewDatabaseError("Failed to accept case invitation", "accept_case_invitation_failed")
+	}
+	if !invitation.Pending(time.Now()) {
+		s.logger.Warn("Service Level: Case invitation already accepted or expired")
+		return nil, errors.NewNotFoundError("Invitation has expired or was already accepted", "case_invitation_inactive")
+	}
+	if err := s.acceptInvitation(ctx, invitation, callerID); err != nil {
+		return nil, err
+	}
+	acceptedCase, err := s.loadCaseResponse(ctx, invitation.CaseID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve case of accepted invitation", err)
+		return nil, err
+	}
+	s.logger.Info("Service Level: Successfully accepted case invitation")
+	return acceptedCase, nil
+}
+
+// ResolveInvitations accepts, on behalf of a newly created user, every pending
+// invitation addressed to their email, returning how many were resolved. Each
+// invitation is resolved on its own so one failure does not hold back the rest.
+func (s *CaseServiceImpl) ResolveInvitations(ctx context.Context, userID primitive.ObjectID, email string) (int, error) {
+	s.logger.Info("Service Level: Attempting to resolve pending case invitations")
+	email = normalizeEmail(email)
+	if email == "" {
+		return 0, nil
+	}
+	invitations, err := s.inviteRepo.GetPendingInvitationsForEmail(ctx, email)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve pending case invitations", err)
+		return 0, errors.NewDatabaseError("Failed to resolve case invitations", "resolve_case_invitations_failed")
+	}
+	resolved := 0
+	var firstErr error
+	for _, invitation := range invitations {
+		if err := s.acceptInvitation(ctx, invitation, userID); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		resolved++
+	}
+	s.logger.Info("Service Level: Resolved pending case invitations", zap.Int("resolved", resolved))
+	return resolved, firstErr
+}
+
+// acceptInvitation turns an invitation into a collaborator entry on a live case.
+// The case creator is already its owner, so their invitation is only marked as
+// accepted.
+func (s *CaseServiceImpl) acceptInvitation(ctx context.Context, invitation models.CaseInvitation, userID primitive.ObjectID) error {
+	caseModel, err := s.caseRepo.GetCaseByID(ctx, invitation.CaseID)
+	if err != nil {
+		if stderrors.Is(err, daos.ErrCaseNotFound) {
+			return errors.NewNotFoundError("Case not found", "case_not_found")
+		}
+		s.logger.Error("Service Level: Failed to retrieve case of invitation", err)
+		return errors.NewDatabaseError("Failed to accept case invitation", "accept_case_invitation_failed")
+	}
+	added := false
+	if caseModel.CreatorID == userID {
+		err = s.inviteRepo.MarkInvitationAccepted(ctx, invitation, userID, time.Now())
+	} else {
+		added, err = s.inviteRepo.AcceptInvitation(ctx, invitation, userID, time.Now())
+	}
+	if err != nil {
+		if stderrors.Is(err, daos.ErrInvitationNotFound) {
+			return errors.NewNotFoundError("Invitation has expired or was already accepted", "case_invitation_inactive")
+		}
+		if stderrors.Is(err, daos.ErrCaseNotFound) {
+			return errors.NewNotFoundError("Case not found", "case_not_found")
+		}
+		s.logger.Error("Service Level: Failed to accept case invitation", err)
+		return errors.NewDatabaseError("Failed to accept case invitation", "accept_case_invitation_failed")
+	}
+	if added {
+		s.recordMutation(ctx, invitation.CaseID, userID, models.CaseActionAddCollaborator, "collaborators")
+		s.publishCollaboratorAdded(invitation.CaseID, userID, invitation.Role)
+	}
+	return nil
+}
+
+// normalizeEmail returns the form under which invitation emails are stored and matched.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxTagLength = 64
+
+// BulkTagCases adds and removes tags on several cases the caller can access.
+func (s *CaseServiceImpl) BulkTagCases(ctx context.Context, callerID primitive.ObjectID, request dtos.BulkTagRequest) (*dtos.BulkTagResponse, error) {
+	s.logger.Info("Service Level: Attempting to tag cases")
+	if len(request.CaseIDs) == 0 {
+		return nil, errors.NewIncorrectInputError("At least one case ID is required", "case_ids_required")
+	}
+	add, err := normalizeTags(request.Add)
+	if err != nil {
+		return nil, err
+	}
+	remove, err := normalizeTags(request.Remove)
+	if err != nil {
+		return nil, err
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, errors.NewIncorrectInputError("No tags to add or remove", "tags_required")
+	}
+	updated, err := s.caseRepo.UpdateCaseTags(ctx, request.CaseIDs, callerID, add, remove)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to tag cases", err)
+		return nil, errors.NewDatabaseError("Failed to tag cases", "tag_cases_failed")
+	}
+	for _, id := range updated {
+		s.recordMutation(ctx, id, callerID, models.CaseActionTag, "tags")
+	}
+	s.logger.Info("Service Level: Successfully tagged cases")
+	return &dtos.BulkTagResponse{Updated: int64(len(updated))}, nil
+}
+
+// caseListFilter resolves a listing query into a filter, expanding the folder into
+// its whole subtree.
+func (s *CaseServiceImpl) caseListFilter(ctx context.Context, ownerID primitive.ObjectID, query dtos.CaseListQuery) (models.CaseListFilter, error) {
+	var filter models.CaseListFilter
+	tags, err := normalizeTags(query.Tags)
+	if err != nil {
+		return filter, err
+	}
+	filter.Tags = tags
+	if !query.FolderID.Present {
+		return filter, nil
+	}
+	folder, err := s.loadCaseFolder(ctx, query.FolderID.Value, ownerID)
+	if err != nil {
+		return filter, err
+	}
+	filter.FolderIDs, err = s.folderRepo.GetSubtreeIDs(ctx, folder.ID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve subfolders", err)
+		return filter, errors.NewDatabaseError("Failed to retrieve folders", "get_folders_failed")
+	}
+	return filter, nil
+}
+
+// validateCaseOrganization checks the folder and tags of a case update. The
+// folder must belong to the case creator, since folders are personal.
+func (s *CaseServiceImpl) validateCaseOrganization(ctx context.Context, id primitive.ObjectID, updates *dtos.UpdateCaseRequest) error {
+	if updates.Tags.Present {
+		tags, err := normalizeTags(updates.Tags.Value)
+		if err != nil {
+			return err
+		}
+		updates.Tags.Value = tags
+	}
+	if !updates.FolderID.Present || updates.FolderID.Value.IsZero() {
+		return nil
+	}
+	caseModel, err := s.caseRepo.GetCaseByID(ctx, id)
+	if err != nil {
+		if stderrors.Is(err, daos.ErrCaseNotFound) {
+			return errors.NewNotFoundError("Case not found", "case_not_found")
+		}
+		s.logger.Error("Service Level: Failed to retrieve case", err)
+		return errors.NewDatabaseError("Failed to retrieve case", "get_case_failed")
+	}
+	_, err = s.loadCaseFolder(ctx, updates.FolderID.Value, caseModel.CreatorID)
+	return err
+}
+
+func (s *CaseServiceImpl) loadCaseFolder(ctx context.Context, id, ownerID primitive.ObjectID) (models.Folder, error) {
+	folder, err := s.folderRepo.GetFolderByID(ctx, id)
+	if err != nil {
+		if stderrors.Is(err, daos.ErrFolderNotFound) {
+			return folder, errors.NewNotFoundError("Folder not found", "folder_not_found")
+		}
+		s.logger.Error("Service Level: Failed to retrieve folder", err)
+		return folder, errors.NewDatabaseError("Failed to retrieve folder", "get_folder_failed")
+	}
+	if folder.OwnerID != ownerID {
+		return folder, errors.NewNotFoundError("Folder not found", "folder_not_found")
+	}
+	return folder, nil
+}
+
+// normalizeTags trims tags and drops blanks and duplicates, keeping their order.
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return nil, errors.NewIncorrectInputError("Tags are limited to 64 characters", "tag_too_long")
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultSearchResults = 20
+	maxSearchResults     = 100
+	maxSearchMessages    = 500
+	maxSearchDocuments   = 100
+	snippetRadius        = 60
+)
+
+// SearchCases searches the names, message content and document text of the
+// cases the caller created or collaborates on. Name and document matches are
+// only considered for plain text queries; a sender or date filter restricts the
+// search to messages.
+func (s *CaseServiceImpl) SearchCases(ctx context.Context, callerID primitive.ObjectID, query dtos.CaseSearchQuery) (*dtos.CaseSearchResponse, error) {
+	s.logger.Info("Service Level: Attempting to search cases")
+	query.Query = strings.TrimSpace(query.Query)
+	query.Sender = strings.TrimSpace(query.Sender)
+	if query.Query == "" && query.Sender == "" && !query.From.Present && !query.To.Present {
+		return nil, errors.NewIncorrectInputError("A search query, sender or date range is required", "search_query_empty")
+	}
+	if query.From.Present && query.To.Present && query.To.Value.Before(query.From.Value) {
+		return nil, errors.NewIncorrectInputError("The end of the date range must not precede its start", "invalid_search_range")
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultSearchResults
+	}
+	if limit > maxSearchResults {
+		limit = maxSearchResults
+	}
+
+	accessible, err := s.caseRepo.GetAccessibleCases(ctx, callerID, query.AgentID.Value)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve accessible cases", err)
+		return nil, errors.NewDatabaseError("Failed to search cases", "search_cases_failed")
+	}
+	response := &dtos.CaseSearchResponse{
+		Results: helpers.NewNullable([]dtos.CaseSearchHit{}),
+		Total:   helpers.Nullable[int]{Value: 0, Present: true},
+	}
+	if len(accessible) == 0 {
+		return response, nil
+	}
+	cases := make(map[primitive.ObjectID]models.Case, len(accessible))
+	ids := make([]primitive.ObjectID, len(accessible))
+	for i, caseModel := range accessible {
+		cases[caseModel.ID] = caseModel
+		ids[i] = caseModel.ID
+	}
+
+	hits := make(map[primitive.ObjectID]*dtos.CaseSearchHit)
+	var order []primitive.ObjectID
+	hitFor := func(id primitive.ObjectID) *dtos.CaseSearchHit {
+		if hit, ok := hits[id]; ok {
+			return hit
+		}
+		caseModel := cases[id]
+		hit := &dtos.CaseSearchHit{
+			CaseID:    helpers.NewNullable(caseModel.ID),
+			Name:      helpers.NewNullable(caseModel.Name),
+			AgentID:   helpers.NewNullable(caseModel.AgentID),
+			LastEdit:  helpers.NewNullable(caseModel.LastEdit),
+			NameMatch: helpers.Nullable[bool]{Value: false, Present: true},
+			Matches:   helpers.NewNullable([]dtos.MessageSearchMatch{}),
+			Documents: helpers.NewNullable([]dtos.DocumentSearchMatch{}),
+		}
+		hits[id] = hit
+		order = append(order, id)
+		return hit
+	}
+
+	plainQuery := query.Query != "" && query.Sender == "" && !query.From.Present && !query.To.Present
+	if plainQuery {
+		named, err := s.caseRepo.SearchCaseNames(ctx, ids, query.Query)
+		if err != nil {
+			s.logger.Error("Service Level: Failed to search case names", err)
+			return nil, errors.NewDatabaseError("Failed to search cases", "search_cases_failed")
+		}
+		for _, result := range named {
+			hit := hitFor(result.ID)
+			hit.NameMatch = helpers.NewNullable(true)
+			hit.Score = helpers.NewNullable(result.Score)
+		}
+	}
+
+	messages, err := s.messageRepo.SearchMessages(ctx, ids, models.MessageSearchFilter{
+		Query:  query.Query,
+		Sender: query.Sender,
+		From:   query.From.Value,
+		To:     query.To.Value,
+	}, maxSearchMessages)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to search messages", err)
+		return nil, errors.NewDatabaseError("Failed to search cases", "search_cases_failed")
+	}
+	terms := searchTerms(query.Query)
+	for _, result := range messages {
+		hit := hitFor(result.CaseID)
+		if result.Score > hit.Score.Value {
+			hit.Score = helpers.NewNullable(result.Score)
+		}
+		snippet, offset := messageSnippet(result.Content, terms)
+		match := dtos.MessageSearchMatch{
+			MessageID: helpers.NewNullable(result.ID),
+			Position:  helpers.Nullable[int]{Value: result.Sequence, Present: true},
+			Sender:    helpers.NewNullable(result.Sender),
+			CreatedAt: helpers.NewNullable(result.CreatedAt),
+			Snippet:   helpers.NewNullable(snippet),
+		}
+		if offset >= 0 {
+			match.Offset = helpers.Nullable[int]{Value: offset, Present: true}
+		}
+		hit.Matches = helpers.NewNullable(append(hit.Matches.Value, match))
+	}
+
+	if plainQuery {
+		documents, err := s.documentRepo.SearchDocuments(ctx, ids, query.Query, maxSearchDocuments)
+		if err != nil {
+			s.logger.Error("Service Level: Failed to search documents", err)
+			return nil, errors.NewDatabaseError("Failed to search cases", "search_cases_failed")
+		}
+		for _, result := range documents {
+			hit := hitFor(result.CaseID)
+			if result.Score > hit.Score.Value {
+				hit.Score = helpers.NewNullable(result.Score)
+			}
+			snippet, offset := messageSnippet(result.Text, terms)
+			match := dtos.DocumentSearchMatch{
+				DocumentID: helpers.NewNullable(result.ID),
+				FileName:   helpers.NewNullable(result.FileName),
+				Path:       helpers.NewNullable(models.DocumentPath(result.ID)),
+				Snippet:    helpers.NewNullable(snippet),
+			}
+			if offset >= 0 {
+				match.Offset = helpers.Nullable[int]{Value: offset, Present: true}
+			}
+			hit.Documents = helpers.NewNullable(append(hit.Documents.Value, match))
+		}
+	}
+
+	// Without a text query every score is zero and the stable sort keeps cases in
+	// the order of their newest matching message.
+	sort.SliceStable(order, func(i, j int) bool {
+		return hits[order[i]].Score.Value > hits[order[j]].Score.Value
+	})
+	results := make([]dtos.CaseSearchHit, 0, len(order))
+	for _, id := range order {
+		hit := hits[id]
+		sort.SliceStable(hit.Matches.Value, func(i, j int) bool {
+			return hit.Matches.Value[i].Position.Value < hit.Matches.Value[j].Position.Value
+		})
+		results = append(results, *hit)
+	}
+	response.Total = helpers.Nullable[int]{Value: len(results), Present: true}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	response.Results = helpers.NewNullable(results)
+	s.logger.Info("Service Level: Successfully searched cases")
+	return response, nil
+}
+
+// searchTerms extracts the words of a text search, ignoring negated terms and
+// quoting so they can be highlighted in message snippets.
+func searchTerms(query string) []string {
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		field = strings.Trim(field, `"`)
+		if field != "" {
+			terms = append(terms, regexp.QuoteMeta(field))
+		}
+	}
+	return terms
+}
+
+// messageSnippet cuts the content around the first occurrence of any term and
+// returns it with the character offset of that occurrence. When no term occurs
+// literally (e.g. a stemmed match) the start of the content is returned with an
+// offset of -1.
+func messageSnippet(content string, terms []string) (string, int) {
+	start, end, offset := 0, 0, -1
+	if len(terms) > 0 {
+		pattern := regexp.MustCompile(`(?i)` + strings.Join(terms, "|"))
+		if loc := pattern.FindStringIndex(content); loc != nil {
+			start, end = loc[0], loc[1]
+			offset = utf8.RuneCountInString(content[:start])
+		}
+	}
+
+	from := start
+	for i := 0; i < snippetRadius && from > 0; i++ {
+		_, size := utf8.DecodeLastRuneInString(content[:from])
+		from -= size
+	}
+	to := end
+	for i := 0; i < snippetRadius && to < len(content); i++ {
+		_, size := utf8.DecodeRuneInString(content[to:])
+		to += size
+	}
+
+	snippet := content[from:to]
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(content) {
+		snippet += "…"
+	}
+	return snippet, offset
+}
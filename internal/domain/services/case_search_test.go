@@ -0,0 +1,88 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMessageSnippet(t *testing.T) {
+	long := strings.Repeat("a", 100) + "needle" + strings.Repeat("b", 100)
+	accented := strings.Repeat("é", 70) + "needle"
+
+	tests := []struct {
+		name        string
+		content     string
+		terms       []string
+		wantSnippet string
+		wantOffset  int
+	}{
+		{
+			name:        "short content is returned whole",
+			content:     "The contract was signed",
+			terms:       []string{"contract"},
+			wantSnippet: "The contract was signed",
+			wantOffset:  4,
+		},
+		{
+			name:        "matching ignores case",
+			content:     "CONTRACT terms",
+			terms:       []string{"contract"},
+			wantSnippet: "CONTRACT terms",
+			wantOffset:  0,
+		},
+		{
+			name:        "earliest occurrence of any term wins",
+			content:     "lease and contract",
+			terms:       []string{"contract", "lease"},
+			wantSnippet: "lease and contract",
+			wantOffset:  0,
+		},
+		{
+			name:        "long content is cut around the match",
+			content:     long,
+			terms:       []string{"needle"},
+			wantSnippet: "…" + strings.Repeat("a", snippetRadius) + "needle" + strings.Repeat("b", snippetRadius) + "…",
+			wantOffset:  100,
+		},
+		{
+			name:        "offset and cut count characters, not bytes",
+			content:     accented,
+			terms:       []string{"needle"},
+			wantSnippet: "…" + strings.Repeat("é", snippetRadius) + "needle",
+			wantOffset:  70,
+		},
+		{
+			name:        "no literal match starts at the beginning",
+			content:     long,
+			terms:       []string{"missing"},
+			wantSnippet: strings.Repeat("a", snippetRadius) + "…",
+			wantOffset:  -1,
+		},
+		{
+			name:        "no terms starts at the beginning",
+			content:     "short",
+			terms:       nil,
+			wantSnippet: "short",
+			wantOffset:  -1,
+		},
+		{
+			name:        "terms from searchTerms match literally",
+			content:     "see art. 5 and arts 6",
+			terms:       searchTerms(`"art." -arts`),
+			wantSnippet: "see art. 5 and arts 6",
+			wantOffset:  4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snippet, offset := messageSnippet(tt.content, tt.terms)
+			if snippet != tt.wantSnippet {
+				t.Errorf("snippet = %q, want %q", snippet, tt.wantSnippet)
+			}
+			if offset != tt.wantOffset {
+				t.Errorf("offset = %d, want %d", offset, tt.wantOffset)
+			}
+		})
+	}
+}
@@ -2,53 +2,236 @@ package services
 
 import (
 	"context"
+	stderrors "errors"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/events"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/export"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/notify"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/pricing"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/repositories"
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/services/mappers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // CaseService defines the operations available for managing cases.
 type CaseService interface {
-	GetAllCases(ctx context.Context) ([]dtos.CaseResponse, error)
-	GetCaseByID(ctx context.Context, id primitive.ObjectID) (dtos.CaseResponse, error)
-	GetCasesByCreatorID(ctx context.Context, creatorID primitive.ObjectID) ([]dtos.CaseResponse, error)
+	GetAllCases(ctx context.Context, callerID primitive.ObjectID) ([]dtos.CaseResponse, error)
+	GetCaseByID(ctx context.Context, id, callerID primitive.ObjectID, mainLine bool) (dtos.CaseResponse, error)
+	GetCasesByCreatorID(ctx context.Context, creatorID primitive.ObjectID, query dtos.CaseListQuery) ([]dtos.CaseResponse, error)
 	CreateCase(ctx context.Context, caseRequest dtos.CreateCaseRequest) (dtos.CaseResponse, error)
-	UpdateCase(ctx context.Context, id primitive.ObjectID, updates map[string]interface{}) (dtos.CaseResponse, error)
-	DeleteCase(ctx context.Context, id primitive.ObjectID) (dtos.CaseResponse, error)
-	AddCollaboratorToCase(ctx context.Context, id, collaboratorID primitive.ObjectID) (dtos.CaseResponse, error)
-	RemoveCollaboratorFromCase(ctx context.Context, id, collaboratorID primitive.ObjectID) (dtos.CaseResponse, error)
+	UpdateCase(ctx context.Context, id, actorID primitive.ObjectID, updates map[string]interface{}) (dtos.CaseResponse, error)
+	DeleteCase(ctx context.Context, id, actorID primitive.ObjectID) (dtos.CaseResponse, error)
+	AddCollaboratorToCase(ctx context.Context, id, actorID primitive.ObjectID, request dtos.AddCollaboratorToCase) (*dtos.UserResponse, *dtos.CaseInvitationResponse, error)
+	RemoveCollaboratorFromCase(ctx context.Context, id, actorID, collaboratorID primitive.ObjectID) (dtos.CaseResponse, error)
+	UpdateCollaboratorRole(ctx context.Context, id, actorID, collaboratorID primitive.ObjectID, role string) (*dtos.CaseResponse, error)
+	AppendMessages(ctx context.Context, id, authorID primitive.ObjectID, messages []dtos.MessageResponse) ([]dtos.MessageResponse, error)
+	GetCaseMessages(ctx context.Context, id, callerID primitive.ObjectID, query dtos.MessagePageQuery) (*dtos.MessagePageResponse, error)
+	EditMessage(ctx context.Context, id, messageID, editorID primitive.ObjectID, request dtos.EditMessageRequest) (*dtos.MessageResponse, error)
+	DeleteMessage(ctx context.Context, id, messageID, deleterID primitive.ObjectID) (*dtos.MessageResponse, error)
+	GetMessageHistory(ctx context.Context, id, messageID, callerID primitive.ObjectID) (*dtos.MessageResponse, error)
+	GetMessageThread(ctx context.Context, id, messageID, callerID primitive.ObjectID) (*dtos.MessageThreadResponse, error)
+	SearchCases(ctx context.Context, callerID primitive.ObjectID, query dtos.CaseSearchQuery) (*dtos.CaseSearchResponse, error)
+	GetCaseVersions(ctx context.Context, id, callerID primitive.ObjectID) ([]dtos.CaseVersionResponse, error)
+	GetCaseVersion(ctx context.Context, id, callerID primitive.ObjectID, version int) (*dtos.CaseVersionResponse, error)
+	DiffCaseVersions(ctx context.Context, id, callerID primitive.ObjectID, from, to int) (*dtos.CaseVersionDiffResponse, error)
+	RestoreCaseVersion(ctx context.Context, id, actorID primitive.ObjectID, version int) (*dtos.CaseResponse, error)
+	GetTrashedCases(ctx context.Context, userID primitive.ObjectID) ([]dtos.CaseResponse, error)
+	RestoreCase(ctx context.Context, id, actorID primitive.ObjectID) (*dtos.CaseResponse, error)
+	PurgeTrashedCases(ctx context.Context, cutoff time.Time) (int, error)
+	ForkCase(ctx context.Context, id, callerID primitive.ObjectID, request dtos.ForkCaseRequest) (*dtos.CaseResponse, error)
+	GetCaseForks(ctx context.Context, id, callerID primitive.ObjectID) ([]dtos.CaseResponse, error)
+	GetToolInvocations(ctx context.Context, id, callerID primitive.ObjectID) ([]dtos.ToolInvocationResponse, error)
+	BulkTagCases(ctx context.Context, callerID primitive.ObjectID, request dtos.BulkTagRequest) (*dtos.BulkTagResponse, error)
+	CreateShareLink(ctx context.Context, id, callerID primitive.ObjectID, request dtos.CreateShareLinkRequest) (*dtos.ShareLinkResponse, error)
+	GetShareLinks(ctx context.Context, id, callerID primitive.ObjectID) ([]dtos.ShareLinkResponse, error)
+	RevokeShareLink(ctx context.Context, id, linkID, callerID primitive.ObjectID) error
+	GetSharedCase(ctx context.Context, token, password string) (*dtos.CaseResponse, error)
+	GetCaseInvitations(ctx context.Context, id, callerID primitive.ObjectID) ([]dtos.CaseInvitationResponse, error)
+	RevokeCaseInvitation(ctx context.Context, id, invitationID, callerID primitive.ObjectID) error
+	AcceptCaseInvitation(ctx context.Context, token string, callerID primitive.ObjectID) (*dtos.CaseResponse, error)
+	ResolveInvitations(ctx context.Context, userID primitive.ObjectID, email string) (int, error)
+	GetCaseActivity(ctx context.Context, id, callerID primitive.ObjectID, query dtos.CaseActivityQuery) (*dtos.CaseActivityPageResponse, error)
+	CreateAnnotation(ctx context.Context, id, messageID, callerID primitive.ObjectID, request dtos.CreateAnnotationRequest) (*dtos.AnnotationResponse, error)
+	GetAnnotations(ctx context.Context, id, callerID primitive.ObjectID, query dtos.AnnotationQuery) (*dtos.AnnotationPageResponse, error)
+	UpdateAnnotation(ctx context.Context, id, annotationID, callerID primitive.ObjectID, request dtos.UpdateAnnotationRequest) (*dtos.AnnotationResponse, error)
+	DeleteAnnotation(ctx context.Context, id, annotationID, callerID primitive.ObjectID) error
+	GetUserBookmarks(ctx context.Context, userID, callerID primitive.ObjectID, query dtos.AnnotationQuery) (*dtos.BookmarkPageResponse, error)
+	UploadDocument(ctx context.Context, id, callerID primitive.ObjectID, fileName, contentType string, content io.Reader) (*dtos.DocumentResponse, error)
+	GetCaseDocuments(ctx context.Context, id, callerID primitive.ObjectID) ([]dtos.DocumentResponse, error)
+	DownloadDocument(ctx context.Context, documentID, callerID primitive.ObjectID) (*DocumentContent, error)
+	GetDocumentText(ctx context.Context, documentID, callerID primitive.ObjectID) (*dtos.DocumentTextResponse, error)
+	DeleteDocument(ctx context.Context, id, documentID, callerID primitive.ObjectID) error
+	GetUsage(ctx context.Context, callerID primitive.ObjectID, query dtos.UsageQuery) (*dtos.UsageReportResponse, error)
 }
 
+const (
+	defaultMessagePageSize = 50
+	maxMessagePageSize     = 200
+)
+
 // CaseServiceImpl implements the CaseService interface.
 type CaseServiceImpl struct {
-	caseRepo   *repositories.CaseRepository
-	userRepo   *repositories.UserRepositoryImpl
-	mapper     *mappers.CaseConversionServiceImpl
-	userMapper *mappers.UserConversionServiceImpl
-	logger     logs.Logger
+	caseRepo       *repositories.CaseRepository
+	messageRepo    *repositories.MessageRepository
+	agentRepo      *repositories.AgentRepository
+	versionRepo    *repositories.CaseVersionRepository
+	folderRepo     *repositories.FolderRepository
+	templateRepo   *repositories.CaseTemplateRepository
+	shareLinkRepo  *repositories.ShareLinkRepository
+	inviteRepo     *repositories.CaseInvitationRepository
+	activityRepo   *repositories.CaseActivityRepository
+	annotationRepo *repositories.AnnotationRepository
+	documentRepo   *repositories.DocumentRepository
+	userRepo       *repositories.UserRepositoryImpl
+	mapper         *mappers.CaseConversionServiceImpl
+	linkMapper     *mappers.ShareLinkConversionServiceImpl
+	userMapper     *mappers.UserConversionServiceImpl
+	events         *events.Broadcaster
+	exporter       *export.Renderer
+	notifier       notify.Notifier
+	prices         pricing.Table
+	logger         logs.Logger
 }
 
 // NewCaseService creates a new instance of the case service.
-func NewCaseService(caseRepo *repositories.CaseRepository, mapper *mappers.CaseConversionServiceImpl, userMapper *mappers.UserConversionServiceImpl, userRepo *repositories.UserRepositoryImpl, logger logs.Logger) *CaseServiceImpl {
+func NewCaseService(caseRepo *repositories.CaseRepository, messageRepo *repositories.MessageRepository, agentRepo *repositories.AgentRepository, versionRepo *repositories.CaseVersionRepository, folderRepo *repositories.FolderRepository, templateRepo *repositories.CaseTemplateRepository, shareLinkRepo *repositories.ShareLinkRepository, inviteRepo *repositories.CaseInvitationRepository, activityRepo *repositories.CaseActivityRepository, annotationRepo *repositories.AnnotationRepository, documentRepo *repositories.DocumentRepository, mapper *mappers.CaseConversionServiceImpl, linkMapper *mappers.ShareLinkConversionServiceImpl, userMapper *mappers.UserConversionServiceImpl, userRepo *repositories.UserRepositoryImpl, broadcaster *events.Broadcaster, exporter *export.Renderer, notifier notify.Notifier, prices pricing.Table, logger logs.Logger) *CaseServiceImpl {
 	return &CaseServiceImpl{
-		caseRepo:   caseRepo,
-		userRepo:   userRepo,
-		mapper:     mapper,
-		userMapper: userMapper,
-		logger:     logger,
+		caseRepo:       caseRepo,
+		messageRepo:    messageRepo,
+		agentRepo:      agentRepo,
+		versionRepo:    versionRepo,
+		folderRepo:     folderRepo,
+		templateRepo:   templateRepo,
+		shareLinkRepo:  shareLinkRepo,
+		inviteRepo:     inviteRepo,
+		activityRepo:   activityRepo,
+		annotationRepo: annotationRepo,
+		documentRepo:   documentRepo,
+		userRepo:       userRepo,
+		mapper:         mapper,
+		linkMapper:     linkMapper,
+		userMapper:     userMapper,
+		events:         broadcaster,
+		exporter:       exporter,
+		notifier:       notifier,
+		prices:         prices,
+		logger:         logger,
+	}
+}
+
+// publish notifies subscribers of a change to a case. It must only be called once
+// the change has been written.
+func (s *CaseServiceImpl) publish(caseID primitive.ObjectID, eventType string, data interface{}) {
+	if s.events == nil {
+		return
+	}
+	s.events.Publish(caseID, eventType, data)
+}
+
+// publishCaseUpdated announces a case change without its messages, which have
+// their own events and can be paged in separately.
+func (s *CaseServiceImpl) publishCaseUpdated(caseResponse *dtos.CaseResponse) {
+	summary := *caseResponse
+	summary.Messages = helpers.Nullable[[]dtos.MessageResponse]{}
+	s.publish(caseResponse.ID.Value, events.CaseUpdated, summary)
+}
+
+// AuthorizeCaseAccess checks that a user may read a case: that they created it or
+// collaborate on it in any role.
+func (s *CaseServiceImpl) AuthorizeCaseAccess(ctx context.Context, id, userID primitive.ObjectID) error {
+	return s.AuthorizeCaseRole(ctx, id, userID, models.CaseRoleViewer)
+}
+
+// AuthorizeCaseRole checks that a user holds at least the required role on a case.
+func (s *CaseServiceImpl) AuthorizeCaseRole(ctx context.Context, id, userID primitive.ObjectID, required string) error {
+	_, err := s.authorizeCase(ctx, id, userID, required)
+	return err
+}
+
+// authorizeCase loads a live case and checks that a user holds at least the
+// required role on it.
+func (s *CaseServiceImpl) authorizeCase(ctx context.Context, id, userID primitive.ObjectID, required string) (models.Case, error) {
+	caseModel, err := s.caseRepo.GetCaseByID(ctx, id)
+	if err != nil {
+		if stderrors.Is(err, daos.ErrCaseNotFound) {
+			return models.Case{}, errors.NewNotFoundError("Case not found", "case_not_found")
+		}
+		s.logger.Error("Service Level: Failed to retrieve case for access check", err)
+		return models.Case{}, errors.NewDatabaseError("Failed to retrieve case", "get_case_failed")
+	}
+	if err := checkCaseRole(caseModel, userID, required); err != nil {
+		s.logger.Warn("Service Level: User lacks the required role on case")
+		return models.Case{}, err
 	}
+	return caseModel, nil
 }
 
-// GetAllCases retrieves all cases.
-func (s *CaseServiceImpl) GetAllCases(ctx context.Context) ([]dtos.CaseResponse, error) {
+// checkCaseRole returns a forbidden error unless the user holds at least the
+// required role on the case.
+func checkCaseRole(caseModel models.Case, userID primitive.ObjectID, required string) error {
+	role := caseModel.RoleOf(userID)
+	if role == "" {
+		return errors.NewForbiddenError("You do not have access to this case", "case_access_denied")
+	}
+	if !models.CaseRoleAllows(role, required) {
+		return errors.NewForbiddenError("Your role on this case does not allow this action", "case_role_insufficient")
+	}
+	return nil
+}
+
+// SubscribeCaseEvents subscribes a user with access to a case to its events,
+// returning the buffered events published after lastEventID for replay.
+func (s *CaseServiceImpl) SubscribeCaseEvents(ctx context.Context, id, userID primitive.ObjectID, lastEventID uint64) (*events.Subscription, []events.Event, error) {
+	s.logger.Info("Service Level: Attempting to subscribe to case events")
+	if err := s.AuthorizeCaseAccess(ctx, id, userID); err != nil {
+		return nil, nil, err
+	}
+	subscription, replay := s.events.Subscribe(id, lastEventID)
+	s.logger.Info("Service Level: Successfully subscribed to case events")
+	return subscription, replay, nil
+}
+
+// attachMessages loads the messages of each case from the message collection so
+// responses keep exposing them on the case itself.
+func (s *CaseServiceImpl) attachMessages(ctx context.Context, cases []models.Case) error {
+	if len(cases) == 0 {
+		return nil
+	}
+	ids := make([]primitive.ObjectID, len(cases))
+	for i := range cases {
+		ids[i] = cases[i].ID
+	}
+	grouped, err := s.messageRepo.GetMessagesByCaseIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range cases {
+		cases[i].Messages = grouped[cases[i].ID]
+	}
+	return nil
+}
+
+// GetAllCases retrieves every live case the caller created or collaborates on.
+func (s *CaseServiceImpl) GetAllCases(ctx context.Context, callerID primitive.ObjectID) ([]dtos.CaseResponse, error) {
 	s.logger.Info("Service Level: Attempting to retrieve all cases")
-	caseModel, err := s.caseRepo.GetAllCases(ctx)
+	caseModel, err := s.caseRepo.GetAccessibleCases(ctx, callerID, primitive.NilObjectID)
 	if err != nil {
 		s.logger.Error("Service Level: Failed to retrieve all cases", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve cases", "get_cases_failed")
+	}
+	if err := s.attachMessages(ctx, caseModel); err != nil {
+		s.logger.Error("Service Level: Failed to retrieve case messages", err)
 		return nil, err
 	}
 	caseResponses := s.mapper.CasesToDTO(caseModel)
@@ -56,46 +239,159 @@ func (s *CaseServiceImpl) GetAllCases(ctx context.Context) ([]dtos.CaseResponse,
 	return caseResponses, nil
 }
 
-// GetCaseByID retrieves a case by its ID.
-func (s *CaseServiceImpl) GetCaseByID(ctx context.Context, id primitive.ObjectID) (*dtos.CaseResponse, error) {
+// GetCaseByID retrieves a case the caller may read by its ID. With mainLine set,
+// thread replies are left out of its messages, which is the history to hand to
+// the agent; reply counts still cover them.
+func (s *CaseServiceImpl) GetCaseByID(ctx context.Context, id, callerID primitive.ObjectID, mainLine bool) (*dtos.CaseResponse, error) {
 	s.logger.Info("Service Level: Attempting to retrieve case by ID")
-	caseModel, err := s.caseRepo.GetCaseByID(ctx, id)
+	if err := s.AuthorizeCaseAccess(ctx, id, callerID); err != nil {
+		return nil, err
+	}
+	caseResponse, err := s.loadCaseResponse(ctx, id)
 	if err != nil {
-		s.logger.Error("Service Level: Failed to retrieve case by ID", err)
 		return nil, err
 	}
-	caseResponse := s.mapper.CaseToDTO(&caseModel)
+	if mainLine {
+		caseResponse.Messages.Value = slices.DeleteFunc(caseResponse.Messages.Value, func(message dtos.MessageResponse) bool {
+			return message.ParentMessageID.Present
+		})
+	}
 	s.logger.Info("Service Level: Successfully retrieved case by ID")
 	return caseResponse, nil
 }
 
-// GetCasesByCreatorID retrieves cases by the creator's ID.
-func (s *CaseServiceImpl) GetCasesByCreatorID(ctx context.Context, creatorID primitive.ObjectID) ([]dtos.CaseResponse, error) {
+// loadCaseResponse loads a case with its messages without checking access; it
+// backs the responses of operations that have already been authorized.
+func (s *CaseServiceImpl) loadCaseResponse(ctx context.Context, id primitive.ObjectID) (*dtos.CaseResponse, error) {
+	caseModel, err := s.caseRepo.GetCaseByID(ctx, id)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve case by ID", err)
+		return nil, err
+	}
+	caseModel.Messages, err = s.messageRepo.GetMessagesByCaseID(ctx, id)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve case messages", err)
+		return nil, err
+	}
+	return s.mapper.CaseToDTO(&caseModel), nil
+}
+
+// GetCasesByCreatorID retrieves cases by the creator's ID, optionally narrowed to
+// a folder subtree and to a set of tags.
+func (s *CaseServiceImpl) GetCasesByCreatorID(ctx context.Context, creatorID primitive.ObjectID, query dtos.CaseListQuery) ([]dtos.CaseResponse, error) {
 	s.logger.Info("Service Level: Attempting to retrieve cases by creator ID")
-	caseModel, err := s.caseRepo.GetCasesByCreatorID(ctx, creatorID)
+	filter, err := s.caseListFilter(ctx, creatorID, query)
+	if err != nil {
+		return nil, err
+	}
+	caseModel, err := s.caseRepo.GetCasesByCreatorID(ctx, creatorID, filter)
 	if err != nil {
 		s.logger.Error("Service Level: Failed to retrieve cases by creator ID", err)
 		return nil, err
 	}
+	if err := s.attachMessages(ctx, caseModel); err != nil {
+		s.logger.Error("Service Level: Failed to retrieve case messages", err)
+		return nil, err
+	}
 	caseResponses := s.mapper.CasesToDTO(caseModel)
 	s.logger.Info("Service Level: Successfully retrieved cases by creator ID")
 	return caseResponses, nil
 }
 
-// CreateCase creates a new case.
+// CreateCase creates a new case, seeded from a template when one is requested.
 func (s *CaseServiceImpl) CreateCase(ctx context.Context, caseRequest dtos.CreateCaseRequest) (*dtos.CaseResponse, error) {
 	s.logger.Info("Service Level: Attempting to create new case")
-	caseModel, err := s.mapper.DTOToCase(caseRequest)
-	caseModel.CreationDate = time.Now()
+	var template *models.CaseTemplate
+	if caseRequest.TemplateID.Present {
+		resolved, err := s.resolveTemplate(ctx, caseRequest.TemplateID.Value, caseRequest.CreatorID.Value)
+		if err != nil {
+			return nil, err
+		}
+		template = &resolved
+	}
+	if caseRequest.Tags.Present {
+		tags, err := normalizeTags(caseRequest.Tags.Value)
+		if err != nil {
+			return nil, err
+		}
+		caseRequest.Tags.Value = tags
+	}
+	caseModel, err := s.mapper.DTOToCase(caseRequest, template)
 	if err != nil {
 		s.logger.Error("Service Level: Failed to convert DTO to case model", err)
 		return nil, err
 	}
+	caseModel.CreationDate = time.Now()
+	if err := s.prepareCaseMessages(ctx, caseModel); err != nil {
+		return nil, err
+	}
+	createdCase, err := s.insertCase(ctx, caseModel)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Service Level: Successfully created new case")
+	return createdCase, nil
+}
+
+// prepareCaseMessages checks the messages a new case starts with as appended
+// messages are checked, and stamps them as written by the creator. Replies must
+// follow their parent in the list; documents must belong to the case, so a case
+// that does not exist yet cannot refer to any.
+func (s *CaseServiceImpl) prepareCaseMessages(ctx context.Context, caseModel *models.Case) error {
+	if err := resolveNewThreads(caseModel.Messages); err != nil {
+		return err
+	}
+	if err := s.resolveDocuments(ctx, caseModel.ID, caseModel.Messages); err != nil {
+		return err
+	}
+	for i := range caseModel.Messages {
+		caseModel.Messages[i].AuthorUserID = caseModel.CreatorID
+		caseModel.Messages[i].CreatedAt = caseModel.CreationDate
+	}
+	return nil
+}
+
+// prepareAppendedMessages checks the thread parents and documents of messages
+// about to be added to a stored case, and gives them server IDs, their author and
+// their creation time.
+func (s *CaseServiceImpl) prepareAppendedMessages(ctx context.Context, caseID, authorID primitive.ObjectID, messages []models.Message, now time.Time) error {
+	if err := s.resolveThreads(ctx, caseID, messages); err != nil {
+		return err
+	}
+	if err := s.resolveDocuments(ctx, caseID, messages); err != nil {
+		return err
+	}
+	for i := range messages {
+		messages[i].ID = primitive.NewObjectID()
+		messages[i].AuthorUserID = authorID
+		messages[i].CreatedAt = now
+	}
+	return nil
+}
+
+// resolveTemplate loads a template the case creator is allowed to use.
+func (s *CaseServiceImpl) resolveTemplate(ctx context.Context, id, creatorID primitive.ObjectID) (models.CaseTemplate, error) {
+	template, _, err := loadTemplate(ctx, s.templateRepo, s.logger, id, creatorID)
+	return template, err
+}
+
+// insertCase stores a new case with its messages, links it to its creator and
+// records its first version.
+func (s *CaseServiceImpl) insertCase(ctx context.Context, caseModel *models.Case) (*dtos.CaseResponse, error) {
+	messages := caseModel.Messages
+	caseModel.Messages = nil
 	insertResult, err := s.caseRepo.CreateCase(ctx, *caseModel)
 	if err != nil {
 		s.logger.Error("Service Level: Failed to create case", err)
 		return nil, err
 	}
+	if len(messages) > 0 {
+		s.priceMessages(ctx, caseModel.ID, caseModel.AgentID, messages)
+		if _, err := s.messageRepo.AppendMessages(ctx, caseModel.ID, messages, caseModel.LastEdit); err != nil {
+			s.logger.Error("Service Level: Failed to store case messages", err)
+			return nil, err
+		}
+	}
 	user, err := s.userRepo.FindUserByID(ctx, caseModel.CreatorID)
 	if err != nil {
 		s.logger.Error("Service Level: Failed to find user by ID", err)
@@ -108,89 +404,538 @@ func (s *CaseServiceImpl) CreateCase(ctx context.Context, caseRequest dtos.Creat
 		s.logger.Error("Service Level: Failed to update user", err)
 		return nil, err
 	}
-	createdCase, err := s.GetCaseByID(ctx, insertResult.InsertedID.(primitive.ObjectID))
+	s.recordMutation(ctx, caseModel.ID, caseModel.CreatorID, models.CaseActionCreate)
+	createdCase, err := s.loadCaseResponse(ctx, insertResult.InsertedID.(primitive.ObjectID))
 	if err != nil {
 		s.logger.Error("Service Level: Failed to retrieve created case", err)
 		return nil, err
 	}
-	s.logger.Info("Service Level: Successfully created new case")
 	return createdCase, nil
 }
 
-// UpdateCase updates an existing case.
-func (s *CaseServiceImpl) UpdateCase(ctx context.Context, id primitive.ObjectID, updates dtos.UpdateCaseRequest) (*dtos.CaseResponse, error) {
+// UpdateCase updates an existing case. Editors may change it; replacing its
+// collaborators is reserved to owners. Given messages are synced rather than
+// replaced: messages left out are soft-deleted and changed ones edited, so their
+// history, replies and annotations are kept. Listed messages must belong to the
+// case and only their content and document path can change; messages without an
+// ID are added.
+func (s *CaseServiceImpl) UpdateCase(ctx context.Context, id, actorID primitive.ObjectID, updates dtos.UpdateCaseRequest) (*dtos.CaseResponse, error) {
 	s.logger.Info("Service Level: Attempting to update case")
+	required := models.CaseRoleEditor
+	if updates.Collaborators.Present {
+		required = models.CaseRoleOwner
+	}
+	if err := s.AuthorizeCaseRole(ctx, id, actorID, required); err != nil {
+		return nil, err
+	}
+	if err := s.validateCaseOrganization(ctx, id, &updates); err != nil {
+		return nil, err
+	}
+	var messages []models.Message
+	if updates.Messages.Present {
+		var err error
+		if messages, err = s.syncedMessages(ctx, id, actorID, updates.Messages.Value); err != nil {
+			return nil, err
+		}
+	}
 	updateCaseMap, err := s.mapper.UpdateCaseFieldsToMap(updates)
 	if err != nil {
 		s.logger.Error("Service Level: Failed to map case", err)
-		return nil, err
+		return nil, errors.NewIncorrectInputError(err.Error(), "invalid_case_update")
 	}
 	_, err = s.caseRepo.UpdateCase(ctx, id, updateCaseMap)
 	if err != nil {
 		s.logger.Error("Service Level: Failed to update case", err)
 		return nil, err
 	}
-	updatedCase, err := s.GetCaseByID(ctx, id)
+	if updates.Messages.Present {
+		if err := s.messageRepo.SyncMessages(ctx, id, messages, time.Now(), actorID); err != nil {
+			s.logger.Error("Service Level: Failed to sync case messages", err)
+			return nil, errors.NewDatabaseError("Failed to update case messages", "update_case_messages_failed")
+		}
+	}
+	fields := updatedFields(updateCaseMap)
+	if updates.Messages.Present {
+		fields = append(fields, "messages")
+	}
+	s.recordMutation(ctx, id, actorID, models.CaseActionUpdate, fields...)
+	updatedCase, err := s.loadCaseResponse(ctx, id)
 	if err != nil {
 		s.logger.Error("Service Level: Failed to retrieve updated case", err)
 		return nil, err
 	}
+	s.publishCaseUpdated(updatedCase)
 	s.logger.Info("Service Level: Successfully updated case")
 	return updatedCase, nil
 }
 
-// DeleteCase deletes a case by its ID.
-func (s *CaseServiceImpl) DeleteCase(ctx context.Context, id primitive.ObjectID) (*dtos.CaseResponse, error) {
+// syncedMessages converts the messages given to replace those of a case. Messages
+// with an ID must be stored in the case, at most once, and keep everything but
+// their content and document path; fields left out are kept as stored. Messages
+// without an ID are checked and stamped like appended ones.
+func (s *CaseServiceImpl) syncedMessages(ctx context.Context, caseID, actorID primitive.ObjectID, messagesDTO []dtos.MessageResponse) ([]models.Message, error) {
+	messages, err := s.mapper.DTOToMessages(messagesDTO)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to convert messages", err)
+		return nil, errors.NewIncorrectInputError(err.Error(), "invalid_message")
+	}
+	stored, err := s.messageRepo.GetAllMessagesByCaseID(ctx, caseID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve case messages", err)
+		return nil, errors.NewDatabaseError("Failed to update case messages", "update_case_messages_failed")
+	}
+	existing := make(map[primitive.ObjectID]models.Message, len(stored))
+	for _, message := range stored {
+		existing[message.ID] = message
+	}
+	listed := make(map[primitive.ObjectID]bool, len(messagesDTO))
+	var added []int
+	for i, dto := range messagesDTO {
+		if !dto.ID.Present {
+			added = append(added, i)
+			continue
+		}
+		current, ok := existing[dto.ID.Value]
+		if !ok {
+			return nil, errors.NewIncorrectInputError("Message "+dto.ID.Value.Hex()+" does not belong to the case", "message_not_in_case")
+		}
+		if listed[current.ID] {
+			return nil, errors.NewIncorrectInputError("Message "+dto.ID.Value.Hex()+" is listed more than once", "duplicate_message")
+		}
+		listed[current.ID] = true
+		if !keepsMessageFields(current, messages[i], dto) {
+			return nil, errors.NewIncorrectInputError("Only the content and document path of message "+dto.ID.Value.Hex()+" can change", "message_field_immutable")
+		}
+	}
+	if len(added) > 0 {
+		newMessages := make([]models.Message, len(added))
+		for j, i := range added {
+			newMessages[j] = messages[i]
+		}
+		if err := s.prepareAppendedMessages(ctx, caseID, actorID, newMessages, time.Now()); err != nil {
+			return nil, err
+		}
+		for j, i := range added {
+			messages[i] = newMessages[j]
+		}
+	}
+	s.priceMessages(ctx, caseID, primitive.NilObjectID, messages)
+	return messages, nil
+}
+
+// keepsMessageFields reports whether a listed message leaves the fields that
+// cannot be edited as stored. Optional fields the client left out count as kept.
+func keepsMessageFields(current, listed models.Message, dto dtos.MessageResponse) bool {
+	if current.Sender != listed.Sender || current.Recipient != listed.Recipient {
+		return false
+	}
+	if dto.ToolCalls.Present && !reflect.DeepEqual(current.ToolCalls, listed.ToolCalls) {
+		return false
+	}
+	if dto.ToolResult.Present && !reflect.DeepEqual(current.ToolResult, listed.ToolResult) {
+		return false
+	}
+	if dto.Usage.Present && !sameUsage(current.Usage, listed.Usage) {
+		return false
+	}
+	if dto.DocumentID.Present && current.DocumentID != listed.DocumentID {
+		return false
+	}
+	if dto.ParentMessageID.Present && current.ParentMessageID != listed.ParentMessageID {
+		return false
+	}
+	return true
+}
+
+// sameUsage compares reported usage, leaving out the cost worked out on storage.
+func sameUsage(a, b *models.TokenUsage) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Model == b.Model && a.PromptTokens == b.PromptTokens && a.CompletionTokens == b.CompletionTokens
+}
+
+// AppendMessages appends messages to a case without rewriting the ones already stored.
+// IDs, authors and creation timestamps are always assigned by the server. Users
+// need the editor role, or the commenter role when every message is a thread
+// reply.
+func (s *CaseServiceImpl) AppendMessages(ctx context.Context, id, authorID primitive.ObjectID, messagesDTO []dtos.MessageResponse) ([]dtos.MessageResponse, error) {
+	s.logger.Info("Service Level: Attempting to append messages to case")
+	if len(messagesDTO) == 0 {
+		s.logger.Warn("Service Level: No messages provided to append")
+		return nil, errors.NewIncorrectInputError("At least one message is required", "messages_required")
+	}
+	messages, err := s.mapper.DTOToMessages(messagesDTO)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to convert messages", err)
+		return nil, errors.NewIncorrectInputError(err.Error(), "invalid_message")
+	}
+	required := models.CaseRoleCommenter
+	if slices.ContainsFunc(messages, func(message models.Message) bool { return !message.IsReply() }) {
+		required = models.CaseRoleEditor
+	}
+	if err := s.AuthorizeCaseRole(ctx, id, authorID, required); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := s.prepareAppendedMessages(ctx, id, authorID, messages, now); err != nil {
+		return nil, err
+	}
+	s.priceMessages(ctx, id, primitive.NilObjectID, messages)
+	stored, err := s.messageRepo.AppendMessages(ctx, id, messages, now)
+	if err != nil {
+		if stderrors.Is(err, daos.ErrCaseNotFound) {
+			s.logger.Warn("Service Level: Case not found while appending messages")
+			return nil, errors.NewNotFoundError("Case not found", "case_not_found")
+		}
+		s.logger.Error("Service Level: Failed to append messages to case", err)
+		return nil, errors.NewDatabaseError("Failed to append messages", "append_messages_failed")
+	}
+	s.recordMutation(ctx, id, authorID, models.CaseActionAppendMessages, "messages")
+	appended := s.mapper.MessagesToDTO(stored)
+	for _, message := range appended {
+		s.publish(id, events.MessageAppended, message)
+	}
+	s.logger.Info("Service Level: Successfully appended messages to case")
+	return appended, nil
+}
+
+// GetCaseMessages returns one page of a case's messages. Cursors are message
+// positions (sequence numbers); only the requested page is read from the database.
+// Top-level messages carry the number of replies in their thread.
+func (s *CaseServiceImpl) GetCaseMessages(ctx context.Context, id, callerID primitive.ObjectID, query dtos.MessagePageQuery) (*dtos.MessagePageResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve case messages page")
+	if query.Before.Present && query.After.Present {
+		return nil, errors.NewIncorrectInputError("Only one of before or after may be given", "invalid_message_cursor")
+	}
+	if (query.Before.Present && query.Before.Value < 0) || (query.After.Present && query.After.Value < 0) {
+		return nil, errors.NewIncorrectInputError("Message cursors must not be negative", "invalid_message_cursor")
+	}
+	order := query.Order
+	if order == "" {
+		order = dtos.MessageOrderNewestFirst
+	}
+	if order != dtos.MessageOrderNewestFirst && order != dtos.MessageOrderOldestFirst {
+		return nil, errors.NewIncorrectInputError("Order must be asc or desc", "invalid_message_order")
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultMessagePageSize
+	}
+	if limit > maxMessagePageSize {
+		limit = maxMessagePageSize
+	}
+
+	if err := s.AuthorizeCaseAccess(ctx, id, callerID); err != nil {
+		return nil, err
+	}
+
+	var stored []models.Message
+	var err error
+	switch {
+	case query.Before.Present:
+		stored, err = s.messageRepo.GetMessagesBefore(ctx, id, query.Before.Value, limit, query.MainLine)
+	case query.After.Present:
+		stored, err = s.messageRepo.GetMessagesAfter(ctx, id, query.After.Value, limit, query.MainLine)
+	case order == dtos.MessageOrderNewestFirst:
+		stored, err = s.messageRepo.GetLastMessages(ctx, id, limit, query.MainLine)
+	default:
+		stored, err = s.messageRepo.GetFirstMessages(ctx, id, limit, query.MainLine)
+	}
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve case messages page", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve messages", "get_messages_failed")
+	}
+	total, err := s.messageRepo.CountMessages(ctx, id, query.MainLine)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to count case messages", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve messages", "get_messages_failed")
+	}
+	parentIDs := make([]primitive.ObjectID, 0, len(stored))
+	for _, message := range stored {
+		if !message.IsReply() {
+			parentIDs = append(parentIDs, message.ID)
+		}
+	}
+	replyCounts, err := s.messageRepo.CountReplies(ctx, id, parentIDs)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to count message replies", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve messages", "get_messages_failed")
+	}
+
+	page := &dtos.MessagePageResponse{
+		Total: helpers.Nullable[int]{Value: int(total), Present: true},
+		Order: helpers.NewNullable(order),
+	}
+	if len(stored) > 0 {
+		first, last := stored[0].Sequence, stored[len(stored)-1].Sequence
+		hasOlder, err := s.messageRepo.HasMessagesBefore(ctx, id, first, query.MainLine)
+		if err != nil {
+			s.logger.Error("Service Level: Failed to check for older messages", err)
+			return nil, errors.NewDatabaseError("Failed to retrieve messages", "get_messages_failed")
+		}
+		hasNewer, err := s.messageRepo.HasMessagesAfter(ctx, id, last, query.MainLine)
+		if err != nil {
+			s.logger.Error("Service Level: Failed to check for newer messages", err)
+			return nil, errors.NewDatabaseError("Failed to retrieve messages", "get_messages_failed")
+		}
+		if hasOlder {
+			page.Before = helpers.Nullable[int]{Value: first, Present: true}
+		}
+		if hasNewer {
+			page.After = helpers.Nullable[int]{Value: last, Present: true}
+		}
+	}
+
+	messages := s.mapper.ApplyReplyCounts(s.mapper.MessagesToDTO(stored), replyCounts)
+	if order == dtos.MessageOrderNewestFirst {
+		slices.Reverse(messages)
+	}
+	page.Messages = helpers.NewNullable(messages)
+	s.logger.Info("Service Level: Successfully retrieved case messages page")
+	return page, nil
+}
+
+// EditMessage replaces the content of a single message. The previous content is
+// kept in the message history.
+func (s *CaseServiceImpl) EditMessage(ctx context.Context, id, messageID, editorID primitive.ObjectID, request dtos.EditMessageRequest) (*dtos.MessageResponse, error) {
+	s.logger.Info("Service Level: Attempting to edit message")
+	if !request.Content.Present && !request.DocumentPath.Present {
+		return nil, errors.NewIncorrectInputError("Content or document path is required", "message_edit_empty")
+	}
+	if err := s.AuthorizeCaseRole(ctx, id, editorID, models.CaseRoleEditor); err != nil {
+		return nil, err
+	}
+	current, err := s.messageRepo.GetMessageByID(ctx, id, messageID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve message for edit", err)
+		return nil, messageError(err)
+	}
+	now := time.Now()
+	edited, err := s.messageRepo.EditMessage(ctx, id, messageID,
+		request.Content.OrElse(current.Content),
+		request.DocumentPath.OrElse(current.DocumentPath),
+		now, editorID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to edit message", err)
+		return nil, messageError(err)
+	}
+	if _, err := s.caseRepo.UpdateCase(ctx, id, map[string]interface{}{"last_edit": now}); err != nil {
+		s.logger.Error("Service Level: Failed to update case last edit", err)
+		return nil, errors.NewDatabaseError("Failed to update case", "update_case_failed")
+	}
+	s.recordMutation(ctx, id, editorID, models.CaseActionEditMessage, "messages")
+	response := s.mapper.MessageToDTO(edited)
+	s.publish(id, events.MessageUpdated, response)
+	s.logger.Info("Service Level: Successfully edited message")
+	return &response, nil
+}
+
+// DeleteMessage soft-deletes a single message. It disappears from the conversation
+// but remains available, with its history, through GetMessageHistory.
+func (s *CaseServiceImpl) DeleteMessage(ctx context.Context, id, messageID, deleterID primitive.ObjectID) (*dtos.MessageResponse, error) {
+	s.logger.Info("Service Level: Attempting to delete message")
+	if err := s.AuthorizeCaseRole(ctx, id, deleterID, models.CaseRoleEditor); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	deleted, err := s.messageRepo.DeleteMessage(ctx, id, messageID, now, deleterID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to delete message", err)
+		return nil, messageError(err)
+	}
+	if _, err := s.caseRepo.UpdateCase(ctx, id, map[string]interface{}{"last_edit": now}); err != nil {
+		s.logger.Error("Service Level: Failed to update case last edit", err)
+		return nil, errors.NewDatabaseError("Failed to update case", "update_case_failed")
+	}
+	s.recordMutation(ctx, id, deleterID, models.CaseActionDeleteMessage, "messages")
+	response := s.mapper.MessageToDTO(deleted)
+	s.publish(id, events.MessageDeleted, response)
+	s.logger.Info("Service Level: Successfully deleted message")
+	return &response, nil
+}
+
+// GetMessageHistory retrieves a message, deleted or not, with its full revision history.
+func (s *CaseServiceImpl) GetMessageHistory(ctx context.Context, id, messageID, callerID primitive.ObjectID) (*dtos.MessageResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve message history")
+	if err := s.AuthorizeCaseAccess(ctx, id, callerID); err != nil {
+		return nil, err
+	}
+	message, err := s.messageRepo.GetMessageByID(ctx, id, messageID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve message history", err)
+		return nil, messageError(err)
+	}
+	response := s.mapper.MessageHistoryToDTO(message)
+	s.logger.Info("Service Level: Successfully retrieved message history")
+	return &response, nil
+}
+
+// messageError maps repository errors on a single message to service errors.
+func messageError(err error) error {
+	if stderrors.Is(err, daos.ErrMessageNotFound) {
+		return errors.NewNotFoundError("Message not found", "message_not_found")
+	}
+	return errors.NewDatabaseError("Failed to access message", "message_access_failed")
+}
+
+// DeleteCase moves a case to the trash. It stays restorable, messages included,
+// until the trash purge removes it for good. Only owners may delete a case.
+func (s *CaseServiceImpl) DeleteCase(ctx context.Context, id, actorID primitive.ObjectID) (*dtos.CaseResponse, error) {
 	s.logger.Info("Service Level: Attempting to delete case")
-	deletedCase, err := s.GetCaseByID(ctx, id)
+	if err := s.AuthorizeCaseRole(ctx, id, actorID, models.CaseRoleOwner); err != nil {
+		return nil, err
+	}
+	deletedCase, err := s.loadCaseResponse(ctx, id)
 	if err != nil {
 		s.logger.Error("Service Level: Failed to retrieve case for deletion", err)
 		return nil, err
 	}
-	err = s.caseRepo.DeleteCase(ctx, id)
-	if err != nil {
-		s.logger.Error("Service Level: Failed to delete case", err)
+	// The final state is recorded while the case can still be loaded.
+	s.recordMutation(ctx, id, actorID, models.CaseActionDelete, "deleted_at")
+	now := time.Now()
+	if err := s.caseRepo.TrashCase(ctx, id, now, actorID); err != nil {
+		s.logger.Error("Service Level: Failed to move case to trash", err)
 		return nil, err
 	}
+	deletedCase.DeletedAt = helpers.NewNullable(now)
+	deletedCase.DeletedBy = helpers.NewNullable(actorID)
+	s.publish(id, events.CaseDeleted, map[string]interface{}{"id": id})
 	s.logger.Info("Service Level: Successfully deleted case")
 	return deletedCase, nil
 }
 
-// AddCollaboratorToCase adds a collaborator to a case.
-func (s *CaseServiceImpl) AddCollaboratorToCase(ctx context.Context, id primitive.ObjectID, email string, canEdit bool) (*dtos.UserResponse, error) {
+// AddCollaboratorToCase adds a collaborator to a case with the requested role.
+// Requests that predate roles only carry the edit flag, which grants editor over
+// viewer. When no user has the email yet, a pending invitation is issued instead
+// and returned in place of the user. Only owners may manage collaborators.
+func (s *CaseServiceImpl) AddCollaboratorToCase(ctx context.Context, id, actorID primitive.ObjectID, request dtos.AddCollaboratorToCase) (*dtos.UserResponse, *dtos.CaseInvitationResponse, error) {
 	s.logger.Info("Service Level: Attempting to add collaborator to case")
+	email := strings.TrimSpace(request.Email.Value)
+	if email == "" {
+		return nil, nil, errors.NewIncorrectInputError("Collaborator email is required", "collaborator_email_required")
+	}
+	role := request.Role.Value
+	if !request.Role.Present {
+		role = models.CaseRoleViewer
+		if request.Edit.Value {
+			role = models.CaseRoleEditor
+		}
+	}
+	if !models.ValidCaseRole(role) {
+		return nil, nil, errors.NewIncorrectInputError("Role must be owner, editor, commenter or viewer", "invalid_case_role")
+	}
+	caseModel, err := s.authorizeCase(ctx, id, actorID, models.CaseRoleOwner)
+	if err != nil {
+		return nil, nil, err
+	}
 	collaborator, err := s.userRepo.FindUserByEmail(ctx, email)
 	if err != nil {
+		if stderrors.Is(err, daos.ErrUserNotFound) {
+			invitation, err := s.inviteCollaborator(ctx, caseModel, actorID, email, role)
+			return nil, invitation, err
+		}
 		s.logger.Error("Service Level: Failed to find collaborator by email", err)
-		return nil, err
+		return nil, nil, errors.NewDatabaseError("Failed to find user", "get_user_failed")
 	}
-
-	updates := map[string]interface{}{
-		"collaborator_id": collaborator.ID,
-		"can_edit":        canEdit,
+	if collaborator.ID == caseModel.CreatorID {
+		return nil, nil, errors.NewConflictError("The case creator is already its owner", "collaborator_exists")
 	}
-	_, err = s.caseRepo.AddCollaboratorToCase(ctx, id, updates)
+
+	_, err = s.caseRepo.AddCollaboratorToCase(ctx, id, models.Collaborators{
+		ID:   collaborator.ID,
+		Role: role,
+		Edit: models.CaseRoleAllows(role, models.CaseRoleEditor),
+	})
 	if err != nil {
+		if stderrors.Is(err, daos.ErrCollaboratorExists) {
+			return nil, nil, errors.NewConflictError("User already collaborates on this case", "collaborator_exists")
+		}
 		s.logger.Error("Service Level: Failed to add collaborator to case", err)
-		return nil, err
+		return nil, nil, errors.NewDatabaseError("Failed to add collaborator", "add_collaborator_failed")
 	}
+	s.recordMutation(ctx, id, actorID, models.CaseActionAddCollaborator, "collaborators")
+	s.publishCollaboratorAdded(id, collaborator.ID, role)
 	s.logger.Info("Service Level: Successfully added collaborator to case")
-	return s.userMapper.UserToDTO(collaborator), nil
+	return s.userMapper.UserToDTO(collaborator), nil, nil
 }
 
-// RemoveCollaboratorFromCase removes a collaborator from a case.
-func (s *CaseServiceImpl) RemoveCollaboratorFromCase(ctx context.Context, id, collaboratorID primitive.ObjectID) (*dtos.CaseResponse, error) {
+// publishCollaboratorAdded announces a new collaborator on a case.
+func (s *CaseServiceImpl) publishCollaboratorAdded(caseID, collaboratorID primitive.ObjectID, role string) {
+	s.publish(caseID, events.CollaboratorChanged, map[string]interface{}{
+		"action":          "added",
+		"collaborator_id": collaboratorID,
+		"role":            role,
+		"edit":            models.CaseRoleAllows(role, models.CaseRoleEditor),
+	})
+}
+
+// RemoveCollaboratorFromCase removes a collaborator from a case. Owners may remove
+// anyone; any collaborator may remove themselves.
+func (s *CaseServiceImpl) RemoveCollaboratorFromCase(ctx context.Context, id, actorID, collaboratorID primitive.ObjectID) (*dtos.CaseResponse, error) {
 	s.logger.Info("Service Level: Attempting to remove collaborator from case")
+	required := models.CaseRoleOwner
+	if actorID == collaboratorID {
+		required = models.CaseRoleViewer
+	}
+	if err := s.AuthorizeCaseRole(ctx, id, actorID, required); err != nil {
+		return nil, err
+	}
 	_, err := s.caseRepo.RemoveCollaboratorFromCase(ctx, id, collaboratorID)
 	if err != nil {
+		if stderrors.Is(err, daos.ErrCollaboratorNotFound) {
+			return nil, errors.NewNotFoundError("Collaborator not found", "collaborator_not_found")
+		}
 		s.logger.Error("Service Level: Failed to remove collaborator from case", err)
-		return nil, err
+		return nil, errors.NewDatabaseError("Failed to remove collaborator", "remove_collaborator_failed")
 	}
-	updatedCase, err := s.GetCaseByID(ctx, id)
+	s.recordMutation(ctx, id, actorID, models.CaseActionRemoveCollaborator, "collaborators")
+	updatedCase, err := s.loadCaseResponse(ctx, id)
 	if err != nil {
 		s.logger.Error("Service Level: Failed to retrieve updated case", err)
 		return nil, err
 	}
+	s.publish(id, events.CollaboratorChanged, map[string]interface{}{
+		"action":          "removed",
+		"collaborator_id": collaboratorID,
+	})
 	s.logger.Info("Service Level: Successfully removed collaborator from case")
 	return updatedCase, nil
 }
+
+// UpdateCollaboratorRole changes the role of a collaborator on a case. Only owners
+// may change roles; the creator always stays an owner.
+func (s *CaseServiceImpl) UpdateCollaboratorRole(ctx context.Context, id, actorID, collaboratorID primitive.ObjectID, role string) (*dtos.CaseResponse, error) {
+	s.logger.Info("Service Level: Attempting to update collaborator role")
+	if !models.ValidCaseRole(role) {
+		return nil, errors.NewIncorrectInputError("Role must be owner, editor, commenter or viewer", "invalid_case_role")
+	}
+	caseModel, err := s.authorizeCase(ctx, id, actorID, models.CaseRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if collaboratorID == caseModel.CreatorID {
+		return nil, errors.NewIncorrectInputError("The role of the case creator cannot be changed", "creator_role_fixed")
+	}
+	if err := s.caseRepo.UpdateCollaboratorRole(ctx, id, collaboratorID, role); err != nil {
+		if stderrors.Is(err, daos.ErrCollaboratorNotFound) {
+			return nil, errors.NewNotFoundError("Collaborator not found", "collaborator_not_found")
+		}
+		s.logger.Error("Service Level: Failed to update collaborator role", err)
+		return nil, errors.NewDatabaseError("Failed to update collaborator role", "update_collaborator_role_failed")
+	}
+	s.recordMutation(ctx, id, actorID, models.CaseActionUpdateCollaboratorRole, "collaborators")
+	updatedCase, err := s.loadCaseResponse(ctx, id)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve updated case", err)
+		return nil, err
+	}
+	s.publish(id, events.CollaboratorChanged, map[string]interface{}{
+		"action":          "role_changed",
+		"collaborator_id": collaboratorID,
+		"role":            role,
+		"edit":            models.CaseRoleAllows(role, models.CaseRoleEditor),
+	})
+	s.logger.Info("Service Level: Successfully updated collaborator role")
+	return updatedCase, nil
+}
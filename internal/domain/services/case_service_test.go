@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestKeepsMessageFields(t *testing.T) {
+	parentID, documentID := primitive.NewObjectID(), primitive.NewObjectID()
+	stored := models.Message{
+		Sender:          "user",
+		Recipient:       "agent",
+		Content:         "draft",
+		ToolCalls:       []models.ToolCall{{CallID: "a", Name: "search", Arguments: "{}"}},
+		Usage:           &models.TokenUsage{Model: "gpt-4o", PromptTokens: 10, CompletionTokens: 5, Cost: 0.0001},
+		DocumentID:      documentID,
+		ParentMessageID: parentID,
+	}
+
+	tests := []struct {
+		name   string
+		listed func(models.Message) models.Message
+		dto    dtos.MessageResponse
+		want   bool
+	}{
+		{
+			name:   "content and document path may change",
+			listed: func(m models.Message) models.Message { m.Content, m.DocumentPath = "final", "brief.pdf"; return m },
+			want:   true,
+		},
+		{
+			name:   "sender may not change",
+			listed: func(m models.Message) models.Message { m.Sender = "agent"; return m },
+			want:   false,
+		},
+		{
+			name:   "recipient may not change",
+			listed: func(m models.Message) models.Message { m.Recipient = "user"; return m },
+			want:   false,
+		},
+		{
+			name:   "left out tool calls are kept",
+			listed: func(m models.Message) models.Message { m.ToolCalls = nil; return m },
+			want:   true,
+		},
+		{
+			name: "tool calls may not change",
+			listed: func(m models.Message) models.Message {
+				m.ToolCalls = []models.ToolCall{{CallID: "b", Name: "fetch", Arguments: "{}"}}
+				return m
+			},
+			dto:  dtos.MessageResponse{ToolCalls: helpers.Nullable[[]dtos.ToolCallResponse]{Present: true}},
+			want: false,
+		},
+		{
+			name: "tool result may not be added",
+			listed: func(m models.Message) models.Message {
+				m.ToolResult = &models.ToolResult{Status: models.ToolStatusSuccess}
+				return m
+			},
+			dto:  dtos.MessageResponse{ToolResult: helpers.Nullable[dtos.ToolResultResponse]{Present: true}},
+			want: false,
+		},
+		{
+			name: "usage without its stored cost is unchanged",
+			listed: func(m models.Message) models.Message {
+				m.Usage = &models.TokenUsage{Model: "gpt-4o", PromptTokens: 10, CompletionTokens: 5}
+				return m
+			},
+			dto:  dtos.MessageResponse{Usage: helpers.Nullable[dtos.UsageResponse]{Present: true}},
+			want: true,
+		},
+		{
+			name: "usage may not change",
+			listed: func(m models.Message) models.Message {
+				m.Usage = &models.TokenUsage{Model: "gpt-4o", PromptTokens: 1, CompletionTokens: 5}
+				return m
+			},
+			dto:  dtos.MessageResponse{Usage: helpers.Nullable[dtos.UsageResponse]{Present: true}},
+			want: false,
+		},
+		{
+			name:   "document may not change",
+			listed: func(m models.Message) models.Message { m.DocumentID = primitive.NewObjectID(); return m },
+			dto:    dtos.MessageResponse{DocumentID: helpers.NewNullable(primitive.NewObjectID())},
+			want:   false,
+		},
+		{
+			name:   "thread parent may not change",
+			listed: func(m models.Message) models.Message { m.ParentMessageID = primitive.NilObjectID; return m },
+			dto:    dtos.MessageResponse{ParentMessageID: helpers.Nullable[primitive.ObjectID]{Present: true}},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keepsMessageFields(stored, tt.listed(stored), tt.dto); got != tt.want {
+				t.Errorf("keepsMessageFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	stderrors "errors"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// secretTokenBytes is the amount of randomness in share link and invitation tokens.
+const secretTokenBytes = 32
+
+// CreateShareLink issues a new public link to a case. Only owners may share a
+// case. The token is returned once and only its hash is stored.
+func (s *CaseServiceImpl) CreateShareLink(ctx context.Context, id, callerID primitive.ObjectID, request dtos.CreateShareLinkRequest) (*dtos.ShareLinkResponse, error) {
+	s.logger.Info("Service Level: Attempting to create share link")
+	scope := request.Scope.OrElse(models.ShareScopeReadOnly)
+	if scope != models.ShareScopeReadOnly {
+		return nil, errors.NewIncorrectInputError("Scope must be read_only", "invalid_share_scope")
+	}
+	now := time.Now()
+	if request.ExpiresAt.Present && !request.ExpiresAt.Value.After(now) {
+		return nil, errors.NewIncorrectInputError("Expiry must be in the future", "invalid_share_expiry")
+	}
+	if err := s.AuthorizeCaseRole(ctx, id, callerID, models.CaseRoleOwner); err != nil {
+		return nil, err
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		s.logger.Error("Service Level: Failed to generate share token", err)
+		return nil, errors.NewSlugError("Failed to create share link", "create_share_link_failed", errors.ErrorTypeUnknown)
+	}
+	link := models.ShareLink{
+		CaseID:    id,
+		TokenHash: hashSecretToken(token),
+		Scope:     scope,
+		CreatedBy: callerID,
+		CreatedAt: now,
+		ExpiresAt: request.ExpiresAt.Value,
+	}
+	if password := request.Password.Value; password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			if stderrors.Is(err, bcrypt.ErrPasswordTooLong) {
+				return nil, errors.NewIncorrectInputError("Password must be at most 72 bytes", "share_password_too_long")
+			}
+			s.logger.Error("Service Level: Failed to hash share link password", err)
+			return nil, errors.NewSlugError("Failed to create share link", "create_share_link_failed", errors.ErrorTypeUnknown)
+		}
+		link.PasswordHash = string(hash)
+	}
+	if err := s.shareLinkRepo.CreateShareLink(ctx, &link); err != nil {
+		s.logger.Error("Service Level: Failed to create share link", err)
+		return nil, errors.NewDatabaseError("Failed to create share link", "create_share_link_failed")
+	}
+	s.recordActivity(ctx, id, callerID, models.CaseActionCreateShareLink, "share")
+
+	response := s.linkMapper.ShareLinkToDTO(link, now)
+	response.Token = helpers.NewNullable(token)
+	s.logger.Info("Service Level: Successfully created share link")
+	return &response, nil
+}
+
+// GetShareLinks lists the share links of a case, revoked and expired ones included,
+// with their access counts. Only owners may list them.
+func (s *CaseServiceImpl) GetShareLinks(ctx context.Context, id, callerID primitive.ObjectID) ([]dtos.ShareLinkResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve share links")
+	if err := s.AuthorizeCaseRole(ctx, id, callerID, models.CaseRoleOwner); err != nil {
+		return nil, err
+	}
+	links, err := s.shareLinkRepo.GetShareLinks(ctx, id)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve share links", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve share links", "get_share_links_failed")
+	}
+	s.logger.Info("Service Level: Successfully retrieved share links")
+	return s.linkMapper.ShareLinksToDTO(links, time.Now()), nil
+}
+
+// RevokeShareLink stops a share link from working. Only owners may revoke links.
+func (s *CaseServiceImpl) RevokeShareLink(ctx context.Context, id, linkID, callerID primitive.ObjectID) error {
+	s.logger.Info("Service Level: Attempting to revoke share link")
+	if err := s.AuthorizeCaseRole(ctx, id, callerID, models.CaseRoleOwner); err != nil {
+		return err
+	}
+	if _, err := s.shareLinkRepo.RevokeShareLink(ctx, id, linkID, callerID); err != nil {
+		if stderrors.Is(err, daos.ErrShareLinkNotFound) {
+			return errors.NewNotFoundError("Share link not found", "share_link_not_found")
+		}
+		s.logger.Error("Service Level: Failed to revoke share link", err)
+		return errors.NewDatabaseError("Failed to revoke share link", "revoke_share_link_failed")
+	}
+	s.recordActivity(ctx, id, callerID, models.CaseActionRevokeShareLink, "share")
+	s.logger.Info("Service Level: Successfully revoked share link")
+	return nil
+}
+
+// GetSharedCase returns the redacted, read-only view of the case behind a share
+// link and counts the access. Unknown, revoked and expired links, as well as links
+// to cases in the trash, are all reported as not found.
+func (s *CaseServiceImpl) GetSharedCase(ctx context.Context, token, password string) (*dtos.CaseResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve shared case")
+	notFound := errors.NewNotFoundError("Share link not found", "share_link_not_found")
+	if token == "" {
+		return nil, notFound
+	}
+	link, err := s.shareLinkRepo.GetShareLinkByTokenHash(ctx, hashSecretToken(token))
+	if err != nil {
+		if stderrors.Is(err, daos.ErrShareLinkNotFound) {
+			return nil, notFound
+		}
+		s.logger.Error("Service Level: Failed to retrieve share link", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve shared case", "get_shared_case_failed")
+	}
+	now := time.Now()
+	if !link.Active(now) {
+		s.logger.Warn("Service Level: Share link is revoked or expired")
+		return nil, notFound
+	}
+	if link.PasswordHash != "" {
+		if password == "" {
+			return nil, errors.NewAuthorizationError("This share link requires a password", "share_password_required")
+		}
+		if bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)) != nil {
+			s.logger.Warn("Service Level: Incorrect share link password")
+			return nil, errors.NewAuthorizationError("Incorrect share link password", "share_password_invalid")
+		}
+	}
+
+	caseModel, err := s.caseRepo.GetCaseByID(ctx, link.CaseID)
+	if err != nil {
+		if stderrors.Is(err, daos.ErrCaseNotFound) {
+			return nil, notFound
+		}
+		s.logger.Error("Service Level: Failed to retrieve shared case", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve shared case", "get_shared_case_failed")
+	}
+	caseModel.Messages, err = s.messageRepo.GetMessagesByCaseID(ctx, link.CaseID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve shared case messages", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve shared case", "get_shared_case_failed")
+	}
+	// A failure to count the access must not hide the case from its reader.
+	if err := s.shareLinkRepo.RecordAccess(ctx, link.ID, now); err != nil {
+		s.logger.Error("Service Level: Failed to record share link access", err)
+	}
+	s.logger.Info("Service Level: Successfully retrieved shared case")
+	return s.mapper.SharedCaseToDTO(&caseModel), nil
+}
+
+// newSecretToken returns a random URL-safe token.
+func newSecretToken() (string, error) {
+	buf := make([]byte, secretTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashSecretToken returns the form under which a token is stored and looked up.
+// Tokens carry enough randomness that a fast hash is sufficient.
+func hashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/repositories"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/services/mappers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CaseTemplateService defines the operations available for managing case templates.
+type CaseTemplateService interface {
+	CreateTemplate(ctx context.Context, creatorID primitive.ObjectID, request dtos.CreateCaseTemplateRequest) (*dtos.CaseTemplateResponse, error)
+	GetTemplates(ctx context.Context, userID primitive.ObjectID) ([]dtos.CaseTemplateResponse, error)
+	GetTemplateByID(ctx context.Context, id, userID primitive.ObjectID) (*dtos.CaseTemplateResponse, error)
+	UpdateTemplate(ctx context.Context, id, userID primitive.ObjectID, request dtos.UpdateCaseTemplateRequest) (*dtos.CaseTemplateResponse, error)
+	DeleteTemplate(ctx context.Context, id, userID primitive.ObjectID) error
+}
+
+// CaseTemplateServiceImpl implements the CaseTemplateService interface.
+type CaseTemplateServiceImpl struct {
+	templateRepo *repositories.CaseTemplateRepository
+	mapper       *mappers.CaseTemplateConversionServiceImpl
+	logger       logs.Logger
+}
+
+// NewCaseTemplateService creates a new instance of the case template service.
+func NewCaseTemplateService(templateRepo *repositories.CaseTemplateRepository, mapper *mappers.CaseTemplateConversionServiceImpl, logger logs.Logger) *CaseTemplateServiceImpl {
+	return &CaseTemplateServiceImpl{
+		templateRepo: templateRepo,
+		mapper:       mapper,
+		logger:       logger,
+	}
+}
+
+// CreateTemplate creates a personal template, or a team template for a team the
+// creator belongs to.
+func (s *CaseTemplateServiceImpl) CreateTemplate(ctx context.Context, creatorID primitive.ObjectID, request dtos.CreateCaseTemplateRequest) (*dtos.CaseTemplateResponse, error) {
+	s.logger.Info("Service Level: Attempting to create case template")
+	name := strings.TrimSpace(request.Name.OrElse(""))
+	if name == "" {
+		return nil, errors.NewIncorrectInputError("A template name is required", "template_name_required")
+	}
+	messages, err := s.mapper.DTOToTemplateMessages(request.Messages.OrElse(nil))
+	if err != nil {
+		return nil, errors.NewIncorrectInputError(err.Error(), "invalid_message")
+	}
+	tags, err := normalizeTags(request.Tags.OrElse(nil))
+	if err != nil {
+		return nil, err
+	}
+	if teamID := request.TeamID.OrElse(primitive.NilObjectID); !teamID.IsZero() {
+		teams, err := s.templateRepo.GetTeamsForUser(ctx, creatorID)
+		if err != nil {
+			s.logger.Error("Service Level: Failed to retrieve user teams", err)
+			return nil, errors.NewDatabaseError("Failed to retrieve teams", "get_teams_failed")
+		}
+		if _, ok := findTeam(teams, teamID); !ok {
+			return nil, errors.NewForbiddenError("You are not a member of this team", "team_access_denied")
+		}
+	}
+
+	now := time.Now()
+	template := models.CaseTemplate{
+		ID:        primitive.NewObjectID(),
+		Name:      name,
+		CreatorID: creatorID,
+		TeamID:    request.TeamID.OrElse(primitive.NilObjectID),
+		AgentID:   request.AgentID.OrElse(primitive.NilObjectID),
+		Action:    request.Action.OrElse(""),
+		Messages:  messages,
+		Tags:      tags,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.templateRepo.CreateTemplate(ctx, &template); err != nil {
+		s.logger.Error("Service Level: Failed to create case template", err)
+		return nil, errors.NewDatabaseError("Failed to create case template", "create_template_failed")
+	}
+	response := s.mapper.TemplateToDTO(template)
+	s.logger.Info("Service Level: Successfully created case template")
+	return &response, nil
+}
+
+// GetTemplates lists the personal templates of a user and those of their teams.
+func (s *CaseTemplateServiceImpl) GetTemplates(ctx context.Context, userID primitive.ObjectID) ([]dtos.CaseTemplateResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve case templates")
+	templates, err := s.templateRepo.GetTemplatesForUser(ctx, userID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve case templates", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve case templates", "get_templates_failed")
+	}
+	s.logger.Info("Service Level: Successfully retrieved case templates")
+	return s.mapper.TemplatesToDTO(templates), nil
+}
+
+// GetTemplateByID retrieves a template the user may use.
+func (s *CaseTemplateServiceImpl) GetTemplateByID(ctx context.Context, id, userID primitive.ObjectID) (*dtos.CaseTemplateResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve case template")
+	template, _, err := loadTemplate(ctx, s.templateRepo, s.logger, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	response := s.mapper.TemplateToDTO(template)
+	s.logger.Info("Service Level: Successfully retrieved case template")
+	return &response, nil
+}
+
+// UpdateTemplate changes a template. Personal templates can be changed by their
+// creator, team templates by their creator and the team admin.
+func (s *CaseTemplateServiceImpl) UpdateTemplate(ctx context.Context, id, userID primitive.ObjectID, request dtos.UpdateCaseTemplateRequest) (*dtos.CaseTemplateResponse, error) {
+	s.logger.Info("Service Level: Attempting to update case template")
+	template, canManage, err := loadTemplate(ctx, s.templateRepo, s.logger, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
+		return nil, errors.NewForbiddenError("You cannot change this template", "template_access_denied")
+	}
+
+	set := bson.M{}
+	if request.Name.Present {
+		name := strings.TrimSpace(request.Name.Value)
+		if name == "" {
+			return nil, errors.NewIncorrectInputError("A template name is required", "template_name_required")
+		}
+		set["name"], template.Name = name, name
+	}
+	if request.AgentID.Present {
+		set["agent_id"], template.AgentID = request.AgentID.Value, request.AgentID.Value
+	}
+	if request.Action.Present {
+		set["action"], template.Action = request.Action.Value, request.Action.Value
+	}
+	if request.Messages.Present {
+		messages, err := s.mapper.DTOToTemplateMessages(request.Messages.Value)
+		if err != nil {
+			return nil, errors.NewIncorrectInputError(err.Error(), "invalid_message")
+		}
+		set["messages"], template.Messages = messages, messages
+	}
+	if request.Tags.Present {
+		tags, err := normalizeTags(request.Tags.Value)
+		if err != nil {
+			return nil, err
+		}
+		set["tags"], template.Tags = tags, tags
+	}
+	template.UpdatedAt = time.Now()
+	set["updated_at"] = template.UpdatedAt
+
+	if err := s.templateRepo.UpdateTemplate(ctx, id, bson.M{"$set": set}); err != nil {
+		if stderrors.Is(err, daos.ErrTemplateNotFound) {
+			return nil, errors.NewNotFoundError("Template not found", "template_not_found")
+		}
+		s.logger.Error("Service Level: Failed to update case template", err)
+		return nil, errors.NewDatabaseError("Failed to update case template", "update_template_failed")
+	}
+	response := s.mapper.TemplateToDTO(template)
+	s.logger.Info("Service Level: Successfully updated case template")
+	return &response, nil
+}
+
+// DeleteTemplate deletes a template the user may manage.
+func (s *CaseTemplateServiceImpl) DeleteTemplate(ctx context.Context, id, userID primitive.ObjectID) error {
+	s.logger.Info("Service Level: Attempting to delete case template")
+	_, canManage, err := loadTemplate(ctx, s.templateRepo, s.logger, id, userID)
+	if err != nil {
+		return err
+	}
+	if !canManage {
+		return errors.NewForbiddenError("You cannot delete this template", "template_access_denied")
+	}
+	if err := s.templateRepo.DeleteTemplate(ctx, id); err != nil {
+		if stderrors.Is(err, daos.ErrTemplateNotFound) {
+			return errors.NewNotFoundError("Template not found", "template_not_found")
+		}
+		s.logger.Error("Service Level: Failed to delete case template", err)
+		return errors.NewDatabaseError("Failed to delete case template", "delete_template_failed")
+	}
+	s.logger.Info("Service Level: Successfully deleted case template")
+	return nil
+}
+
+// loadTemplate retrieves a template the user may use and reports whether they may
+// also manage it. Templates the user cannot see are reported as missing.
+func loadTemplate(ctx context.Context, templateRepo *repositories.CaseTemplateRepository, logger logs.Logger, id, userID primitive.ObjectID) (models.CaseTemplate, bool, error) {
+	template, err := templateRepo.GetTemplateByID(ctx, id)
+	if err != nil {
+		if stderrors.Is(err, daos.ErrTemplateNotFound) {
+			return template, false, errors.NewNotFoundError("Template not found", "template_not_found")
+		}
+		logger.Error("Service Level: Failed to retrieve case template", err)
+		return template, false, errors.NewDatabaseError("Failed to retrieve case template", "get_template_failed")
+	}
+	if template.TeamID.IsZero() {
+		if template.CreatorID != userID {
+			return template, false, errors.NewNotFoundError("Template not found", "template_not_found")
+		}
+		return template, true, nil
+	}
+	teams, err := templateRepo.GetTeamsForUser(ctx, userID)
+	if err != nil {
+		logger.Error("Service Level: Failed to retrieve user teams", err)
+		return template, false, errors.NewDatabaseError("Failed to retrieve teams", "get_teams_failed")
+	}
+	team, ok := findTeam(teams, template.TeamID)
+	if !ok {
+		return template, false, errors.NewNotFoundError("Template not found", "template_not_found")
+	}
+	return template, template.CreatorID == userID || team.AdminID == userID, nil
+}
+
+func findTeam(teams []models.Team, id primitive.ObjectID) (models.Team, bool) {
+	for _, team := range teams {
+		if team.ID == id {
+			return team, true
+		}
+	}
+	return models.Team{}, false
+}
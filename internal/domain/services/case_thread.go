@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetMessageThread retrieves a top-level message with the replies in its side
+// thread, oldest first. Asking for a reply returns the whole thread it belongs to.
+func (s *CaseServiceImpl) GetMessageThread(ctx context.Context, id, messageID, callerID primitive.ObjectID) (*dtos.MessageThreadResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve message thread")
+	if err := s.AuthorizeCaseAccess(ctx, id, callerID); err != nil {
+		return nil, err
+	}
+	parent, err := s.messageRepo.GetMessageByID(ctx, id, messageID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve thread parent", err)
+		return nil, messageError(err)
+	}
+	if parent.IsReply() {
+		parent, err = s.messageRepo.GetMessageByID(ctx, id, parent.ParentMessageID)
+		if err != nil {
+			s.logger.Error("Service Level: Failed to retrieve thread parent", err)
+			return nil, messageError(err)
+		}
+	}
+	replies, err := s.messageRepo.GetReplies(ctx, id, parent.ID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve thread replies", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve thread", "get_message_thread_failed")
+	}
+
+	parentDTO := s.mapper.MessageToDTO(parent)
+	parentDTO.ReplyCount = helpers.Nullable[int]{Value: len(replies), Present: true}
+	s.logger.Info("Service Level: Successfully retrieved message thread")
+	return &dtos.MessageThreadResponse{
+		Parent:  parentDTO,
+		Replies: s.mapper.MessagesToDTO(replies),
+	}, nil
+}
+
+// resolveThreads checks the parents of the thread replies among new messages.
+// Threads are one level deep: a reply to a reply joins the thread of the
+// top-level message. Parents must be live messages of the same case.
+func (s *CaseServiceImpl) resolveThreads(ctx context.Context, id primitive.ObjectID, messages []models.Message) error {
+	roots := make(map[primitive.ObjectID]primitive.ObjectID)
+	for i := range messages {
+		parentID := messages[i].ParentMessageID
+		if parentID.IsZero() {
+			continue
+		}
+		root, ok := roots[parentID]
+		if !ok {
+			parent, err := s.messageRepo.GetMessageByID(ctx, id, parentID)
+			if err != nil {
+				if stderrors.Is(err, daos.ErrMessageNotFound) {
+					return errors.NewNotFoundError("Parent message not found", "parent_message_not_found")
+				}
+				s.logger.Error("Service Level: Failed to retrieve parent message", err)
+				return errors.NewDatabaseError("Failed to append messages", "append_messages_failed")
+			}
+			if !parent.DeletedAt.IsZero() {
+				return errors.NewIncorrectInputError("Cannot reply to a deleted message", "parent_message_deleted")
+			}
+			root = parent.ID
+			if parent.IsReply() {
+				root = parent.ParentMessageID
+			}
+			roots[parentID] = root
+		}
+		messages[i].ParentMessageID = root
+	}
+	return nil
+}
+
+// resolveNewThreads gives the messages of a new case their IDs and points each
+// reply at the root of its thread under the new IDs. Parents are referred to by
+// the IDs the messages were listed with, and must come before their replies.
+func resolveNewThreads(messages []models.Message) error {
+	newIDs := make(map[primitive.ObjectID]primitive.ObjectID, len(messages))
+	roots := make(map[primitive.ObjectID]primitive.ObjectID, len(messages))
+	for i := range messages {
+		listedID := messages[i].ID
+		if _, ok := newIDs[listedID]; ok && !listedID.IsZero() {
+			return errors.NewIncorrectInputError("Message "+listedID.Hex()+" is listed more than once", "duplicate_message")
+		}
+		id := primitive.NewObjectID()
+		root := id
+		if parentID := messages[i].ParentMessageID; !parentID.IsZero() {
+			parent, ok := newIDs[parentID]
+			if !ok {
+				return errors.NewNotFoundError("Parent message not found", "parent_message_not_found")
+			}
+			root = roots[parent]
+			messages[i].ParentMessageID = root
+		}
+		if !listedID.IsZero() {
+			newIDs[listedID] = id
+		}
+		roots[id] = root
+		messages[i].ID = id
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestResolveNewThreads(t *testing.T) {
+	first, second, third := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+
+	tests := []struct {
+		name     string
+		messages []models.Message
+		roots    []int // index of the thread root of each message, -1 for none
+		wantErr  bool
+	}{
+		{
+			name: "reply points at its parent under the new ID",
+			messages: []models.Message{
+				{ID: first},
+				{ID: second, ParentMessageID: first},
+			},
+			roots: []int{-1, 0},
+		},
+		{
+			name: "reply to a reply joins the thread root",
+			messages: []models.Message{
+				{ID: first},
+				{ID: second, ParentMessageID: first},
+				{ID: third, ParentMessageID: second},
+			},
+			roots: []int{-1, 0, 0},
+		},
+		{
+			name: "messages without listed IDs",
+			messages: []models.Message{
+				{},
+				{},
+			},
+			roots: []int{-1, -1},
+		},
+		{
+			name: "parent listed after its reply",
+			messages: []models.Message{
+				{ID: second, ParentMessageID: first},
+				{ID: first},
+			},
+			wantErr: true,
+		},
+		{
+			name: "parent outside the case",
+			messages: []models.Message{
+				{ID: first, ParentMessageID: primitive.NewObjectID()},
+			},
+			wantErr: true,
+		},
+		{
+			name: "message listed twice",
+			messages: []models.Message{
+				{ID: first},
+				{ID: first},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := resolveNewThreads(tt.messages)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveNewThreads() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			seen := make(map[primitive.ObjectID]bool, len(tt.messages))
+			for i, message := range tt.messages {
+				if message.ID.IsZero() || message.ID == first || message.ID == second || message.ID == third || seen[message.ID] {
+					t.Errorf("message %d kept ID %s, want a new one", i, message.ID.Hex())
+				}
+				seen[message.ID] = true
+				want := primitive.NilObjectID
+				if tt.roots[i] >= 0 {
+					want = tt.messages[tt.roots[i]].ID
+				}
+				if message.ParentMessageID != want {
+					t.Errorf("parent of message %d = %s, want %s", i, message.ParentMessageID.Hex(), want.Hex())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetToolInvocations lists the tool calls made in a case, in conversation order,
+// each with its result once one has been recorded.
+func (s *CaseServiceImpl) GetToolInvocations(ctx context.Context, id, callerID primitive.ObjectID) ([]dtos.ToolInvocationResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve tool invocations")
+	if err := s.AuthorizeCaseAccess(ctx, id, callerID); err != nil {
+		return nil, err
+	}
+	messages, err := s.messageRepo.GetToolMessages(ctx, id)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve tool messages", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve tool invocations", "get_tool_invocations_failed")
+	}
+	s.logger.Info("Service Level: Successfully retrieved tool invocations")
+	return s.mapper.ToolInvocationsToDTO(messages), nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/events"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// GetTrashedCases lists the trashed cases a user created or collaborates on,
+// most recently deleted first. Messages are not included.
+func (s *CaseServiceImpl) GetTrashedCases(ctx context.Context, userID primitive.ObjectID) ([]dtos.CaseResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve trashed cases")
+	cases, err := s.caseRepo.GetTrashedCases(ctx, userID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve trashed cases", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve trashed cases", "get_trashed_cases_failed")
+	}
+	caseResponses := s.mapper.CasesToDTO(cases)
+	if caseResponses == nil {
+		caseResponses = []dtos.CaseResponse{}
+	}
+	s.logger.Info("Service Level: Successfully retrieved trashed cases")
+	return caseResponses, nil
+}
+
+// RestoreCase takes a case out of the trash. Only its owners may restore it.
+func (s *CaseServiceImpl) RestoreCase(ctx context.Context, id, actorID primitive.ObjectID) (*dtos.CaseResponse, error) {
+	s.logger.Info("Service Level: Attempting to restore case from trash")
+	trashed, err := s.caseRepo.GetTrashedCaseByID(ctx, id)
+	if err != nil {
+		if stderrors.Is(err, daos.ErrCaseNotFound) {
+			return nil, errors.NewNotFoundError("Case not found in trash", "trashed_case_not_found")
+		}
+		s.logger.Error("Service Level: Failed to retrieve trashed case", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve trashed case", "get_trashed_case_failed")
+	}
+	if err := checkCaseRole(trashed, actorID, models.CaseRoleOwner); err != nil {
+		s.logger.Warn("Service Level: User lacks the required role on case")
+		return nil, err
+	}
+	if err := s.caseRepo.RestoreCase(ctx, id, time.Now()); err != nil {
+		if stderrors.Is(err, daos.ErrCaseNotFound) {
+			return nil, errors.NewNotFoundError("Case not found in trash", "trashed_case_not_found")
+		}
+		s.logger.Error("Service Level: Failed to restore case from trash", err)
+		return nil, errors.NewDatabaseError("Failed to restore case", "restore_case_failed")
+	}
+	s.recordMutation(ctx, id, actorID, models.CaseActionRestore, "deleted_at")
+
+	restoredCase, err := s.loadCaseResponse(ctx, id)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve restored case", err)
+		return nil, err
+	}
+	s.publish(id, events.CaseRestored, map[string]interface{}{"id": id})
+	s.logger.Info("Service Level: Successfully restored case from trash")
+	return restoredCase, nil
+}
+
+// PurgeTrashedCases permanently deletes the cases trashed before the cutoff,
+// together with their messages, their version history and the references users
+// hold to them. It returns how many cases were purged; a case that fails part way
+// stays in the trash and is retried on the next run.
+func (s *CaseServiceImpl) PurgeTrashedCases(ctx context.Context, cutoff time.Time) (int, error) {
+	s.logger.Info("Service Level: Attempting to purge trashed cases")
+	expired, err := s.caseRepo.GetCasesTrashedBefore(ctx, cutoff)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve expired trashed cases", err)
+		return 0, errors.NewDatabaseError("Failed to retrieve trashed cases", "get_trashed_cases_failed")
+	}
+	purged := 0
+	for _, caseModel := range expired {
+		if err := s.purgeCase(ctx, caseModel.ID); err != nil {
+			s.logger.Error("Service Level: Failed to purge trashed case", err, zap.String("case_id", caseModel.ID.Hex()))
+			continue
+		}
+		purged++
+	}
+	s.logger.Info("Service Level: Successfully purged trashed cases", zap.Int("purged", purged))
+	return purged, nil
+}
+
+// purgeCase removes everything belonging to a case. The case document goes last so
+// that an interrupted purge can be picked up again.
+func (s *CaseServiceImpl) purgeCase(ctx context.Context, id primitive.ObjectID) error {
+	if err := s.messageRepo.DeleteMessagesByCaseID(ctx, id); err != nil {
+		return err
+	}
+	if err := s.versionRepo.DeleteVersions(ctx, id); err != nil {
+		return err
+	}
+	if err := s.shareLinkRepo.DeleteShareLinks(ctx, id); err != nil {
+		return err
+	}
+	if err := s.inviteRepo.DeleteInvitations(ctx, id); err != nil {
+		return err
+	}
+	if err := s.activityRepo.DeleteActivity(ctx, id); err != nil {
+		return err
+	}
+	if err := s.annotationRepo.DeleteAnnotations(ctx, id); err != nil {
+		return err
+	}
+	if err := s.documentRepo.DeleteDocuments(ctx, id); err != nil {
+		return err
+	}
+	if err := s.userRepo.RemoveCaseReferences(ctx, id); err != nil {
+		return err
+	}
+	return s.caseRepo.DeleteCase(ctx, id)
+}
+
+// RunTrashPurge purges cases that have been in the trash longer than retention,
+// once at start-up and then every interval, until ctx is cancelled. A
+// non-positive retention disables the purge.
+func (s *CaseServiceImpl) RunTrashPurge(ctx context.Context, retention, interval time.Duration) {
+	if retention <= 0 || interval <= 0 {
+		s.logger.Info("Trash purge disabled")
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.PurgeTrashedCases(ctx, time.Now().Add(-retention)); err != nil {
+			s.logger.Error("Trash purge failed", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
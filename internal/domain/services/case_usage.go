@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/pricing"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// priceMessages works out the cost of the token usage reported on messages.
+// Models listed in the price table are charged at their listed prices, others
+// at the price of the case's agent, taken per million tokens. The agent is only
+// looked up when a message needs it; when agentID is zero it is taken from the
+// case. Usage that cannot be priced is stored at no cost.
+func (s *CaseServiceImpl) priceMessages(ctx context.Context, caseID, agentID primitive.ObjectID, messages []models.Message) {
+	var agentPrice *pricing.Price
+	for i := range messages {
+		usage := messages[i].Usage
+		if usage == nil {
+			continue
+		}
+		price, ok := s.prices.Lookup(usage.Model)
+		if !ok {
+			if agentPrice == nil {
+				resolved := s.agentPrice(ctx, caseID, agentID)
+				agentPrice = &resolved
+			}
+			price = *agentPrice
+		}
+		usage.Cost = price.Cost(usage.PromptTokens, usage.CompletionTokens)
+	}
+}
+
+// agentPrice is the flat token price of the agent answering in a case.
+func (s *CaseServiceImpl) agentPrice(ctx context.Context, caseID, agentID primitive.ObjectID) pricing.Price {
+	if agentID.IsZero() {
+		caseModel, err := s.caseRepo.GetCaseByID(ctx, caseID)
+		if err != nil {
+			s.logger.Error("Service Level: Failed to retrieve case to price usage", err)
+			return pricing.Price{}
+		}
+		agentID = caseModel.AgentID
+	}
+	if agentID.IsZero() {
+		return pricing.Price{}
+	}
+	agent, err := s.agentRepo.GetAgentByID(ctx, agentID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve agent to price usage", err)
+		return pricing.Price{}
+	}
+	return pricing.Flat(agent.Price)
+}
+
+// GetUsage totals the token usage and cost of the cases the caller can access,
+// grouped by case, by user or by team. Usage is attributed to the creator of a
+// case and to the creator's team; creators without a team share a group without
+// an ID.
+func (s *CaseServiceImpl) GetUsage(ctx context.Context, callerID primitive.ObjectID, query dtos.UsageQuery) (*dtos.UsageReportResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve usage")
+	switch query.GroupBy {
+	case dtos.UsageByCase, dtos.UsageByUser, dtos.UsageByTeam:
+	default:
+		return nil, errors.NewIncorrectInputError("group_by must be case, user or team", "invalid_usage_group")
+	}
+	if query.From.Present && query.To.Present && query.To.Value.Before(query.From.Value) {
+		return nil, errors.NewIncorrectInputError("The end of the date range must not precede its start", "invalid_usage_range")
+	}
+
+	accessible, err := s.caseRepo.GetAccessibleCases(ctx, callerID, primitive.NilObjectID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve accessible cases", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve usage", "get_usage_failed")
+	}
+	cases := make(map[primitive.ObjectID]models.Case, len(accessible))
+	ids := make([]primitive.ObjectID, len(accessible))
+	for i, caseModel := range accessible {
+		cases[caseModel.ID] = caseModel
+		ids[i] = caseModel.ID
+	}
+	usage, err := s.messageRepo.SumUsage(ctx, ids, query.From.Value, query.To.Value)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to sum usage", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve usage", "get_usage_failed")
+	}
+
+	report := &dtos.UsageReportResponse{
+		GroupBy: query.GroupBy,
+		From:    query.From,
+		To:      query.To,
+		Groups:  []dtos.UsageGroupResponse{},
+	}
+	groups := make(map[primitive.ObjectID]*dtos.UsageGroupResponse)
+	users := make(map[primitive.ObjectID]*models.User)
+	for _, caseUsage := range usage {
+		caseModel := cases[caseUsage.CaseID]
+		var key primitive.ObjectID
+		var name helpers.Nullable[string]
+		switch query.GroupBy {
+		case dtos.UsageByCase:
+			key, name = caseModel.ID, helpers.NewNullable(caseModel.Name)
+		case dtos.UsageByUser:
+			key = caseModel.CreatorID
+			if user := s.usageUser(ctx, users, key); user != nil {
+				name = helpers.NewNullable(strings.TrimSpace(user.FirstName + " " + user.LastName))
+			}
+		case dtos.UsageByTeam:
+			if user := s.usageUser(ctx, users, caseModel.CreatorID); user != nil {
+				key = user.TeamID
+			}
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &dtos.UsageGroupResponse{ID: helpers.NewNullable(key), Name: name}
+			groups[key] = group
+		}
+		addUsage(&group.UsageTotals, caseUsage)
+		addUsage(&report.Total, caseUsage)
+	}
+	for _, group := range groups {
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+		return a.ID.Value.Hex() < b.ID.Value.Hex()
+	})
+	s.logger.Info("Service Level: Successfully retrieved usage")
+	return report, nil
+}
+
+// usageUser loads a user once per report; users that cannot be found are nil.
+func (s *CaseServiceImpl) usageUser(ctx context.Context, users map[primitive.ObjectID]*models.User, id primitive.ObjectID) *models.User {
+	if user, ok := users[id]; ok {
+		return user
+	}
+	user, err := s.userRepo.FindUserByID(ctx, id)
+	if err != nil {
+		s.logger.Warn("Service Level: User of usage report not found")
+		user = nil
+	}
+	users[id] = user
+	return user
+}
+
+func addUsage(totals *dtos.UsageTotals, usage models.CaseUsage) {
+	totals.PromptTokens += usage.PromptTokens
+	totals.CompletionTokens += usage.CompletionTokens
+	totals.TotalTokens += usage.PromptTokens + usage.CompletionTokens
+	totals.Cost += usage.Cost
+	totals.Messages += usage.Messages
+}
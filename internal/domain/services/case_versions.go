@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// recordMutation records a successful write in the activity feed and snapshots
+// the case. The snapshot keeps the case fields and references the live messages
+// by revision, so it stays small however long the conversation grows. The write
+// itself has already been applied, so a failed snapshot is logged rather than
+// failing the request: a client retrying it would apply the change twice.
+func (s *CaseServiceImpl) recordMutation(ctx context.Context, caseID, actorID primitive.ObjectID, action string, fields ...string) {
+	s.recordActivity(ctx, caseID, actorID, action, fields...)
+	caseModel, err := s.caseRepo.GetCaseByID(ctx, caseID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to load case for version snapshot", err, zap.String("case_id", caseID.Hex()), zap.String("action", action))
+		return
+	}
+	refs, err := s.messageRepo.GetMessageRefs(ctx, caseID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to load messages for version snapshot", err, zap.String("case_id", caseID.Hex()), zap.String("action", action))
+		return
+	}
+	if _, err := s.versionRepo.CreateVersion(ctx, caseModel, refs, action, actorID); err != nil {
+		s.logger.Error("Service Level: Failed to record case version", err, zap.String("case_id", caseID.Hex()), zap.String("action", action))
+	}
+}
+
+// resolveVersionMessages fills in the snapshot messages of versions that refer to
+// them, as they stood at the recorded revisions. Messages that no longer exist are
+// left out; versions that embed their messages are left as they are.
+func (s *CaseServiceImpl) resolveVersionMessages(ctx context.Context, caseID primitive.ObjectID, versions ...*models.CaseVersion) error {
+	if !slices.ContainsFunc(versions, func(version *models.CaseVersion) bool { return len(version.Messages) > 0 }) {
+		return nil
+	}
+	messages, err := s.messageRepo.GetAllMessagesByCaseID(ctx, caseID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve messages of case version", err)
+		return errors.NewDatabaseError("Failed to retrieve case version", "get_case_version_failed")
+	}
+	byID := make(map[primitive.ObjectID]models.Message, len(messages))
+	for _, message := range messages {
+		byID[message.ID] = message
+	}
+	for _, version := range versions {
+		if len(version.Messages) == 0 {
+			continue
+		}
+		resolved := make([]models.Message, 0, len(version.Messages))
+		for _, ref := range version.Messages {
+			if message, ok := byID[ref.ID]; ok {
+				resolved = append(resolved, message.AtRevision(ref.Revision))
+			}
+		}
+		version.Snapshot.Messages = resolved
+	}
+	return nil
+}
+
+// GetCaseVersions lists the recorded versions of a case, newest first.
+func (s *CaseServiceImpl) GetCaseVersions(ctx context.Context, id, callerID primitive.ObjectID) ([]dtos.CaseVersionResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve case versions")
+	if err := s.AuthorizeCaseAccess(ctx, id, callerID); err != nil {
+		return nil, err
+	}
+	versions, err := s.versionRepo.GetVersions(ctx, id)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve case versions", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve case versions", "get_case_versions_failed")
+	}
+	s.logger.Info("Service Level: Successfully retrieved case versions")
+	return s.mapper.CaseVersionsToDTO(versions), nil
+}
+
+// GetCaseVersion retrieves a single version of a case with its full snapshot.
+func (s *CaseServiceImpl) GetCaseVersion(ctx context.Context, id, callerID primitive.ObjectID, version int) (*dtos.CaseVersionResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve case version")
+	if err := s.AuthorizeCaseAccess(ctx, id, callerID); err != nil {
+		return nil, err
+	}
+	stored, err := s.loadVersion(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resolveVersionMessages(ctx, id, &stored); err != nil {
+		return nil, err
+	}
+	response := s.mapper.CaseVersionToDTO(stored, true)
+	s.logger.Info("Service Level: Successfully retrieved case version")
+	return &response, nil
+}
+
+// DiffCaseVersions compares two versions of a case field by field and message by
+// message, matching messages on their IDs.
+func (s *CaseServiceImpl) DiffCaseVersions(ctx context.Context, id, callerID primitive.ObjectID, from, to int) (*dtos.CaseVersionDiffResponse, error) {
+	s.logger.Info("Service Level: Attempting to diff case versions")
+	if err := s.AuthorizeCaseAccess(ctx, id, callerID); err != nil {
+		return nil, err
+	}
+	older, err := s.loadVersion(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+	newer, err := s.loadVersion(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resolveVersionMessages(ctx, id, &older, &newer); err != nil {
+		return nil, err
+	}
+
+	diff := &dtos.CaseVersionDiffResponse{
+		From:            helpers.Nullable[int]{Value: from, Present: true},
+		To:              helpers.Nullable[int]{Value: to, Present: true},
+		Fields:          diffCaseFields(older.Snapshot, newer.Snapshot),
+		MessagesAdded:   []dtos.MessageResponse{},
+		MessagesRemoved: []dtos.MessageResponse{},
+		MessagesChanged: []dtos.MessageChange{},
+	}
+
+	previous := make(map[primitive.ObjectID]models.Message, len(older.Snapshot.Messages))
+	for _, message := range older.Snapshot.Messages {
+		previous[message.ID] = message
+	}
+	for _, message := range newer.Snapshot.Messages {
+		before, ok := previous[message.ID]
+		if !ok {
+			diff.MessagesAdded = append(diff.MessagesAdded, s.mapper.MessageToDTO(message))
+			continue
+		}
+		delete(previous, message.ID)
+		if messageChanged(before, message) {
+			diff.MessagesChanged = append(diff.MessagesChanged, dtos.MessageChange{
+				ID:   helpers.NewNullable(message.ID),
+				From: s.mapper.MessageToDTO(before),
+				To:   s.mapper.MessageToDTO(message),
+			})
+		}
+	}
+	for _, message := range older.Snapshot.Messages {
+		if _, removed := previous[message.ID]; removed {
+			diff.MessagesRemoved = append(diff.MessagesRemoved, s.mapper.MessageToDTO(message))
+		}
+	}
+
+	s.logger.Info("Service Level: Successfully diffed case versions")
+	return diff, nil
+}
+
+// RestoreCaseVersion brings a case back to the state of an earlier version,
+// fields and messages alike. Messages added since are soft-deleted and changed
+// ones restored, so no message or history is lost. The restore is itself
+// recorded as a new version, so the state it replaces stays available. Since the restore also brings back the
+// collaborators of that version, only owners may perform it.
+func (s *CaseServiceImpl) RestoreCaseVersion(ctx context.Context, id, actorID primitive.ObjectID, version int) (*dtos.CaseResponse, error) {
+	s.logger.Info("Service Level: Attempting to restore case version")
+	if err := s.AuthorizeCaseRole(ctx, id, actorID, models.CaseRoleOwner); err != nil {
+		return nil, err
+	}
+	stored, err := s.loadVersion(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.resolveVersionMessages(ctx, id, &stored); err != nil {
+		return nil, err
+	}
+	snapshot := stored.Snapshot
+	if snapshot.Collaborators == nil {
+		snapshot.Collaborators = []models.Collaborators{}
+	}
+	now := time.Now()
+	updates := map[string]interface{}{
+		"name":          snapshot.Name,
+		"collaborators": snapshot.Collaborators,
+		"action":        snapshot.Action,
+		"agent_id":      snapshot.AgentID,
+		"share":         snapshot.Share,
+		"is_archived":   snapshot.IsArchived,
+		"last_edit":     now,
+	}
+	result, err := s.caseRepo.UpdateCase(ctx, id, updates)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to restore case fields", err)
+		return nil, errors.NewDatabaseError("Failed to restore case", "restore_case_version_failed")
+	}
+	if result.MatchedCount == 0 {
+		return nil, errors.NewNotFoundError("Case not found", "case_not_found")
+	}
+	if err := s.messageRepo.SyncMessages(ctx, id, snapshot.Messages, now, actorID); err != nil {
+		s.logger.Error("Service Level: Failed to restore case messages", err)
+		return nil, errors.NewDatabaseError("Failed to restore case", "restore_case_version_failed")
+	}
+	s.recordMutation(ctx, id, actorID, models.CaseActionRestoreVersion, append(updatedFields(updates), "messages")...)
+
+	restoredCase, err := s.loadCaseResponse(ctx, id)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve restored case", err)
+		return nil, err
+	}
+	s.publishCaseUpdated(restoredCase)
+	s.logger.Info("Service Level: Successfully restored case version")
+	return restoredCase, nil
+}
+
+func (s *CaseServiceImpl) loadVersion(ctx context.Context, id primitive.ObjectID, version int) (models.CaseVersion, error) {
+	stored, err := s.versionRepo.GetVersion(ctx, id, version)
+	if err != nil {
+		if stderrors.Is(err, daos.ErrVersionNotFound) {
+			return stored, errors.NewNotFoundError("Case version not found", "case_version_not_found")
+		}
+		s.logger.Error("Service Level: Failed to retrieve case version", err)
+		return stored, errors.NewDatabaseError("Failed to retrieve case version", "get_case_version_failed")
+	}
+	return stored, nil
+}
+
+// diffCaseFields lists the case fields that differ between two snapshots.
+func diffCaseFields(older, newer models.Case) []dtos.CaseFieldChange {
+	if len(older.Collaborators) == 0 {
+		older.Collaborators = nil
+	}
+	if len(newer.Collaborators) == 0 {
+		newer.Collaborators = nil
+	}
+	fields := []struct {
+		name     string
+		from, to interface{}
+	}{
+		{"name", older.Name, newer.Name},
+		{"action", older.Action, newer.Action},
+		{"agent_id", older.AgentID, newer.AgentID},
+		{"share", older.Share, newer.Share},
+		{"is_archived", older.IsArchived, newer.IsArchived},
+		{"collaborators", older.Collaborators, newer.Collaborators},
+	}
+	changes := []dtos.CaseFieldChange{}
+	for _, field := range fields {
+		if !reflect.DeepEqual(field.from, field.to) {
+			changes = append(changes, dtos.CaseFieldChange{Field: field.name, From: field.from, To: field.to})
+		}
+	}
+	return changes
+}
+
+func messageChanged(before, after models.Message) bool {
+	return before.Content != after.Content ||
+		before.DocumentPath != after.DocumentPath ||
+		before.Sender != after.Sender ||
+		before.Recipient != after.Recipient ||
+		before.FunctionCall != after.FunctionCall ||
+		!reflect.DeepEqual(before.ToolCalls, after.ToolCalls) ||
+		!reflect.DeepEqual(before.ToolResult, after.ToolResult)
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDiffCaseFields(t *testing.T) {
+	agentA, agentB := primitive.NewObjectID(), primitive.NewObjectID()
+	collaborator := models.Collaborators{ID: primitive.NewObjectID(), Role: "viewer"}
+	promoted := models.Collaborators{ID: collaborator.ID, Role: "editor", Edit: true}
+	base := models.Case{
+		ID:      primitive.NewObjectID(),
+		Name:    "Lease dispute",
+		Action:  "draft",
+		AgentID: agentA,
+	}
+
+	tests := []struct {
+		name   string
+		older  models.Case
+		newer  func(models.Case) models.Case
+		wanted []dtos.CaseFieldChange
+	}{
+		{
+			name:   "identical snapshots",
+			older:  base,
+			newer:  func(c models.Case) models.Case { return c },
+			wanted: []dtos.CaseFieldChange{},
+		},
+		{
+			name:  "renamed case",
+			older: base,
+			newer: func(c models.Case) models.Case {
+				c.Name = "Lease dispute (appeal)"
+				return c
+			},
+			wanted: []dtos.CaseFieldChange{
+				{Field: "name", From: "Lease dispute", To: "Lease dispute (appeal)"},
+			},
+		},
+		{
+			name:  "several fields in field order",
+			older: base,
+			newer: func(c models.Case) models.Case {
+				c.IsArchived = true
+				c.AgentID = agentB
+				c.Share = true
+				return c
+			},
+			wanted: []dtos.CaseFieldChange{
+				{Field: "agent_id", From: agentA, To: agentB},
+				{Field: "share", From: false, To: true},
+				{Field: "is_archived", From: false, To: true},
+			},
+		},
+		{
+			name: "empty and missing collaborators are equal",
+			older: func() models.Case {
+				c := base
+				c.Collaborators = []models.Collaborators{}
+				return c
+			}(),
+			newer:  func(c models.Case) models.Case { c.Collaborators = nil; return c },
+			wanted: []dtos.CaseFieldChange{},
+		},
+		{
+			name: "collaborator role change",
+			older: func() models.Case {
+				c := base
+				c.Collaborators = []models.Collaborators{collaborator}
+				return c
+			}(),
+			newer: func(c models.Case) models.Case {
+				c.Collaborators = []models.Collaborators{promoted}
+				return c
+			},
+			wanted: []dtos.CaseFieldChange{
+				{Field: "collaborators", From: []models.Collaborators{collaborator}, To: []models.Collaborators{promoted}},
+			},
+		},
+		{
+			name:  "fields outside the diff are ignored",
+			older: base,
+			newer: func(c models.Case) models.Case {
+				c.Messages = []models.Message{{Content: "hello"}}
+				c.MessageSeq = 1
+				c.LastEdit = c.LastEdit.Add(time.Hour)
+				return c
+			},
+			wanted: []dtos.CaseFieldChange{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffCaseFields(tt.older, tt.newer(tt.older))
+			if !reflect.DeepEqual(got, tt.wanted) {
+				t.Errorf("diffCaseFields() = %#v, want %#v", got, tt.wanted)
+			}
+		})
+	}
+}
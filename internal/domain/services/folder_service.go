@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/daos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/repositories"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/services/mappers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/errors"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FolderService defines the operations available for organizing cases in folders.
+type FolderService interface {
+	CreateFolder(ctx context.Context, ownerID primitive.ObjectID, request dtos.CreateFolderRequest) (*dtos.FolderResponse, error)
+	GetFolders(ctx context.Context, ownerID primitive.ObjectID) ([]dtos.FolderResponse, error)
+	GetFolderByID(ctx context.Context, id, ownerID primitive.ObjectID) (*dtos.FolderResponse, error)
+	UpdateFolder(ctx context.Context, id, ownerID primitive.ObjectID, request dtos.UpdateFolderRequest) (*dtos.FolderResponse, error)
+	DeleteFolder(ctx context.Context, id, ownerID primitive.ObjectID) error
+}
+
+// FolderServiceImpl implements the FolderService interface.
+type FolderServiceImpl struct {
+	folderRepo *repositories.FolderRepository
+	mapper     *mappers.FolderConversionServiceImpl
+	logger     logs.Logger
+}
+
+// NewFolderService creates a new instance of the folder service.
+func NewFolderService(folderRepo *repositories.FolderRepository, mapper *mappers.FolderConversionServiceImpl, logger logs.Logger) *FolderServiceImpl {
+	return &FolderServiceImpl{
+		folderRepo: folderRepo,
+		mapper:     mapper,
+		logger:     logger,
+	}
+}
+
+// CreateFolder creates a folder for a user, at the root or below one of their folders.
+func (s *FolderServiceImpl) CreateFolder(ctx context.Context, ownerID primitive.ObjectID, request dtos.CreateFolderRequest) (*dtos.FolderResponse, error) {
+	s.logger.Info("Service Level: Attempting to create folder")
+	name := strings.TrimSpace(request.Name.OrElse(""))
+	if name == "" {
+		return nil, errors.NewIncorrectInputError("A folder name is required", "folder_name_required")
+	}
+	now := time.Now()
+	folder := models.Folder{
+		ID:        primitive.NewObjectID(),
+		OwnerID:   ownerID,
+		Name:      name,
+		Ancestors: []primitive.ObjectID{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if parentID := request.ParentID.OrElse(primitive.NilObjectID); !parentID.IsZero() {
+		parent, err := s.loadOwnedFolder(ctx, parentID, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		folder.ParentID = parent.ID
+		folder.Ancestors = append(append(folder.Ancestors, parent.Ancestors...), parent.ID)
+	}
+	if err := s.folderRepo.CreateFolder(ctx, &folder); err != nil {
+		s.logger.Error("Service Level: Failed to create folder", err)
+		return nil, errors.NewDatabaseError("Failed to create folder", "create_folder_failed")
+	}
+	response := s.mapper.FolderToDTO(folder)
+	s.logger.Info("Service Level: Successfully created folder")
+	return &response, nil
+}
+
+// GetFolders lists every folder of a user. Clients build the tree from parent_id.
+func (s *FolderServiceImpl) GetFolders(ctx context.Context, ownerID primitive.ObjectID) ([]dtos.FolderResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve folders")
+	folders, err := s.folderRepo.GetFoldersByOwnerID(ctx, ownerID)
+	if err != nil {
+		s.logger.Error("Service Level: Failed to retrieve folders", err)
+		return nil, errors.NewDatabaseError("Failed to retrieve folders", "get_folders_failed")
+	}
+	s.logger.Info("Service Level: Successfully retrieved folders")
+	return s.mapper.FoldersToDTO(folders), nil
+}
+
+// GetFolderByID retrieves one of the caller's folders.
+func (s *FolderServiceImpl) GetFolderByID(ctx context.Context, id, ownerID primitive.ObjectID) (*dtos.FolderResponse, error) {
+	s.logger.Info("Service Level: Attempting to retrieve folder")
+	folder, err := s.loadOwnedFolder(ctx, id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	response := s.mapper.FolderToDTO(folder)
+	s.logger.Info("Service Level: Successfully retrieved folder")
+	return &response, nil
+}
+
+// UpdateFolder renames a folder and/or moves it, with its subtree, under another
+// parent. A folder cannot be moved below itself.
+func (s *FolderServiceImpl) UpdateFolder(ctx context.Context, id, ownerID primitive.ObjectID, request dtos.UpdateFolderRequest) (*dtos.FolderResponse, error) {
+	s.logger.Info("Service Level: Attempting to update folder")
+	folder, err := s.loadOwnedFolder(ctx, id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if request.Name.Present {
+		name := strings.TrimSpace(request.Name.Value)
+		if name == "" {
+			return nil, errors.NewIncorrectInputError("A folder name is required", "folder_name_required")
+		}
+		if err := s.folderRepo.UpdateFolder(ctx, id, bson.M{"$set": bson.M{"name": name, "updated_at": now}}); err != nil {
+			s.logger.Error("Service Level: Failed to rename folder", err)
+			return nil, errors.NewDatabaseError("Failed to update folder", "update_folder_failed")
+		}
+		folder.Name = name
+	}
+	if request.ParentID.Present && request.ParentID.Value != folder.ParentID {
+		var parent *models.Folder
+		if !request.ParentID.Value.IsZero() {
+			target, err := s.loadOwnedFolder(ctx, request.ParentID.Value, ownerID)
+			if err != nil {
+				return nil, err
+			}
+			if target.ID == folder.ID || containsObjectID(target.Ancestors, folder.ID) {
+				return nil, errors.NewIncorrectInputError("A folder cannot be moved into itself", "folder_move_cycle")
+			}
+			parent = &target
+		}
+		if folder, err = s.folderRepo.MoveFolder(ctx, folder, parent); err != nil {
+			s.logger.Error("Service Level: Failed to move folder", err)
+			return nil, errors.NewDatabaseError("Failed to update folder", "update_folder_failed")
+		}
+		if err := s.folderRepo.UpdateFolder(ctx, id, bson.M{"$set": bson.M{"updated_at": now}}); err != nil {
+			s.logger.Error("Service Level: Failed to update folder", err)
+		}
+	}
+	folder.UpdatedAt = now
+	response := s.mapper.FolderToDTO(folder)
+	s.logger.Info("Service Level: Successfully updated folder")
+	return &response, nil
+}
+
+// DeleteFolder removes a folder. Its subfolders and cases move up to its parent.
+func (s *FolderServiceImpl) DeleteFolder(ctx context.Context, id, ownerID primitive.ObjectID) error {
+	s.logger.Info("Service Level: Attempting to delete folder")
+	folder, err := s.loadOwnedFolder(ctx, id, ownerID)
+	if err != nil {
+		return err
+	}
+	if err := s.folderRepo.DeleteFolder(ctx, folder); err != nil {
+		s.logger.Error("Service Level: Failed to delete folder", err)
+		return errors.NewDatabaseError("Failed to delete folder", "delete_folder_failed")
+	}
+	s.logger.Info("Service Level: Successfully deleted folder")
+	return nil
+}
+
+// loadOwnedFolder retrieves a folder, reporting folders of other users as missing.
+func (s *FolderServiceImpl) loadOwnedFolder(ctx context.Context, id, ownerID primitive.ObjectID) (models.Folder, error) {
+	folder, err := s.folderRepo.GetFolderByID(ctx, id)
+	if err != nil {
+		if stderrors.Is(err, daos.ErrFolderNotFound) {
+			return folder, errors.NewNotFoundError("Folder not found", "folder_not_found")
+		}
+		s.logger.Error("Service Level: Failed to retrieve folder", err)
+		return folder, errors.NewDatabaseError("Failed to retrieve folder", "get_folder_failed")
+	}
+	if folder.OwnerID != ownerID {
+		s.logger.Warn("Service Level: Folder belongs to another user")
+		return folder, errors.NewNotFoundError("Folder not found", "folder_not_found")
+	}
+	return folder, nil
+}
+
+func containsObjectID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
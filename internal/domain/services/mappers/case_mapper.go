@@ -2,9 +2,13 @@
 package mappers
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
 	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
@@ -14,16 +18,30 @@ import (
 )
 
 type CaseConversionService interface {
-	DTOToCase(caseRequest dtos.CreateCaseRequest) (*models.Case, error)
+	DTOToCase(caseRequest dtos.CreateCaseRequest, template *models.CaseTemplate) (*models.Case, error)
 	CaseToDTO(caseModel *models.Case) *dtos.CaseResponse
 	CasesToDTO(cases []models.Case) []dtos.CaseResponse
+	SharedCaseToDTO(caseModel *models.Case) *dtos.CaseResponse
 	UpdateCaseFieldsToMap(updateRequest dtos.UpdateCaseRequest) (map[string]interface{}, error)
 	CollaboratorsToDTO(collaborators []models.Collaborators) []dtos.CollaboratorResponse
 	DTOToCollaborators(collaboratorsDTO []dtos.CollaboratorResponse) ([]models.Collaborators, error)
 	MessageToDTO(message models.Message) dtos.MessageResponse
+	MessageHistoryToDTO(message models.Message) dtos.MessageResponse
 	DTOToMessage(messageDTO dtos.MessageResponse) (models.Message, error)
 	MessagesToDTO(messages []models.Message) []dtos.MessageResponse
+	ApplyReplyCounts(messages []dtos.MessageResponse, counts map[primitive.ObjectID]int) []dtos.MessageResponse
 	DTOToMessages(messagesDTO []dtos.MessageResponse) ([]models.Message, error)
+	CaseVersionToDTO(version models.CaseVersion, withSnapshot bool) dtos.CaseVersionResponse
+	CaseVersionsToDTO(versions []models.CaseVersion) []dtos.CaseVersionResponse
+	CaseInvitationToDTO(invitation models.CaseInvitation) dtos.CaseInvitationResponse
+	CaseInvitationsToDTO(invitations []models.CaseInvitation) []dtos.CaseInvitationResponse
+	CaseActivitiesToDTO(activities []models.CaseActivity) []dtos.CaseActivityResponse
+	AnnotationToDTO(annotation models.Annotation) dtos.AnnotationResponse
+	AnnotationsToDTO(annotations []models.Annotation) []dtos.AnnotationResponse
+	DocumentToDTO(document models.Document) dtos.DocumentResponse
+	DocumentsToDTO(documents []models.Document) []dtos.DocumentResponse
+	DocumentTextToDTO(document models.Document) dtos.DocumentTextResponse
+	ToolInvocationsToDTO(messages []models.Message) []dtos.ToolInvocationResponse
 }
 
 type CaseConversionServiceImpl struct {
@@ -36,7 +54,10 @@ func NewCaseConversionService(logger logs.Logger) *CaseConversionServiceImpl {
 	}
 }
 
-func (s *CaseConversionServiceImpl) DTOToCase(caseRequest dtos.CreateCaseRequest) (*models.Case, error) {
+// DTOToCase builds a new case from a create request. When a template is given it
+// supplies the name, agent, action, opening messages and tags; any of these set on
+// the request take precedence.
+func (s *CaseConversionServiceImpl) DTOToCase(caseRequest dtos.CreateCaseRequest, template *models.CaseTemplate) (*models.Case, error) {
 	s.logger.Info("Converting DTO to Case")
 
 	if !caseRequest.CreatorID.Present {
@@ -51,6 +72,19 @@ func (s *CaseConversionServiceImpl) DTOToCase(caseRequest dtos.CreateCaseRequest
 		return nil, fmt.Errorf("error converting messages: %w", err)
 	}
 
+	name, action, agentID, tags := "New Case", "summarize", primitive.NilObjectID, []string(nil)
+	if template != nil {
+		name = template.Name
+		if template.Action != "" {
+			action = template.Action
+		}
+		agentID = template.AgentID
+		tags = template.Tags
+		if !caseRequest.Messages.Present {
+			messages = templateMessages(template.Messages)
+		}
+	}
+
 	collaborators, err := s.DTOToCollaborators(caseRequest.Collaborators.OrElse(nil))
 	if err != nil {
 		s.logger.Error("Failed to convert collaborators", err)
@@ -60,22 +94,43 @@ func (s *CaseConversionServiceImpl) DTOToCase(caseRequest dtos.CreateCaseRequest
 	now := time.Now()
 	caseModel := &models.Case{
 		ID:            primitive.NewObjectID(),
-		Name:          caseRequest.Name.OrElse("New Case"),
+		Name:          caseRequest.Name.OrElse(name),
 		CreatorID:     caseRequest.CreatorID.Value,
 		Messages:      messages,
 		Collaborators: collaborators,
-		Action:        caseRequest.Action.OrElse("summarize"),
-		AgentID:       caseRequest.AgentID.OrElse(primitive.NilObjectID),
+		Action:        caseRequest.Action.OrElse(action),
+		AgentID:       caseRequest.AgentID.OrElse(agentID),
 		LastEdit:      caseRequest.LastEdit.OrElse(now),
 		CreationDate:  now,
 		Share:         caseRequest.Share.OrElse(false),
 		IsArchived:    caseRequest.IsArchived.OrElse(false),
+		Tags:          caseRequest.Tags.OrElse(tags),
 	}
 
 	s.logger.Info("Successfully converted DTO to Case")
 	return caseModel, nil
 }
 
+// templateMessages copies the opening messages of a template under new IDs.
+func templateMessages(source []models.Message) []models.Message {
+	now := time.Now()
+	messages := make([]models.Message, len(source))
+	for i, message := range source {
+		messages[i] = models.Message{
+			ID:           primitive.NewObjectID(),
+			Sender:       message.Sender,
+			Recipient:    message.Recipient,
+			Content:      message.Content,
+			DocumentPath: message.DocumentPath,
+			FunctionCall: message.FunctionCall,
+			ToolCalls:    message.ToolCalls,
+			ToolResult:   message.ToolResult,
+			CreatedAt:    now,
+		}
+	}
+	return messages
+}
+
 func (s *CaseConversionServiceImpl) CaseToDTO(caseModel *models.Case) *dtos.CaseResponse {
 	s.logger.Info("Converting Case to DTO")
 
@@ -88,7 +143,7 @@ func (s *CaseConversionServiceImpl) CaseToDTO(caseModel *models.Case) *dtos.Case
 		ID:            helpers.NewNullable(caseModel.ID),
 		Name:          helpers.NewNullable(caseModel.Name),
 		CreatorID:     helpers.NewNullable(caseModel.CreatorID),
-		Messages:      helpers.NewNullable(s.MessagesToDTO(caseModel.Messages)),
+		Messages:      helpers.NewNullable(s.ApplyReplyCounts(s.MessagesToDTO(caseModel.Messages), countReplies(caseModel.Messages))),
 		Collaborators: helpers.NewNullable(s.CollaboratorsToDTO(caseModel.Collaborators)),
 		Action:        helpers.NewNullable(caseModel.Action),
 		AgentID:       helpers.NewNullable(caseModel.AgentID),
@@ -96,12 +151,57 @@ func (s *CaseConversionServiceImpl) CaseToDTO(caseModel *models.Case) *dtos.Case
 		CreationDate:  helpers.NewNullable(caseModel.CreationDate),
 		Share:         helpers.NewNullable(caseModel.Share),
 		IsArchived:    helpers.NewNullable(caseModel.IsArchived),
+		DeletedAt:     helpers.NewNullable(caseModel.DeletedAt),
+		DeletedBy:     helpers.NewNullable(caseModel.DeletedBy),
+		FolderID:      helpers.NewNullable(caseModel.FolderID),
+		Tags:          helpers.NewNullable(caseModel.Tags),
+	}
+	if caseModel.ForkedFrom != nil {
+		dto.ForkedFrom = helpers.Nullable[dtos.CaseForkOriginResponse]{
+			Value: dtos.CaseForkOriginResponse{
+				CaseID:    helpers.NewNullable(caseModel.ForkedFrom.CaseID),
+				MessageID: helpers.NewNullable(caseModel.ForkedFrom.MessageID),
+				Position:  helpers.Nullable[int]{Value: caseModel.ForkedFrom.Position, Present: true},
+			},
+			Present: true,
+		}
 	}
 
 	s.logger.Info("Successfully converted Case to DTO")
 	return dto
 }
 
+// SharedCaseToDTO converts a case for a public share link. Only the conversation
+// and its name, agent and dates are kept: who created it, who collaborates on
+// it, how it is organized, who wrote each message, what each message cost and
+// which documents it refers to are left out.
+func (s *CaseConversionServiceImpl) SharedCaseToDTO(caseModel *models.Case) *dtos.CaseResponse {
+	s.logger.Info("Converting shared Case to DTO")
+
+	if caseModel == nil {
+		s.logger.Warn("Attempted to convert nil Case to DTO")
+		return nil
+	}
+
+	messages := s.ApplyReplyCounts(s.MessagesToDTO(caseModel.Messages), countReplies(caseModel.Messages))
+	for i := range messages {
+		messages[i].AuthorUserID = helpers.Nullable[primitive.ObjectID]{}
+		messages[i].Usage = helpers.Nullable[dtos.UsageResponse]{}
+		messages[i].DocumentID = helpers.Nullable[primitive.ObjectID]{}
+		messages[i].DocumentPath = helpers.Nullable[string]{}
+	}
+	return &dtos.CaseResponse{
+		ID:           helpers.NewNullable(caseModel.ID),
+		Name:         helpers.NewNullable(caseModel.Name),
+		Messages:     helpers.Nullable[[]dtos.MessageResponse]{Value: messages, Present: true},
+		Action:       helpers.NewNullable(caseModel.Action),
+		AgentID:      helpers.NewNullable(caseModel.AgentID),
+		LastEdit:     helpers.NewNullable(caseModel.LastEdit),
+		CreationDate: helpers.NewNullable(caseModel.CreationDate),
+		Share:        helpers.Nullable[bool]{Value: true, Present: true},
+	}
+}
+
 func (s *CaseConversionServiceImpl) CasesToDTO(cases []models.Case) []dtos.CaseResponse {
 	s.logger.Info("Converting multiple Cases to DTOs")
 
@@ -129,14 +229,6 @@ func (s *CaseConversionServiceImpl) UpdateCaseFieldsToMap(updateRequest dtos.Upd
 	if updateRequest.Name.Present {
 		updateFields["name"] = updateRequest.Name.Value
 	}
-	if updateRequest.Messages.Present {
-		messages, err := s.DTOToMessages(updateRequest.Messages.Value)
-		if err != nil {
-			s.logger.Error("Failed to convert messages for update", err)
-			return nil, fmt.Errorf("error converting messages: %w", err)
-		}
-		updateFields["messages"] = messages
-	}
 	if updateRequest.Collaborators.Present {
 		collaborators, err := s.DTOToCollaborators(updateRequest.Collaborators.Value)
 		if err != nil {
@@ -157,6 +249,12 @@ func (s *CaseConversionServiceImpl) UpdateCaseFieldsToMap(updateRequest dtos.Upd
 	if updateRequest.IsArchived.Present {
 		updateFields["is_archived"] = updateRequest.IsArchived.Value
 	}
+	if updateRequest.FolderID.Present {
+		updateFields["folder_id"] = updateRequest.FolderID.Value
+	}
+	if updateRequest.Tags.Present {
+		updateFields["tags"] = updateRequest.Tags.Value
+	}
 
 	updateFields["last_edit"] = time.Now()
 
@@ -174,9 +272,11 @@ func (s *CaseConversionServiceImpl) CollaboratorsToDTO(collaborators []models.Co
 
 	collaboratorDTOs := make([]dtos.CollaboratorResponse, 0, len(collaborators))
 	for _, collaborator := range collaborators {
+		role := collaborator.EffectiveRole()
 		collaboratorDTOs = append(collaboratorDTOs, dtos.CollaboratorResponse{
 			ID:   helpers.NewNullable(collaborator.ID),
-			Edit: helpers.NewNullable(collaborator.Edit),
+			Role: helpers.NewNullable(role),
+			Edit: helpers.Nullable[bool]{Value: models.CaseRoleAllows(role, models.CaseRoleEditor), Present: true},
 		})
 	}
 
@@ -199,10 +299,18 @@ func (s *CaseConversionServiceImpl) DTOToCollaborators(collaboratorsDTO []dtos.C
 			s.logger.Error("Failed to convert DTO to Collaborator: collaborator ID is required", err)
 			return nil, err
 		}
-		collaborators = append(collaborators, models.Collaborators{
-			ID:   dto.ID.Value,
-			Edit: dto.Edit.OrElse(false),
-		})
+		collaborator := models.Collaborators{ID: dto.ID.Value, Edit: dto.Edit.OrElse(false)}
+		if dto.Role.Present {
+			if !models.ValidCaseRole(dto.Role.Value) {
+				err := fmt.Errorf("invalid collaborator role %q", dto.Role.Value)
+				s.logger.Error("Failed to convert DTO to Collaborator: invalid role", err)
+				return nil, err
+			}
+			collaborator.Role = dto.Role.Value
+		}
+		collaborator.Role = collaborator.EffectiveRole()
+		collaborator.Edit = models.CaseRoleAllows(collaborator.Role, models.CaseRoleEditor)
+		collaborators = append(collaborators, collaborator)
 	}
 
 	s.logger.Info("Successfully converted DTOs to Collaborators")
@@ -213,40 +321,299 @@ func (s *CaseConversionServiceImpl) MessageToDTO(message models.Message) dtos.Me
 	s.logger.Info("Converting Message to DTO")
 
 	dto := dtos.MessageResponse{
-		Content:      helpers.NewNullable(message.Content),
-		Sender:       helpers.NewNullable(message.Sender),
-		Recipient:    helpers.NewNullable(message.Recipient),
-		FunctionCall: helpers.NewNullable(message.FunctionCall),
-		DocumentPath: helpers.NewNullable(message.DocumentPath),
+		ID:              helpers.NewNullable(message.ID),
+		Content:         helpers.NewNullable(message.Content),
+		Sender:          helpers.NewNullable(message.Sender),
+		Recipient:       helpers.NewNullable(message.Recipient),
+		FunctionCall:    helpers.NewNullable(message.FunctionCall),
+		ToolCalls:       toolCallsToDTO(message.ToolCalls),
+		ToolResult:      toolResultToDTO(message.ToolResult),
+		Usage:           usageToDTO(message.Usage),
+		DocumentPath:    helpers.NewNullable(message.DocumentPath),
+		DocumentID:      helpers.NewNullable(message.DocumentID),
+		AuthorUserID:    helpers.NewNullable(message.AuthorUserID),
+		CreatedAt:       helpers.NewNullable(message.CreatedAt),
+		EditedAt:        helpers.NewNullable(message.EditedAt),
+		DeletedAt:       helpers.NewNullable(message.DeletedAt),
+		Position:        helpers.Nullable[int]{Value: message.Sequence, Present: true},
+		ParentMessageID: helpers.NewNullable(message.ParentMessageID),
 	}
 
 	s.logger.Info("Successfully converted Message to DTO")
 	return dto
 }
 
+// ApplyReplyCounts sets the reply count of every top-level message, zero included.
+func (s *CaseConversionServiceImpl) ApplyReplyCounts(messages []dtos.MessageResponse, counts map[primitive.ObjectID]int) []dtos.MessageResponse {
+	for i := range messages {
+		if !messages[i].ParentMessageID.Present {
+			messages[i].ReplyCount = helpers.Nullable[int]{Value: counts[messages[i].ID.Value], Present: true}
+		}
+	}
+	return messages
+}
+
+// countReplies counts the replies to each message among a complete set of messages.
+func countReplies(messages []models.Message) map[primitive.ObjectID]int {
+	counts := make(map[primitive.ObjectID]int)
+	for _, message := range messages {
+		if message.IsReply() {
+			counts[message.ParentMessageID]++
+		}
+	}
+	return counts
+}
+
+// MessageHistoryToDTO converts a message together with its edit and delete history.
+func (s *CaseConversionServiceImpl) MessageHistoryToDTO(message models.Message) dtos.MessageResponse {
+	dto := s.MessageToDTO(message)
+	history := make([]dtos.MessageRevisionResponse, 0, len(message.History))
+	for _, revision := range message.History {
+		history = append(history, dtos.MessageRevisionResponse{
+			Action:       helpers.NewNullable(revision.Action),
+			Content:      helpers.NewNullable(revision.Content),
+			DocumentPath: helpers.NewNullable(revision.DocumentPath),
+			ChangedAt:    helpers.NewNullable(revision.ChangedAt),
+			ChangedBy:    helpers.NewNullable(revision.ChangedBy),
+		})
+	}
+	dto.History = helpers.Nullable[[]dtos.MessageRevisionResponse]{Value: history, Present: true}
+	return dto
+}
+
 func (s *CaseConversionServiceImpl) DTOToMessage(messageDTO dtos.MessageResponse) (models.Message, error) {
 	s.logger.Info("Converting DTO to Message")
 
 	s.logger.Info(messageDTO.Content.String() + messageDTO.Recipient.String() + messageDTO.Sender.String())
 
-	if !messageDTO.Content.Present || !messageDTO.Sender.Present || !messageDTO.Recipient.Present {
+	// Messages that only ask for tool calls may leave the content out.
+	if (!messageDTO.Content.Present && len(messageDTO.ToolCalls.Value) == 0) || !messageDTO.Sender.Present || !messageDTO.Recipient.Present {
 		err := errors.New("content, sender, and recipient are required")
 		s.logger.Error("Failed to convert DTO to Message: content, sender, and recipient are required", err)
 		return models.Message{}, err
 	}
+	toolCalls, err := dtoToToolCalls(messageDTO.ToolCalls.Value)
+	if err != nil {
+		s.logger.Error("Failed to convert DTO to Message: invalid tool calls", err)
+		return models.Message{}, err
+	}
+	toolResult, err := dtoToToolResult(messageDTO.ToolResult)
+	if err != nil {
+		s.logger.Error("Failed to convert DTO to Message: invalid tool result", err)
+		return models.Message{}, err
+	}
+	usage, err := dtoToUsage(messageDTO.Usage)
+	if err != nil {
+		s.logger.Error("Failed to convert DTO to Message: invalid usage", err)
+		return models.Message{}, err
+	}
 
 	message := models.Message{
-		Content:      messageDTO.Content.Value,
-		Sender:       messageDTO.Sender.Value,
-		Recipient:    messageDTO.Recipient.Value,
-		FunctionCall: messageDTO.FunctionCall.OrElse(false),
-		DocumentPath: messageDTO.DocumentPath.OrElse(""),
+		ID:              messageDTO.ID.OrElse(primitive.NewObjectID()),
+		Content:         messageDTO.Content.Value,
+		Sender:          messageDTO.Sender.Value,
+		Recipient:       messageDTO.Recipient.Value,
+		FunctionCall:    messageDTO.FunctionCall.OrElse(false) || len(toolCalls) > 0 || toolResult != nil,
+		ToolCalls:       toolCalls,
+		ToolResult:      toolResult,
+		Usage:           usage,
+		DocumentPath:    messageDTO.DocumentPath.OrElse(""),
+		DocumentID:      messageDTO.DocumentID.Value,
+		CreatedAt:       messageDTO.CreatedAt.OrElse(time.Now()),
+		ParentMessageID: messageDTO.ParentMessageID.Value,
 	}
 
 	s.logger.Info("Successfully converted DTO to Message")
 	return message, nil
 }
 
+// toolCallsToDTO converts the tool calls of a message; messages without calls
+// leave the field absent.
+func toolCallsToDTO(calls []models.ToolCall) helpers.Nullable[[]dtos.ToolCallResponse] {
+	if len(calls) == 0 {
+		return helpers.Nullable[[]dtos.ToolCallResponse]{}
+	}
+	callDTOs := make([]dtos.ToolCallResponse, len(calls))
+	for i, call := range calls {
+		callDTOs[i] = toolCallToDTO(call)
+	}
+	return helpers.NewNullable(callDTOs)
+}
+
+func toolCallToDTO(call models.ToolCall) dtos.ToolCallResponse {
+	return dtos.ToolCallResponse{
+		CallID:    helpers.NewNullable(call.CallID),
+		Name:      helpers.NewNullable(call.Name),
+		Arguments: helpers.NewNullable(json.RawMessage(call.Arguments)),
+	}
+}
+
+func toolResultToDTO(result *models.ToolResult) helpers.Nullable[dtos.ToolResultResponse] {
+	if result == nil {
+		return helpers.Nullable[dtos.ToolResultResponse]{}
+	}
+	resultDTO := dtos.ToolResultResponse{
+		CallID: helpers.NewNullable(result.CallID),
+		Name:   helpers.NewNullable(result.Name),
+		Status: helpers.NewNullable(result.Status),
+	}
+	if result.DurationMS != nil {
+		resultDTO.DurationMS = helpers.Nullable[int64]{Value: *result.DurationMS, Present: true}
+	}
+	return helpers.NewNullable(resultDTO)
+}
+
+// ToolInvocationsToDTO pairs the tool calls among messages, in conversation
+// order, with their results. Results are matched by call ID; calls without one,
+// as made by older function-calling agents, take the next result for the same
+// function name. Results that match no call are listed on their own.
+func (s *CaseConversionServiceImpl) ToolInvocationsToDTO(messages []models.Message) []dtos.ToolInvocationResponse {
+	invocations := []dtos.ToolInvocationResponse{}
+	byCallID := make(map[string]int)
+	unnamed := make(map[string][]int)
+	for _, message := range messages {
+		for _, call := range message.ToolCalls {
+			if call.CallID != "" {
+				byCallID[call.CallID] = len(invocations)
+			} else {
+				unnamed[call.Name] = append(unnamed[call.Name], len(invocations))
+			}
+			callDTO := toolCallToDTO(call)
+			invocations = append(invocations, dtos.ToolInvocationResponse{
+				CallID:        callDTO.CallID,
+				Name:          callDTO.Name,
+				Arguments:     callDTO.Arguments,
+				Caller:        helpers.NewNullable(message.Sender),
+				CallMessageID: helpers.NewNullable(message.ID),
+				CallPosition:  helpers.Nullable[int]{Value: message.Sequence, Present: true},
+				CalledAt:      helpers.NewNullable(message.CreatedAt),
+				Status:        helpers.NewNullable(models.ToolStatusPending),
+			})
+		}
+		if message.ToolResult == nil {
+			continue
+		}
+		result := message.ToolResult
+		index := -1
+		if i, ok := byCallID[result.CallID]; ok && result.CallID != "" {
+			index = i
+			delete(byCallID, result.CallID)
+		} else if pending := unnamed[result.Name]; result.CallID == "" && len(pending) > 0 {
+			index = pending[0]
+			unnamed[result.Name] = pending[1:]
+		}
+		if index < 0 {
+			invocations = append(invocations, dtos.ToolInvocationResponse{
+				CallID: helpers.NewNullable(result.CallID),
+				Name:   helpers.NewNullable(result.Name),
+			})
+			index = len(invocations) - 1
+		}
+		invocation := &invocations[index]
+		if !invocation.Name.Present {
+			invocation.Name = helpers.NewNullable(result.Name)
+		}
+		invocation.Status = helpers.NewNullable(result.Status)
+		if result.DurationMS != nil {
+			invocation.DurationMS = helpers.Nullable[int64]{Value: *result.DurationMS, Present: true}
+		}
+		invocation.Output = helpers.Nullable[string]{Value: message.Content, Present: true}
+		invocation.ResultMessageID = helpers.NewNullable(message.ID)
+		invocation.ResultPosition = helpers.Nullable[int]{Value: message.Sequence, Present: true}
+	}
+	return invocations
+}
+
+// dtoToToolCalls validates the tool calls of a message. Every call needs a name
+// and call IDs must be unique within the message; arguments default to an empty
+// object and are stored compacted.
+func dtoToToolCalls(callDTOs []dtos.ToolCallResponse) ([]models.ToolCall, error) {
+	if len(callDTOs) == 0 {
+		return nil, nil
+	}
+	calls := make([]models.ToolCall, 0, len(callDTOs))
+	callIDs := make(map[string]bool, len(callDTOs))
+	for _, dto := range callDTOs {
+		name := strings.TrimSpace(dto.Name.Value)
+		if name == "" {
+			return nil, errors.New("tool calls need a name")
+		}
+		callID := strings.TrimSpace(dto.CallID.Value)
+		if callID != "" {
+			if callIDs[callID] {
+				return nil, fmt.Errorf("duplicate tool call ID %q", callID)
+			}
+			callIDs[callID] = true
+		}
+		arguments := "{}"
+		if len(dto.Arguments.Value) > 0 {
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, dto.Arguments.Value); err != nil {
+				return nil, fmt.Errorf("arguments of tool call %q are not valid JSON", name)
+			}
+			arguments = compact.String()
+		}
+		calls = append(calls, models.ToolCall{CallID: callID, Name: name, Arguments: arguments})
+	}
+	return calls, nil
+}
+
+// dtoToToolResult validates the tool result of a message. A result must name the
+// call it answers, by call ID or by function name; the status defaults to success.
+func dtoToToolResult(resultDTO helpers.Nullable[dtos.ToolResultResponse]) (*models.ToolResult, error) {
+	if !resultDTO.Present {
+		return nil, nil
+	}
+	result := &models.ToolResult{
+		CallID: strings.TrimSpace(resultDTO.Value.CallID.Value),
+		Name:   strings.TrimSpace(resultDTO.Value.Name.Value),
+		Status: resultDTO.Value.Status.OrElse(models.ToolStatusSuccess),
+	}
+	if result.CallID == "" && result.Name == "" {
+		return nil, errors.New("tool results need a call ID or a name")
+	}
+	if !models.ValidToolResultStatus(result.Status) {
+		return nil, fmt.Errorf("invalid tool result status %q", result.Status)
+	}
+	if resultDTO.Value.DurationMS.Present {
+		duration := resultDTO.Value.DurationMS.Value
+		if duration < 0 {
+			return nil, errors.New("tool result duration must not be negative")
+		}
+		result.DurationMS = &duration
+	}
+	return result, nil
+}
+
+func usageToDTO(usage *models.TokenUsage) helpers.Nullable[dtos.UsageResponse] {
+	if usage == nil {
+		return helpers.Nullable[dtos.UsageResponse]{}
+	}
+	return helpers.NewNullable(dtos.UsageResponse{
+		Model:            helpers.NewNullable(usage.Model),
+		PromptTokens:     helpers.Nullable[int64]{Value: usage.PromptTokens, Present: true},
+		CompletionTokens: helpers.Nullable[int64]{Value: usage.CompletionTokens, Present: true},
+		Cost:             helpers.Nullable[float64]{Value: usage.Cost, Present: true},
+	})
+}
+
+// dtoToUsage validates the token usage of a message. Token counts must not be
+// negative; the cost is left to the service, which prices the usage.
+func dtoToUsage(usageDTO helpers.Nullable[dtos.UsageResponse]) (*models.TokenUsage, error) {
+	if !usageDTO.Present {
+		return nil, nil
+	}
+	usage := &models.TokenUsage{
+		Model:            strings.TrimSpace(usageDTO.Value.Model.Value),
+		PromptTokens:     usageDTO.Value.PromptTokens.Value,
+		CompletionTokens: usageDTO.Value.CompletionTokens.Value,
+	}
+	if usage.PromptTokens < 0 || usage.CompletionTokens < 0 {
+		return nil, errors.New("token counts must not be negative")
+	}
+	return usage, nil
+}
+
 func (s *CaseConversionServiceImpl) MessagesToDTO(messages []models.Message) []dtos.MessageResponse {
 	s.logger.Info("Converting multiple Messages to DTOs")
 
@@ -285,3 +652,138 @@ func (s *CaseConversionServiceImpl) DTOToMessages(messagesDTO []dtos.MessageResp
 	s.logger.Info("Successfully converted DTOs to Messages")
 	return messages, nil
 }
+
+func (s *CaseConversionServiceImpl) CaseVersionToDTO(version models.CaseVersion, withSnapshot bool) dtos.CaseVersionResponse {
+	s.logger.Info("Converting CaseVersion to DTO")
+
+	dto := dtos.CaseVersionResponse{
+		Version:      helpers.Nullable[int]{Value: version.Version, Present: true},
+		Action:       helpers.NewNullable(version.Action),
+		ActorID:      helpers.NewNullable(version.ActorID),
+		CreatedAt:    helpers.NewNullable(version.CreatedAt),
+		Name:         helpers.NewNullable(version.Snapshot.Name),
+		MessageCount: helpers.Nullable[int]{Value: version.MessageCount, Present: true},
+	}
+	if withSnapshot {
+		dto.Snapshot = s.CaseToDTO(&version.Snapshot)
+	}
+
+	s.logger.Info("Successfully converted CaseVersion to DTO")
+	return dto
+}
+
+func (s *CaseConversionServiceImpl) CaseVersionsToDTO(versions []models.CaseVersion) []dtos.CaseVersionResponse {
+	s.logger.Info("Converting multiple CaseVersions to DTOs")
+
+	versionDTOs := make([]dtos.CaseVersionResponse, 0, len(versions))
+	for _, version := range versions {
+		versionDTOs = append(versionDTOs, s.CaseVersionToDTO(version, false))
+	}
+
+	s.logger.Info("Successfully converted multiple CaseVersions to DTOs")
+	return versionDTOs
+}
+
+func (s *CaseConversionServiceImpl) CaseInvitationToDTO(invitation models.CaseInvitation) dtos.CaseInvitationResponse {
+	return dtos.CaseInvitationResponse{
+		ID:         helpers.NewNullable(invitation.ID),
+		CaseID:     helpers.NewNullable(invitation.CaseID),
+		Email:      helpers.NewNullable(invitation.Email),
+		Role:       helpers.NewNullable(invitation.Role),
+		InvitedBy:  helpers.NewNullable(invitation.InvitedBy),
+		CreatedAt:  helpers.NewNullable(invitation.CreatedAt),
+		ExpiresAt:  helpers.NewNullable(invitation.ExpiresAt),
+		AcceptedAt: helpers.NewNullable(invitation.AcceptedAt),
+		AcceptedBy: helpers.NewNullable(invitation.AcceptedBy),
+	}
+}
+
+func (s *CaseConversionServiceImpl) CaseInvitationsToDTO(invitations []models.CaseInvitation) []dtos.CaseInvitationResponse {
+	s.logger.Info("Converting multiple CaseInvitations to DTOs")
+	invitationDTOs := make([]dtos.CaseInvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		invitationDTOs[i] = s.CaseInvitationToDTO(invitation)
+	}
+	return invitationDTOs
+}
+
+func (s *CaseConversionServiceImpl) CaseActivityToDTO(activity models.CaseActivity) dtos.CaseActivityResponse {
+	return dtos.CaseActivityResponse{
+		ID:        helpers.NewNullable(activity.ID),
+		ActorID:   helpers.NewNullable(activity.ActorID),
+		Action:    helpers.NewNullable(activity.Action),
+		Fields:    helpers.NewNullable(activity.Fields),
+		CreatedAt: helpers.NewNullable(activity.CreatedAt),
+	}
+}
+
+func (s *CaseConversionServiceImpl) CaseActivitiesToDTO(activities []models.CaseActivity) []dtos.CaseActivityResponse {
+	s.logger.Info("Converting multiple CaseActivities to DTOs")
+	activityDTOs := make([]dtos.CaseActivityResponse, len(activities))
+	for i, activity := range activities {
+		activityDTOs[i] = s.CaseActivityToDTO(activity)
+	}
+	return activityDTOs
+}
+
+func (s *CaseConversionServiceImpl) AnnotationToDTO(annotation models.Annotation) dtos.AnnotationResponse {
+	response := dtos.AnnotationResponse{
+		ID:              helpers.NewNullable(annotation.ID),
+		CaseID:          helpers.NewNullable(annotation.CaseID),
+		MessageID:       helpers.NewNullable(annotation.MessageID),
+		MessagePosition: helpers.Nullable[int]{Value: annotation.MessageSequence, Present: true},
+		AuthorID:        helpers.NewNullable(annotation.AuthorID),
+		Quote:           helpers.NewNullable(annotation.Quote),
+		Note:            helpers.NewNullable(annotation.Note),
+		Visibility:      helpers.NewNullable(annotation.Visibility),
+		CreatedAt:       helpers.NewNullable(annotation.CreatedAt),
+		UpdatedAt:       helpers.NewNullable(annotation.UpdatedAt),
+	}
+	if annotation.Range != nil {
+		response.Range = helpers.NewNullable(dtos.TextRange{Start: annotation.Range.Start, End: annotation.Range.End})
+	}
+	return response
+}
+
+func (s *CaseConversionServiceImpl) AnnotationsToDTO(annotations []models.Annotation) []dtos.AnnotationResponse {
+	s.logger.Info("Converting multiple Annotations to DTOs")
+	annotationDTOs := make([]dtos.AnnotationResponse, len(annotations))
+	for i, annotation := range annotations {
+		annotationDTOs[i] = s.AnnotationToDTO(annotation)
+	}
+	return annotationDTOs
+}
+
+func (s *CaseConversionServiceImpl) DocumentToDTO(document models.Document) dtos.DocumentResponse {
+	return dtos.DocumentResponse{
+		ID:          helpers.NewNullable(document.ID),
+		CaseID:      helpers.NewNullable(document.CaseID),
+		UploaderID:  helpers.NewNullable(document.UploaderID),
+		FileName:    helpers.NewNullable(document.FileName),
+		ContentType: helpers.NewNullable(document.ContentType),
+		Size:        helpers.Nullable[int64]{Value: document.Size, Present: true},
+		SHA256:      helpers.NewNullable(document.SHA256),
+		Path:        helpers.NewNullable(models.DocumentPath(document.ID)),
+		TextStatus:  helpers.NewNullable(document.TextStatus),
+		CreatedAt:   helpers.NewNullable(document.CreatedAt),
+	}
+}
+
+func (s *CaseConversionServiceImpl) DocumentsToDTO(documents []models.Document) []dtos.DocumentResponse {
+	s.logger.Info("Converting multiple Documents to DTOs")
+	documentDTOs := make([]dtos.DocumentResponse, len(documents))
+	for i, document := range documents {
+		documentDTOs[i] = s.DocumentToDTO(document)
+	}
+	return documentDTOs
+}
+
+func (s *CaseConversionServiceImpl) DocumentTextToDTO(document models.Document) dtos.DocumentTextResponse {
+	return dtos.DocumentTextResponse{
+		DocumentID:  helpers.NewNullable(document.ID),
+		FileName:    helpers.NewNullable(document.FileName),
+		ContentType: helpers.NewNullable(document.ContentType),
+		Length:      helpers.Nullable[int]{Value: utf8.RuneCountInString(document.Text), Present: true},
+		Text:        helpers.Nullable[string]{Value: document.Text, Present: true},
+	}
+}
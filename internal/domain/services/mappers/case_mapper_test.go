@@ -0,0 +1,180 @@
+package mappers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...zap.Field)        {}
+func (nopLogger) Info(string, ...zap.Field)         {}
+func (nopLogger) Warn(string, ...zap.Field)         {}
+func (nopLogger) Error(string, error, ...zap.Field) {}
+func (nopLogger) Sync()                             {}
+
+// invocation is the part of a tool invocation the pairing decides, with
+// messages referred to by their index; -1 stands for no message.
+type invocation struct {
+	callID, name, status string
+	call, result         int
+}
+
+func call(callID, name string) models.Message {
+	return models.Message{Sender: "agent", ToolCalls: []models.ToolCall{{CallID: callID, Name: name, Arguments: "{}"}}}
+}
+
+func result(callID, name, status string) models.Message {
+	return models.Message{Sender: "tool", Content: name + " output", ToolResult: &models.ToolResult{CallID: callID, Name: name, Status: status}}
+}
+
+func TestToolInvocationsToDTO(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []models.Message
+		want     []invocation
+	}{
+		{
+			name: "results paired by call ID whatever their order",
+			messages: []models.Message{
+				{Sender: "agent", ToolCalls: []models.ToolCall{{CallID: "a", Name: "search"}, {CallID: "b", Name: "fetch"}}},
+				result("b", "fetch", models.ToolStatusError),
+				result("a", "search", models.ToolStatusSuccess),
+			},
+			want: []invocation{
+				{callID: "a", name: "search", status: models.ToolStatusSuccess, call: 0, result: 2},
+				{callID: "b", name: "fetch", status: models.ToolStatusError, call: 0, result: 1},
+			},
+		},
+		{
+			name: "call without a result is pending",
+			messages: []models.Message{
+				call("a", "search"),
+				{Sender: "agent", Content: "thinking"},
+			},
+			want: []invocation{
+				{callID: "a", name: "search", status: models.ToolStatusPending, call: 0, result: -1},
+			},
+		},
+		{
+			name: "calls without IDs take results of the same name in order",
+			messages: []models.Message{
+				call("", "search"),
+				call("", "fetch"),
+				call("", "search"),
+				result("", "search", models.ToolStatusSuccess),
+				result("", "search", models.ToolStatusError),
+				result("", "fetch", models.ToolStatusSuccess),
+			},
+			want: []invocation{
+				{name: "search", status: models.ToolStatusSuccess, call: 0, result: 3},
+				{name: "fetch", status: models.ToolStatusSuccess, call: 1, result: 5},
+				{name: "search", status: models.ToolStatusError, call: 2, result: 4},
+			},
+		},
+		{
+			name: "result matching no call is listed on its own",
+			messages: []models.Message{
+				result("x", "search", models.ToolStatusSuccess),
+			},
+			want: []invocation{
+				{callID: "x", name: "search", status: models.ToolStatusSuccess, call: -1, result: 0},
+			},
+		},
+		{
+			name: "result with an unknown call ID does not take an unnamed call",
+			messages: []models.Message{
+				call("", "search"),
+				result("x", "search", models.ToolStatusSuccess),
+			},
+			want: []invocation{
+				{name: "search", status: models.ToolStatusPending, call: 0, result: -1},
+				{callID: "x", name: "search", status: models.ToolStatusSuccess, call: -1, result: 1},
+			},
+		},
+		{
+			name: "second result for a call is listed on its own",
+			messages: []models.Message{
+				call("a", "search"),
+				result("a", "search", models.ToolStatusError),
+				result("a", "search", models.ToolStatusSuccess),
+			},
+			want: []invocation{
+				{callID: "a", name: "search", status: models.ToolStatusError, call: 0, result: 1},
+				{callID: "a", name: "search", status: models.ToolStatusSuccess, call: -1, result: 2},
+			},
+		},
+		{
+			name:     "no tool messages",
+			messages: []models.Message{{Sender: "user", Content: "hello"}},
+			want:     []invocation{},
+		},
+	}
+
+	mapper := NewCaseConversionService(nopLogger{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index := make(map[primitive.ObjectID]int, len(tt.messages))
+			for i := range tt.messages {
+				tt.messages[i].ID = primitive.NewObjectID()
+				tt.messages[i].Sequence = i
+				index[tt.messages[i].ID] = i
+			}
+			messageIndex := func(id primitive.ObjectID, present bool) int {
+				if !present {
+					return -1
+				}
+				return index[id]
+			}
+
+			got := []invocation{}
+			for _, dto := range mapper.ToolInvocationsToDTO(tt.messages) {
+				got = append(got, invocation{
+					callID: dto.CallID.Value,
+					name:   dto.Name.Value,
+					status: dto.Status.Value,
+					call:   messageIndex(dto.CallMessageID.Value, dto.CallMessageID.Present),
+					result: messageIndex(dto.ResultMessageID.Value, dto.ResultMessageID.Present),
+				})
+				if dto.ResultMessageID.Present && dto.Output.Value != tt.messages[index[dto.ResultMessageID.Value]].Content {
+					t.Errorf("output of %q = %q, want the result message content", dto.Name.Value, dto.Output.Value)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToolInvocationsToDTO() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSharedCaseToDTO(t *testing.T) {
+	caseModel := &models.Case{
+		ID:   primitive.NewObjectID(),
+		Name: "Lease dispute",
+		Messages: []models.Message{{
+			ID:           primitive.NewObjectID(),
+			Sender:       "agent",
+			Content:      "See the attached lease.",
+			Usage:        &models.TokenUsage{Model: "gpt-4o", PromptTokens: 10, CompletionTokens: 5, Cost: 0.0001},
+			DocumentID:   primitive.NewObjectID(),
+			DocumentPath: "lease.pdf",
+			AuthorUserID: primitive.NewObjectID(),
+		}},
+	}
+
+	dto := NewCaseConversionService(nopLogger{}).SharedCaseToDTO(caseModel)
+	if len(dto.Messages.Value) != 1 {
+		t.Fatalf("messages = %d, want 1", len(dto.Messages.Value))
+	}
+	message := dto.Messages.Value[0]
+	if message.Content.Value != "See the attached lease." {
+		t.Errorf("content = %q, want the message content", message.Content.Value)
+	}
+	if message.Usage.Present || message.DocumentID.Present || message.DocumentPath.Present || message.AuthorUserID.Present {
+		t.Errorf("shared message = %+v, want no usage, document or author", message)
+	}
+}
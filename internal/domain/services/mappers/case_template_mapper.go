@@ -0,0 +1,84 @@
+package mappers
+
+import (
+	"fmt"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+)
+
+type CaseTemplateConversionService interface {
+	TemplateToDTO(template models.CaseTemplate) dtos.CaseTemplateResponse
+	TemplatesToDTO(templates []models.CaseTemplate) []dtos.CaseTemplateResponse
+	DTOToTemplateMessages(messagesDTO []dtos.MessageResponse) ([]models.Message, error)
+}
+
+type CaseTemplateConversionServiceImpl struct {
+	caseMapper *CaseConversionServiceImpl
+	logger     logs.Logger
+}
+
+func NewCaseTemplateConversionService(caseMapper *CaseConversionServiceImpl, logger logs.Logger) *CaseTemplateConversionServiceImpl {
+	return &CaseTemplateConversionServiceImpl{
+		caseMapper: caseMapper,
+		logger:     logger,
+	}
+}
+
+func (s *CaseTemplateConversionServiceImpl) TemplateToDTO(template models.CaseTemplate) dtos.CaseTemplateResponse {
+	s.logger.Info("Converting CaseTemplate to DTO")
+	tags := template.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	messages := s.caseMapper.MessagesToDTO(template.Messages)
+	for i := range messages {
+		messages[i].Position = helpers.Nullable[int]{Value: i, Present: true}
+	}
+	return dtos.CaseTemplateResponse{
+		ID:        helpers.NewNullable(template.ID),
+		Name:      helpers.NewNullable(template.Name),
+		CreatorID: helpers.NewNullable(template.CreatorID),
+		TeamID:    helpers.NewNullable(template.TeamID),
+		AgentID:   helpers.NewNullable(template.AgentID),
+		Action:    helpers.NewNullable(template.Action),
+		Messages:  helpers.Nullable[[]dtos.MessageResponse]{Value: messages, Present: true},
+		Tags:      helpers.Nullable[[]string]{Value: tags, Present: true},
+		CreatedAt: helpers.NewNullable(template.CreatedAt),
+		UpdatedAt: helpers.NewNullable(template.UpdatedAt),
+	}
+}
+
+func (s *CaseTemplateConversionServiceImpl) TemplatesToDTO(templates []models.CaseTemplate) []dtos.CaseTemplateResponse {
+	s.logger.Info("Converting multiple CaseTemplates to DTOs")
+	templateDTOs := make([]dtos.CaseTemplateResponse, len(templates))
+	for i, template := range templates {
+		templateDTOs[i] = s.TemplateToDTO(template)
+	}
+	return templateDTOs
+}
+
+// DTOToTemplateMessages converts the opening messages of a template. Only the
+// message content is kept; IDs and timestamps are assigned when a case is created.
+func (s *CaseTemplateConversionServiceImpl) DTOToTemplateMessages(messagesDTO []dtos.MessageResponse) ([]models.Message, error) {
+	s.logger.Info("Converting DTOs to template Messages")
+	messages := make([]models.Message, 0, len(messagesDTO))
+	for _, dto := range messagesDTO {
+		message, err := s.caseMapper.DTOToMessage(dto)
+		if err != nil {
+			return nil, fmt.Errorf("error converting message: %w", err)
+		}
+		messages = append(messages, models.Message{
+			Sender:       message.Sender,
+			Recipient:    message.Recipient,
+			Content:      message.Content,
+			DocumentPath: message.DocumentPath,
+			FunctionCall: message.FunctionCall,
+			ToolCalls:    message.ToolCalls,
+			ToolResult:   message.ToolResult,
+		})
+	}
+	return messages, nil
+}
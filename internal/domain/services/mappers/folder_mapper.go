@@ -0,0 +1,49 @@
+package mappers
+
+import (
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FolderConversionService interface {
+	FolderToDTO(folder models.Folder) dtos.FolderResponse
+	FoldersToDTO(folders []models.Folder) []dtos.FolderResponse
+}
+
+type FolderConversionServiceImpl struct {
+	logger logs.Logger
+}
+
+func NewFolderConversionService(logger logs.Logger) *FolderConversionServiceImpl {
+	return &FolderConversionServiceImpl{
+		logger: logger,
+	}
+}
+
+func (s *FolderConversionServiceImpl) FolderToDTO(folder models.Folder) dtos.FolderResponse {
+	ancestors := folder.Ancestors
+	if ancestors == nil {
+		ancestors = []primitive.ObjectID{}
+	}
+	return dtos.FolderResponse{
+		ID:        helpers.NewNullable(folder.ID),
+		OwnerID:   helpers.NewNullable(folder.OwnerID),
+		Name:      helpers.NewNullable(folder.Name),
+		ParentID:  helpers.NewNullable(folder.ParentID),
+		Ancestors: helpers.Nullable[[]primitive.ObjectID]{Value: ancestors, Present: true},
+		CreatedAt: helpers.NewNullable(folder.CreatedAt),
+		UpdatedAt: helpers.NewNullable(folder.UpdatedAt),
+	}
+}
+
+func (s *FolderConversionServiceImpl) FoldersToDTO(folders []models.Folder) []dtos.FolderResponse {
+	s.logger.Info("Converting multiple Folders to DTOs")
+	folderDTOs := make([]dtos.FolderResponse, len(folders))
+	for i, folder := range folders {
+		folderDTOs[i] = s.FolderToDTO(folder)
+	}
+	return folderDTOs
+}
@@ -0,0 +1,53 @@
+package mappers
+
+import (
+	"time"
+
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/app/pkg/helpers"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/dtos"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/domain/models"
+	"github.com/ECTM-IT/legal_assistant_chat_persistence/internal/shared/logs"
+)
+
+type ShareLinkConversionService interface {
+	ShareLinkToDTO(link models.ShareLink, now time.Time) dtos.ShareLinkResponse
+	ShareLinksToDTO(links []models.ShareLink, now time.Time) []dtos.ShareLinkResponse
+}
+
+type ShareLinkConversionServiceImpl struct {
+	logger logs.Logger
+}
+
+func NewShareLinkConversionService(logger logs.Logger) *ShareLinkConversionServiceImpl {
+	return &ShareLinkConversionServiceImpl{
+		logger: logger,
+	}
+}
+
+// ShareLinkToDTO converts a share link, reporting whether it is still active at
+// the given time. The token is never part of the conversion.
+func (s *ShareLinkConversionServiceImpl) ShareLinkToDTO(link models.ShareLink, now time.Time) dtos.ShareLinkResponse {
+	return dtos.ShareLinkResponse{
+		ID:                helpers.NewNullable(link.ID),
+		CaseID:            helpers.NewNullable(link.CaseID),
+		Scope:             helpers.NewNullable(link.Scope),
+		PasswordProtected: helpers.Nullable[bool]{Value: link.PasswordHash != "", Present: true},
+		Active:            helpers.Nullable[bool]{Value: link.Active(now), Present: true},
+		CreatedBy:         helpers.NewNullable(link.CreatedBy),
+		CreatedAt:         helpers.NewNullable(link.CreatedAt),
+		ExpiresAt:         helpers.NewNullable(link.ExpiresAt),
+		RevokedAt:         helpers.NewNullable(link.RevokedAt),
+		RevokedBy:         helpers.NewNullable(link.RevokedBy),
+		AccessCount:       helpers.Nullable[int64]{Value: link.AccessCount, Present: true},
+		LastAccessedAt:    helpers.NewNullable(link.LastAccessedAt),
+	}
+}
+
+func (s *ShareLinkConversionServiceImpl) ShareLinksToDTO(links []models.ShareLink, now time.Time) []dtos.ShareLinkResponse {
+	s.logger.Info("Converting multiple ShareLinks to DTOs")
+	linkDTOs := make([]dtos.ShareLinkResponse, len(links))
+	for i, link := range links {
+		linkDTOs[i] = s.ShareLinkToDTO(link, now)
+	}
+	return linkDTOs
+}
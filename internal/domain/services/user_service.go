@@ -22,19 +22,28 @@ type UserService interface {
 	UpdateUser(ctx context.Context, userID primitive.ObjectID, user *dtos.UpdateUserRequest) (*dtos.UserResponse, error)
 }
 
+// InvitationResolver turns the pending case invitations addressed to an email
+// into collaborator entries once a user with that email exists.
+type InvitationResolver interface {
+	ResolveInvitations(ctx context.Context, userID primitive.ObjectID, email string) (int, error)
+}
+
 // UserServiceImpl implements the UserService interface.
 type UserServiceImpl struct {
-	userRepo *repositories.UserRepositoryImpl
-	mapper   *mappers.UserConversionServiceImpl
-	logger   logs.Logger
+	userRepo    *repositories.UserRepositoryImpl
+	mapper      *mappers.UserConversionServiceImpl
+	invitations InvitationResolver
+	logger      logs.Logger
 }
 
-// NewUserService creates a new instance of the user service.
-func NewUserService(repo *repositories.UserRepositoryImpl, mapper *mappers.UserConversionServiceImpl, logger logs.Logger) *UserServiceImpl {
+// NewUserService creates a new instance of the user service. The invitation
+// resolver is optional.
+func NewUserService(repo *repositories.UserRepositoryImpl, mapper *mappers.UserConversionServiceImpl, invitations InvitationResolver, logger logs.Logger) *UserServiceImpl {
 	return &UserServiceImpl{
-		userRepo: repo,
-		mapper:   mapper,
-		logger:   logger,
+		userRepo:    repo,
+		mapper:      mapper,
+		invitations: invitations,
+		logger:      logger,
 	}
 }
 
@@ -101,6 +110,14 @@ func (s *UserServiceImpl) CreateUser(ctx context.Context, userDTO *dtos.CreateUs
 		return nil, errors.NewDatabaseError("Service Level: Failed to create user", "create_user_failed")
 	}
 
+	// The user exists either way; invitations left unresolved can still be
+	// accepted through their token.
+	if s.invitations != nil && createdUser.Email != "" {
+		if _, err := s.invitations.ResolveInvitations(ctx, createdUser.ID, createdUser.Email); err != nil {
+			s.logger.Error("Service Level: Failed to resolve case invitations of new user", err)
+		}
+	}
+
 	s.logger.Info("Service Level: Successfully created new user")
 	return s.mapper.UserToDTO(createdUser), nil
 }
@@ -12,6 +12,7 @@ type ErrorType struct {
 var (
 	ErrorTypeUnknown        = ErrorType{"unknown", http.StatusInternalServerError}
 	ErrorTypeAuthorization  = ErrorType{"authorization", http.StatusUnauthorized}
+	ErrorTypeForbidden      = ErrorType{"forbidden", http.StatusForbidden}
 	ErrorTypeIncorrectInput = ErrorType{"incorrect-input", http.StatusBadRequest}
 	ErrorTypeNotFound       = ErrorType{"not-found", http.StatusNotFound}
 	ErrorTypeDatabase       = ErrorType{"database", http.StatusInternalServerError}
@@ -61,6 +62,10 @@ func NewAuthorizationError(message, slug string) SlugError {
 	return NewSlugError(message, slug, ErrorTypeAuthorization)
 }
 
+func NewForbiddenError(message, slug string) SlugError {
+	return NewSlugError(message, slug, ErrorTypeForbidden)
+}
+
 func NewNotFoundError(message, slug string) SlugError {
 	return NewSlugError(message, slug, ErrorTypeNotFound)
 }